@@ -202,12 +202,23 @@ type SocLimiter interface {
 	TargetSoC() (float64, error)
 }
 
+// SocLimitSetter sets the vehicles charge limit
+type SocLimitSetter interface {
+	SetTargetSoC(soc int) error
+}
+
 // VehicleChargeController allows to start/stop the charging session on the vehicle side
 type VehicleChargeController interface {
 	StartCharge() error
 	StopCharge() error
 }
 
+// VehicleClimateController allows to start/stop climatisation on the vehicle side
+type VehicleClimateController interface {
+	StartClimate() error
+	StopClimate() error
+}
+
 // Resurrector provides wakeup calls to the vehicle with an API call or a CP interrupt from the charger
 type Resurrector interface {
 	WakeUp() error
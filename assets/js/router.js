@@ -2,6 +2,7 @@ import { createRouter, createWebHashHistory } from "vue-router";
 
 import Main from "./views/Main.vue";
 import ChargingSessions from "./views/ChargingSessions.vue";
+import Setup from "./views/Setup.vue";
 import { ensureCurrentLocaleMessages } from "./i18n";
 
 export default function setupRouter(i18n) {
@@ -10,6 +11,7 @@ export default function setupRouter(i18n) {
     routes: [
       { path: "/", component: Main, props: true },
       { path: "/sessions", component: ChargingSessions, props: true },
+      { path: "/setup", component: Setup, props: true },
     ],
   });
   router.beforeEach(async () => {
@@ -20,6 +20,7 @@ function setProperty(obj, props, value) {
 
 const state = reactive({
   loadpoints: [], // ensure array type
+  consumers: [], // ensure array type
 });
 
 const store = {
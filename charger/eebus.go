@@ -139,10 +139,14 @@ func (c *EEBus) setLoadpointMinMaxLimits(data *communication.EVSEClientDataType)
 	newMax := data.EVData.Limits[1].Max
 
 	if c.lp.GetMinCurrent() != newMin && newMin > 0 {
-		c.lp.SetMinCurrent(newMin)
+		if err := c.lp.SetMinCurrent(newMin); err != nil {
+			c.log.ERROR.Printf("set min current: %v", err)
+		}
 	}
 	if c.lp.GetMaxCurrent() != newMax && newMax > 0 {
-		c.lp.SetMaxCurrent(newMax)
+		if err := c.lp.SetMaxCurrent(newMax); err != nil {
+			c.log.ERROR.Printf("set max current: %v", err)
+		}
 	}
 }
 
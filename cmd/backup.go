@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	coredb "github.com/evcc-io/evcc/core/db"
+	"github.com/evcc-io/evcc/server"
+	"github.com/evcc-io/evcc/server/db/backup"
+	"github.com/evcc-io/evcc/server/db/settings"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// backupCmd represents the backup command
+var backupCmd = &cobra.Command{
+	Use:   "backup [file]",
+	Short: "Backup configuration file and database",
+	Args:  cobra.MaximumNArgs(1),
+	Run:   runBackup,
+}
+
+// restoreCmd represents the restore command
+var restoreCmd = &cobra.Command{
+	Use:   "restore <file>",
+	Short: "Restore database and configuration file from backup",
+	Long: `Restore replaces the database contents with the backup.
+The configuration file is only restored if it does not exist yet.`,
+	Args: cobra.ExactArgs(1),
+	Run:  runRestore,
+}
+
+func init() {
+	rootCmd.AddCommand(backupCmd)
+	rootCmd.AddCommand(restoreCmd)
+}
+
+// openDatabase opens the configured database, the sqlite flag takes precedence
+func openDatabase(cmd *cobra.Command, conf dbConfig) error {
+	if flag := cmd.Flags().Lookup(flagSqlite); flag.Changed {
+		conf.Type = "sqlite"
+		conf.Dsn = flag.Value.String()
+	}
+
+	if conf.Dsn == "" {
+		return errors.New("database not configured")
+	}
+
+	if err := configureDatabase(conf); err != nil {
+		return err
+	}
+
+	return coredb.Migrate()
+}
+
+func runBackup(cmd *cobra.Command, args []string) {
+	if err := loadConfigFile(&conf); err != nil && !errors.As(err, &viper.ConfigFileNotFoundError{}) {
+		log.FATAL.Fatal(err)
+	}
+
+	if err := openDatabase(cmd, conf.Database); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	var config []byte
+	if cfgFile != "" {
+		var err error
+		if config, err = os.ReadFile(cfgFile); err != nil {
+			log.FATAL.Fatal(err)
+		}
+	}
+
+	file := fmt.Sprintf("evcc-backup-%s.zip", time.Now().Format("20060102"))
+	if len(args) == 1 {
+		file = args[0]
+	}
+
+	f, err := os.Create(file)
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	err = backup.Create(f, server.Version, config)
+	if err == nil {
+		err = f.Close()
+	}
+	if err != nil {
+		_ = os.Remove(file)
+		log.FATAL.Fatal(err)
+	}
+
+	fmt.Println("backup written to", file)
+}
+
+func runRestore(cmd *cobra.Command, args []string) {
+	f, err := os.Open(args[0])
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	archive, err := backup.Open(f, fi.Size())
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	if archive.Evcc != server.Version {
+		log.WARN.Printf("backup created by evcc %s, running %s", archive.Evcc, server.Version)
+	}
+
+	// restore config file unless existing
+	if len(archive.ConfigFile) > 0 {
+		target := cfgFile
+		if target == "" {
+			target = "evcc.yaml"
+		}
+
+		if _, err := os.Stat(target); errors.Is(err, os.ErrNotExist) {
+			if err := os.WriteFile(target, archive.ConfigFile, 0o644); err != nil {
+				log.FATAL.Fatal(err)
+			}
+			viper.SetConfigFile(target)
+			log.INFO.Println("restored config file:", target)
+		} else {
+			log.WARN.Println("config file exists, not restored:", target)
+		}
+	}
+
+	if err := loadConfigFile(&conf); err != nil && !errors.As(err, &viper.ConfigFileNotFoundError{}) {
+		log.FATAL.Fatal(err)
+	}
+
+	if err := openDatabase(cmd, conf.Database); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	if err := archive.Restore(); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	// don't overwrite restored settings on shutdown
+	if err := settings.Init(); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	fmt.Printf("restored %d tables from backup created %s\n", len(archive.Tables), archive.Created.Local().Format(time.RFC1123))
+}
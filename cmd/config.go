@@ -14,11 +14,13 @@ import (
 	"github.com/dustin/go-humanize"
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/charger"
+	"github.com/evcc-io/evcc/core"
 	"github.com/evcc-io/evcc/meter"
 	"github.com/evcc-io/evcc/provider/mqtt"
 	"github.com/evcc-io/evcc/push"
 	"github.com/evcc-io/evcc/server"
 	autoauth "github.com/evcc-io/evcc/server/auth"
+	"github.com/evcc-io/evcc/server/db"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/modbus"
 	"github.com/evcc-io/evcc/vehicle"
@@ -50,6 +52,7 @@ type config struct {
 	URI          interface{} // TODO deprecated
 	Network      networkConfig
 	Log          string
+	Include      []string // additional config files
 	SponsorToken string
 	Plant        string // telemetry plant id
 	Telemetry    bool
@@ -58,8 +61,12 @@ type config struct {
 	Levels       map[string]string
 	Interval     time.Duration
 	Database     dbConfig
+	Backup       backupConfig
+	Templates    string
 	Mqtt         mqttConfig
 	ModbusProxy  []proxyConfig
+	SunSpec      sunspecConfig
+	Discovery    discoveryConfig
 	Javascript   []javascriptConfig
 	Influx       server.InfluxConfig
 	EEBus        map[string]interface{}
@@ -71,6 +78,9 @@ type config struct {
 	Tariffs      tariffConfig
 	Site         map[string]interface{}
 	LoadPoints   []map[string]interface{}
+	Consumers    []map[string]interface{}
+	HeatPumps    []map[string]interface{}
+	Remotes      []map[string]interface{}
 }
 
 type mqttConfig struct {
@@ -89,9 +99,24 @@ type proxyConfig struct {
 	modbus.Settings `mapstructure:",squash"`
 }
 
+type sunspecConfig struct {
+	Port int
+	ID   uint8
+}
+
+type discoveryConfig struct {
+	Interval time.Duration
+}
+
+type backupConfig struct {
+	Token string
+}
+
 type dbConfig struct {
-	Type string
-	Dsn  string
+	Type    string
+	Dsn     string
+	Prefix  string
+	db.Pool `mapstructure:",squash"`
 }
 
 type qualifiedConfig struct {
@@ -134,11 +159,18 @@ func (c networkConfig) URI() string {
 
 // ConfigProvider provides configuration items
 type ConfigProvider struct {
+	mu       sync.Mutex
 	meters   map[string]api.Meter
 	chargers map[string]api.Charger
 	vehicles map[string]api.Vehicle
 	visited  map[string]bool
 	auth     *util.AuthCollection
+
+	// runtime configuration
+	reloadMu   sync.Mutex
+	static     map[string][]string     // devices configured via yaml by class
+	loadpoints map[int]*core.LoadPoint // running loadpoints by runtime configuration id
+	site       *core.Site
 }
 
 func (cp *ConfigProvider) TrackVisitors() {
@@ -147,6 +179,9 @@ func (cp *ConfigProvider) TrackVisitors() {
 
 // Meter provides meters by name
 func (cp *ConfigProvider) Meter(name string) (api.Meter, error) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
 	if meter, ok := cp.meters[name]; ok {
 		// track duplicate usage https://github.com/evcc-io/evcc/issues/1744
 		if cp.visited != nil {
@@ -163,6 +198,9 @@ func (cp *ConfigProvider) Meter(name string) (api.Meter, error) {
 
 // Charger provides chargers by name
 func (cp *ConfigProvider) Charger(name string) (api.Charger, error) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
 	if charger, ok := cp.chargers[name]; ok {
 		return charger, nil
 	}
@@ -171,6 +209,9 @@ func (cp *ConfigProvider) Charger(name string) (api.Charger, error) {
 
 // Vehicle provides vehicles by name
 func (cp *ConfigProvider) Vehicle(name string) (api.Vehicle, error) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
 	if vehicle, ok := cp.vehicles[name]; ok {
 		return vehicle, nil
 	}
@@ -178,7 +219,10 @@ func (cp *ConfigProvider) Vehicle(name string) (api.Vehicle, error) {
 }
 
 func (cp *ConfigProvider) configure(conf config) error {
-	err := cp.configureMeters(conf)
+	conf, err := cp.withRuntimeDevices(conf)
+	if err == nil {
+		err = cp.configureMeters(conf)
+	}
 	if err == nil {
 		err = cp.configureChargers(conf)
 	}
@@ -257,26 +301,9 @@ func (cp *ConfigProvider) configureVehicles(conf config) error {
 		cc := cc
 
 		g.Go(func() error {
-			// ensure vehicle config has title
-			var ccWithTitle struct {
-				Title string
-				Other map[string]interface{} `mapstructure:",remain"`
-			}
-
-			if err := util.DecodeOther(cc.Other, &ccWithTitle); err != nil {
-				return err
-			}
-
-			if ccWithTitle.Title == "" {
-				//lint:ignore SA1019 as Title is safe on ascii
-				cc.Other["title"] = strings.Title(cc.Name)
-			}
-
-			v, err := vehicle.NewFromConfig(cc.Type, cc.Other)
+			v, err := newVehicle(cc)
 			if err != nil {
-				log.ERROR.Printf("creating vehicle %s failed: %v", cc.Name, err)
-				// wrap any created errors to prevent fatals
-				v, _ = wrapper.New(v, err)
+				return err
 			}
 
 			mu.Lock()
@@ -294,6 +321,38 @@ func (cp *ConfigProvider) configureVehicles(conf config) error {
 	return g.Wait()
 }
 
+// newVehicle creates a vehicle, wrapping creation errors to prevent fatals
+func newVehicle(cc qualifiedConfig) (api.Vehicle, error) {
+	v, err := createVehicle(cc)
+	if err != nil {
+		log.ERROR.Printf("creating vehicle %s failed: %v", cc.Name, err)
+		// wrap any created errors to prevent fatals
+		v, _ = wrapper.New(v, err)
+	}
+
+	return v, nil
+}
+
+// createVehicle creates a vehicle with title defaulting to its name
+func createVehicle(cc qualifiedConfig) (api.Vehicle, error) {
+	// ensure vehicle config has title
+	var ccWithTitle struct {
+		Title string
+		Other map[string]interface{} `mapstructure:",remain"`
+	}
+
+	if err := util.DecodeOther(cc.Other, &ccWithTitle); err != nil {
+		return nil, err
+	}
+
+	if ccWithTitle.Title == "" {
+		//lint:ignore SA1019 as Title is safe on ascii
+		cc.Other["title"] = strings.Title(cc.Name)
+	}
+
+	return vehicle.NewFromConfig(cc.Type, cc.Other)
+}
+
 // webControl handles routing for devices. For now only api.AuthProvider related routes
 func (cp *ConfigProvider) webControl(conf networkConfig, router *mux.Router, paramC chan<- util.Param) {
 	auth := router.PathPrefix("/oauth").Subrouter()
@@ -0,0 +1,444 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/evcc-io/evcc/charger"
+	"github.com/evcc-io/evcc/core"
+	"github.com/evcc-io/evcc/meter"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/templates"
+	"github.com/evcc-io/evcc/vehicle"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+)
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Configuration tools",
+}
+
+// configCheckCmd represents the config check command
+var configCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Validate configuration file without accessing devices",
+	Run:   runConfigCheck,
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configCheckCmd)
+}
+
+const nullTag = "!!null"
+
+// configIssue is a configuration problem found at a line of a configuration file
+type configIssue struct {
+	File string
+	Line int
+	Msg  string
+}
+
+func (i configIssue) String() string {
+	var prefix string
+	if i.File != "" {
+		prefix = i.File + ": "
+	}
+	if i.Line == 0 {
+		return prefix + i.Msg
+	}
+	return fmt.Sprintf("%sline %d: %s", prefix, i.Line, i.Msg)
+}
+
+// configSource is a configuration file merged into the effective configuration
+type configSource struct {
+	File string
+	Data []byte
+}
+
+// configChecker validates the configuration files' structure and device references
+type configChecker struct {
+	file   string // file being checked
+	issues []configIssue
+	names  map[templates.Class][]string
+}
+
+func runConfigCheck(cmd *cobra.Command, args []string) {
+	if err := viper.ReadInConfig(); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	file := viper.ConfigFileUsed()
+	log.INFO.Println("checking config file:", file)
+
+	// included files must be merged for resolving templates directory and device references
+	if err := mergeIncludes(file); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	var sources []configSource
+	for _, f := range configFiles {
+		if f != file {
+			log.INFO.Println("checking included file:", f)
+		}
+
+		b, err := os.ReadFile(f)
+		if err != nil {
+			log.FATAL.Fatal(err)
+		}
+
+		sources = append(sources, configSource{File: f, Data: b})
+	}
+
+	// user templates must be loaded for validating template references
+	var issues []configIssue
+	if dir := viper.GetString("templates"); dir != "" {
+		conflicts, err := templates.LoadDirectory(dir)
+		if err != nil {
+			issues = append(issues, configIssue{File: dir, Msg: err.Error()})
+		}
+
+		for _, c := range conflicts {
+			fmt.Printf("%s: warning: %s\n", dir, c)
+		}
+	}
+
+	issues = append(issues, checkConfig(sources...)...)
+
+	// type errors are only reported by the decoder
+	if len(issues) == 0 {
+		issues = decodeConfig(sources...)
+	}
+
+	for _, issue := range issues {
+		fmt.Println(issue)
+	}
+
+	if len(issues) > 0 {
+		fmt.Printf("\n%d problem(s) found\n", len(issues))
+		os.Exit(1)
+	}
+
+	fmt.Println("config ok")
+}
+
+// checkConfig validates the yaml configuration files and returns all issues found.
+// Device references are resolved across all files, issues are ordered by file and line.
+func checkConfig(sources ...configSource) []configIssue {
+	c := &configChecker{
+		names: make(map[templates.Class][]string),
+	}
+
+	roots := make([]*yaml.Node, len(sources))
+
+	// devices of all files first to allow checking references
+	for i, src := range sources {
+		c.file = src.File
+
+		var doc yaml.Node
+		if err := yaml.Unmarshal(src.Data, &doc); err != nil {
+			c.issues = append(c.issues, configIssue{File: c.file, Msg: err.Error()})
+			continue
+		}
+
+		if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+			// included files may be empty
+			if i == 0 {
+				c.issues = append(c.issues, configIssue{File: c.file, Msg: "empty configuration"})
+			}
+			continue
+		}
+
+		root := doc.Content[0]
+		roots[i] = root
+
+		c.checkKeys("", root, structKeys(config{}))
+
+		for _, class := range []templates.Class{templates.Meter, templates.Charger, templates.Vehicle} {
+			if node := mappingValue(root, string(class)+"s"); node != nil && node.Tag != nullTag {
+				c.checkDevices(class, node)
+			}
+		}
+	}
+
+	for i, root := range roots {
+		if root == nil {
+			continue
+		}
+
+		c.file = sources[i].File
+
+		if node := mappingValue(root, "site"); node != nil && node.Kind == yaml.MappingNode {
+			c.checkSite(node)
+		}
+
+		if node := mappingValue(root, "loadpoints"); node != nil && node.Tag != nullTag {
+			c.checkLoadpoints(node)
+		}
+	}
+
+	order := make(map[string]int, len(sources))
+	for i, src := range sources {
+		order[src.File] = i
+	}
+
+	sort.SliceStable(c.issues, func(i, j int) bool {
+		a, b := c.issues[i], c.issues[j]
+		if a.File != b.File {
+			return order[a.File] < order[b.File]
+		}
+		return a.Line < b.Line
+	})
+
+	return c.issues
+}
+
+// decodeConfig decodes each configuration file like the effective configuration and returns the type errors found
+func decodeConfig(sources ...configSource) []configIssue {
+	var res []configIssue
+
+	for _, src := range sources {
+		v := viper.New()
+		v.SetConfigType("yaml")
+
+		err := v.ReadConfig(bytes.NewReader(src.Data))
+		if err == nil {
+			var conf config
+			err = v.UnmarshalExact(&conf, viper.DecodeHook(secretDecodeHook))
+		}
+
+		if err != nil {
+			res = append(res, configIssue{File: src.File, Msg: err.Error()})
+		}
+	}
+
+	return res
+}
+
+func (c *configChecker) add(node *yaml.Node, format string, a ...any) {
+	c.issues = append(c.issues, configIssue{File: c.file, Line: node.Line, Msg: fmt.Sprintf(format, a...)})
+}
+
+// unknown reports an unknown value with suggestion
+func (c *configChecker) unknown(node *yaml.Node, what, val string, candidates []string) {
+	c.unknownWithPrefix(node, what, "", val, candidates)
+}
+
+func (c *configChecker) unknownWithPrefix(node *yaml.Node, what, prefix, val string, candidates []string) {
+	if s := util.Suggest(val, candidates); s != "" {
+		c.add(node, "unknown %s '%s%s', did you mean '%s'?", what, prefix, val, s)
+	} else {
+		c.add(node, "unknown %s '%s%s'", what, prefix, val)
+	}
+}
+
+// checkKeys reports mapping keys not contained in the valid keys
+func (c *configChecker) checkKeys(prefix string, node *yaml.Node, valid []string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+		if !slices.Contains(valid, strings.ToLower(key.Value)) {
+			c.unknownWithPrefix(key, "key", prefix, key.Value, valid)
+		}
+	}
+}
+
+// checkDevices validates device types, names and template parameters
+func (c *configChecker) checkDevices(class templates.Class, node *yaml.Node) {
+	if node.Kind != yaml.SequenceNode {
+		c.add(node, "%ss must be a list", class)
+		return
+	}
+
+	var types []string
+	switch class {
+	case templates.Meter:
+		types = meter.Types()
+	case templates.Charger:
+		types = charger.Types()
+	case templates.Vehicle:
+		types = vehicle.Types()
+	}
+
+	// included files replace devices of the same name, duplicates are only reported within a file
+	var names []string
+
+	for _, dev := range node.Content {
+		var other map[string]interface{}
+		if err := dev.Decode(&other); err != nil {
+			c.add(dev, "%s: %v", class, err)
+			continue
+		}
+
+		name, _ := other["name"].(string)
+		typ, _ := other["type"].(string)
+
+		switch {
+		case name == "":
+			c.add(dev, "%s: missing name", class)
+		case slices.Contains(names, name):
+			c.add(mappingKey(dev, "name"), "%s: duplicate name '%s'", class, name)
+		default:
+			names = append(names, name)
+			if !slices.Contains(c.names[class], name) {
+				c.names[class] = append(c.names[class], name)
+			}
+		}
+
+		if typ == "" {
+			c.add(dev, "%s %s: missing type", class, name)
+			continue
+		}
+
+		if !slices.Contains(types, strings.ToLower(typ)) {
+			c.unknown(mappingKey(dev, "type"), string(class)+" type", typ, types)
+			continue
+		}
+
+		if strings.ToLower(typ) == "template" {
+			c.checkTemplate(class, name, dev, other)
+		}
+	}
+}
+
+// checkTemplate validates the template reference and parameters
+func (c *configChecker) checkTemplate(class templates.Class, name string, dev *yaml.Node, other map[string]interface{}) {
+	ref, _ := other["template"].(string)
+	if ref == "" {
+		c.add(dev, "%s %s: missing template", class, name)
+		return
+	}
+
+	tmpl, err := templates.ByName(class, ref)
+	if err != nil {
+		var names []string
+		for _, t := range templates.ByClass(class) {
+			names = append(names, t.Template)
+			names = append(names, t.Covers...)
+		}
+
+		c.unknown(mappingKey(dev, "template"), string(class)+" template", ref, names)
+		return
+	}
+
+	for _, err := range tmpl.CheckValues(other) {
+		node := dev
+		if pe, ok := err.(*templates.ParamError); ok {
+			if key := mappingKey(dev, pe.Param); key != nil {
+				node = key
+			}
+		}
+
+		c.add(node, "%s %s: %v", class, name, err)
+	}
+}
+
+// checkRef reports references to undefined devices
+func (c *configChecker) checkRef(class templates.Class, node *yaml.Node) {
+	if node == nil {
+		return
+	}
+
+	refs := []*yaml.Node{node}
+	if node.Kind == yaml.SequenceNode {
+		refs = node.Content
+	}
+
+	for _, ref := range refs {
+		if ref.Value != "" && !slices.Contains(c.names[class], ref.Value) {
+			c.unknown(ref, string(class), ref.Value, c.names[class])
+		}
+	}
+}
+
+func (c *configChecker) checkSite(node *yaml.Node) {
+	c.checkKeys("site.", node, structKeys(core.Site{}))
+
+	if meters := mappingValue(node, "meters"); meters != nil {
+		c.checkKeys("site.meters.", meters, structKeys(core.MetersConfig{}))
+
+		for i := 0; i+1 < len(meters.Content); i += 2 {
+			c.checkRef(templates.Meter, meters.Content[i+1])
+		}
+	}
+}
+
+func (c *configChecker) checkLoadpoints(node *yaml.Node) {
+	if node.Kind != yaml.SequenceNode {
+		c.add(node, "loadpoints must be a list")
+		return
+	}
+
+	for _, lp := range node.Content {
+		c.checkKeys("loadpoint.", lp, structKeys(core.LoadPoint{}))
+
+		if mappingValue(lp, "charger") == nil {
+			c.add(lp, "loadpoint: missing charger")
+		}
+
+		c.checkRef(templates.Charger, mappingValue(lp, "charger"))
+		c.checkRef(templates.Meter, mappingValue(lp, "meter"))
+		c.checkRef(templates.Vehicle, mappingValue(lp, "vehicle"))
+	}
+}
+
+// mappingKey returns the key node of a mapping (case-insensitive)
+func mappingKey(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if strings.EqualFold(node.Content[i].Value, key) {
+			return node.Content[i]
+		}
+	}
+
+	return nil
+}
+
+// mappingValue returns the value node of a mapping (case-insensitive)
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if strings.EqualFold(node.Content[i].Value, key) {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+// structKeys returns the lower case configuration keys of a struct as decoded by mapstructure
+func structKeys(v any) []string {
+	var res []string
+
+	typ := reflect.TypeOf(v)
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+
+		name := f.Name
+		if tag, _, _ := strings.Cut(f.Tag.Get("mapstructure"), ","); tag != "" {
+			name = tag
+		}
+
+		if name != "-" {
+			res = append(res, strings.ToLower(name))
+		}
+	}
+
+	return res
+}
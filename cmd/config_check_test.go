@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigCheck(t *testing.T) {
+	conf := `
+intervall: 10s
+meters:
+- name: grid
+  type: template
+  template: shelly-1pm
+  usage: grid
+  hots: 192.0.2.2
+- name: pv
+  type: tempalte
+chargers:
+- name: wallbox
+  type: template
+  template: go-e-v3x
+vehicles:
+site:
+  titel: Home
+  meters:
+    grid: grid
+    pv: pvv
+loadpoints:
+- title: Garage
+  charger: walbox
+`
+
+	issues := checkConfig(configSource{Data: []byte(conf)})
+
+	var res []string
+	for _, issue := range issues {
+		res = append(res, issue.String())
+	}
+
+	assert.Equal(t, []string{
+		"line 2: unknown key 'intervall', did you mean 'interval'?",
+		"line 7: meter grid: shelly-1pm: usage: invalid value: grid, must be one of pv",
+		"line 8: meter grid: shelly-1pm: hots: unknown parameter, did you mean 'host'?",
+		"line 10: unknown meter type 'tempalte', did you mean 'template'?",
+		"line 14: unknown charger template 'go-e-v3x', did you mean 'go-e-v3'?",
+		"line 17: unknown key 'site.titel', did you mean 'title'?",
+		"line 20: unknown meter 'pvv', did you mean 'pv'?",
+		"line 23: unknown charger 'walbox', did you mean 'wallbox'?",
+	}, res)
+}
+
+func TestConfigCheckIncludes(t *testing.T) {
+	main := `
+include: chargers.yaml
+chargers:
+- name: wallbox
+  type: custom
+loadpoints:
+- title: Garage
+  charger: wallbox2
+- title: Carport
+  charger: wallbox3
+`
+
+	include := `
+chargers:
+- name: wallbox
+  type: custom
+- name: wallbox2
+  type: custom
+- name: wallbox2
+  type: custom
+loadpoint:
+`
+
+	issues := checkConfig(
+		configSource{File: "evcc.yaml", Data: []byte(main)},
+		configSource{File: "chargers.yaml", Data: []byte(include)},
+		configSource{File: "empty.yaml"},
+	)
+
+	var res []string
+	for _, issue := range issues {
+		res = append(res, issue.String())
+	}
+
+	assert.Equal(t, []string{
+		"evcc.yaml: line 10: unknown charger 'wallbox3', did you mean 'wallbox'?",
+		"chargers.yaml: line 7: charger: duplicate name 'wallbox2'",
+		"chargers.yaml: line 9: unknown key 'loadpoint', did you mean 'loadpoints'?",
+	}, res)
+}
+
+func TestConfigCheckDecode(t *testing.T) {
+	t.Setenv("EVCC_TEST_PORT", "7071")
+
+	issues := decodeConfig(
+		configSource{File: "evcc.yaml", Data: []byte("network:\n  port: ${env:EVCC_TEST_PORT}\n")},
+		configSource{File: "site.yaml", Data: []byte("interval: often\n")},
+	)
+
+	require.Len(t, issues, 1)
+	assert.Equal(t, "site.yaml", issues[0].File)
+}
@@ -0,0 +1,426 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/charger"
+	"github.com/evcc-io/evcc/core"
+	"github.com/evcc-io/evcc/meter"
+	"github.com/evcc-io/evcc/server"
+	"github.com/evcc-io/evcc/server/db"
+	dbconfig "github.com/evcc-io/evcc/server/db/config"
+	"github.com/evcc-io/evcc/util"
+	"github.com/samber/lo"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+var _ server.DeviceConfigurator = (*ConfigProvider)(nil)
+
+// withRuntimeDevices adds the devices stored in the database to the yaml configuration.
+// Yaml devices are remembered as static since they cannot be changed at runtime.
+func (cp *ConfigProvider) withRuntimeDevices(conf config) (config, error) {
+	cp.static = map[string][]string{
+		dbconfig.Meter: lo.Map(conf.Meters, func(cc qualifiedConfig, _ int) string {
+			return cc.Name
+		}),
+		dbconfig.Charger: lo.Map(conf.Chargers, func(cc qualifiedConfig, _ int) string {
+			return cc.Name
+		}),
+		dbconfig.Vehicle: lo.Map(conf.Vehicles, func(cc qualifiedConfig, _ int) string {
+			return cc.Name
+		}),
+	}
+
+	if db.Instance == nil {
+		return conf, nil
+	}
+
+	for _, class := range []string{dbconfig.Meter, dbconfig.Charger, dbconfig.Vehicle} {
+		confs, err := dbconfig.Configs(class)
+		if err != nil {
+			return conf, err
+		}
+
+		for _, c := range confs {
+			other, err := c.Other()
+			if err != nil {
+				return conf, fmt.Errorf("cannot decode %s '%s': %w", class, c.Name, err)
+			}
+
+			cc := qualifiedConfig{Name: c.Name, Type: c.Type, Other: other}
+
+			switch class {
+			case dbconfig.Meter:
+				conf.Meters = append(conf.Meters, cc)
+			case dbconfig.Charger:
+				conf.Chargers = append(conf.Chargers, cc)
+			case dbconfig.Vehicle:
+				conf.Vehicles = append(conf.Vehicles, cc)
+			}
+		}
+	}
+
+	return conf, nil
+}
+
+// runtimeLoadPoint is a loadpoint configuration stored in the database
+type runtimeLoadPoint struct {
+	id    int
+	other map[string]interface{}
+}
+
+// runtimeLoadPoints returns the loadpoint configurations stored in the database
+func runtimeLoadPoints() ([]runtimeLoadPoint, error) {
+	if db.Instance == nil {
+		return nil, nil
+	}
+
+	confs, err := dbconfig.Configs(dbconfig.LoadPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []runtimeLoadPoint
+	for _, c := range confs {
+		other, err := loadPointConfig(c)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode loadpoint '%s': %w", c.Name, err)
+		}
+
+		res = append(res, runtimeLoadPoint{id: c.ID, other: other})
+	}
+
+	return res, nil
+}
+
+// loadPointConfig returns the loadpoint configuration using the configuration's name as default title
+func loadPointConfig(conf dbconfig.Config) (map[string]interface{}, error) {
+	other, err := conf.Other()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := other["title"]; !ok {
+		other["title"] = conf.Name
+	}
+
+	return other, nil
+}
+
+// validateLoadPoint verifies that the loadpoint can be created from the runtime configuration
+func (cp *ConfigProvider) validateLoadPoint(conf dbconfig.Config) (map[string]interface{}, error) {
+	other, err := loadPointConfig(conf)
+	if err == nil {
+		_, err = core.NewLoadPointFromConfig(util.NewLogger("lp-"+conf.Name), cp, other)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot create loadpoint '%s': %w", conf.Name, err)
+	}
+
+	return other, nil
+}
+
+// StaticDevices returns the names of the devices configured via yaml
+func (cp *ConfigProvider) StaticDevices(class string) []string {
+	res := slices.Clone(cp.static[class])
+	sort.Strings(res)
+	return res
+}
+
+func (cp *ConfigProvider) isStatic(conf dbconfig.Config) bool {
+	return slices.Contains(cp.static[conf.Class], conf.Name)
+}
+
+// newDevice creates a device from runtime configuration
+func newDevice(conf dbconfig.Config) (any, error) {
+	other, err := conf.Other()
+	if err != nil {
+		return nil, err
+	}
+
+	switch conf.Class {
+	case dbconfig.Meter:
+		return meter.NewFromConfig(conf.Type, other)
+	case dbconfig.Charger:
+		return charger.NewFromConfig(conf.Type, other)
+	case dbconfig.Vehicle:
+		// creation errors are returned instead of wrapped to reject invalid configurations
+		return createVehicle(qualifiedConfig{Name: conf.Name, Type: conf.Type, Other: other})
+	default:
+		return nil, fmt.Errorf("invalid class: %s", conf.Class)
+	}
+}
+
+// setDevice adds, replaces or removes (if dev is nil) a device and returns the previous device
+func (cp *ConfigProvider) setDevice(class, name string, dev any) any {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	var old any
+
+	switch class {
+	case dbconfig.Meter:
+		if v, ok := cp.meters[name]; ok {
+			old = v
+		}
+		if dev == nil {
+			delete(cp.meters, name)
+		} else {
+			cp.meters[name] = dev.(api.Meter)
+		}
+
+	case dbconfig.Charger:
+		if v, ok := cp.chargers[name]; ok {
+			old = v
+		}
+		if dev == nil {
+			delete(cp.chargers, name)
+		} else {
+			cp.chargers[name] = dev.(api.Charger)
+		}
+
+	case dbconfig.Vehicle:
+		if v, ok := cp.vehicles[name]; ok {
+			old = v
+		}
+		if dev == nil {
+			delete(cp.vehicles, name)
+		} else {
+			cp.vehicles[name] = dev.(api.Vehicle)
+		}
+	}
+
+	return old
+}
+
+// hasDevice checks if a device with the given name exists
+func (cp *ConfigProvider) hasDevice(class, name string) bool {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	switch class {
+	case dbconfig.Meter:
+		_, ok := cp.meters[name]
+		return ok
+	case dbconfig.Charger:
+		_, ok := cp.chargers[name]
+		return ok
+	case dbconfig.Vehicle:
+		_, ok := cp.vehicles[name]
+		return ok
+	}
+
+	return false
+}
+
+// runtimeLoadPoint returns the running loadpoint of the runtime configuration
+func (cp *ConfigProvider) runtimeLoadPoint(id int) (*core.LoadPoint, bool) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	lp, ok := cp.loadpoints[id]
+	return lp, ok
+}
+
+// vehicleList returns the configured vehicles
+func (cp *ConfigProvider) vehicleList() []api.Vehicle {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	keys := maps.Keys(cp.vehicles)
+	sort.Strings(keys)
+
+	return lo.Map(keys, func(k string, _ int) api.Vehicle {
+		return cp.vehicles[k]
+	})
+}
+
+// closeDevice releases the device's resources if supported
+func closeDevice(dev any) {
+	if c, ok := dev.(io.Closer); ok {
+		if err := c.Close(); err != nil {
+			log.ERROR.Printf("closing device: %v", err)
+		}
+	}
+}
+
+// apply replaces the device and reconfigures the running site. The previous device is restored on error.
+func (cp *ConfigProvider) apply(conf dbconfig.Config, dev any) (any, error) {
+	old := cp.setDevice(conf.Class, conf.Name, dev)
+
+	if cp.site == nil {
+		return old, nil
+	}
+
+	// meter references are already validated
+	cp.mu.Lock()
+	cp.visited = nil
+	cp.mu.Unlock()
+
+	err := cp.site.Reconfigure(cp, cp.vehicleList())
+	if err != nil {
+		cp.setDevice(conf.Class, conf.Name, old)
+
+		// restore previous state
+		if err := cp.site.Reconfigure(cp, cp.vehicleList()); err != nil {
+			log.ERROR.Printf("restoring %s '%s': %v", conf.Class, conf.Name, err)
+		}
+	}
+
+	return old, err
+}
+
+// AddDevice persists a runtime configuration and adds the device to the running site.
+// Loadpoints are validated and take effect after restart.
+func (cp *ConfigProvider) AddDevice(conf dbconfig.Config) (dbconfig.Config, error) {
+	cp.reloadMu.Lock()
+	defer cp.reloadMu.Unlock()
+
+	other, err := conf.Other()
+	if err != nil {
+		return conf, err
+	}
+
+	if conf.Class == dbconfig.LoadPoint {
+		if _, err := cp.validateLoadPoint(conf); err != nil {
+			return conf, err
+		}
+
+		return dbconfig.AddConfig(conf.Class, conf.Name, conf.Type, other)
+	}
+
+	if cp.hasDevice(conf.Class, conf.Name) {
+		return conf, fmt.Errorf("duplicate %s name: %s already defined and must be unique", conf.Class, conf.Name)
+	}
+
+	dev, err := newDevice(conf)
+	if err != nil {
+		return conf, fmt.Errorf("cannot create %s '%s': %w", conf.Class, conf.Name, err)
+	}
+
+	stored, err := dbconfig.AddConfig(conf.Class, conf.Name, conf.Type, other)
+	if err != nil {
+		closeDevice(dev)
+		return conf, err
+	}
+
+	if _, err := cp.apply(conf, dev); err != nil {
+		closeDevice(dev)
+
+		if err := dbconfig.DeleteConfig(stored.ID); err != nil {
+			log.ERROR.Printf("restoring %s '%s': %v", conf.Class, conf.Name, err)
+		}
+
+		return conf, err
+	}
+
+	return stored, nil
+}
+
+// UpdateDevice persists a changed runtime configuration and replaces the device in the running site
+func (cp *ConfigProvider) UpdateDevice(conf dbconfig.Config) (dbconfig.Config, error) {
+	cp.reloadMu.Lock()
+	defer cp.reloadMu.Unlock()
+
+	if cp.isStatic(conf) {
+		return conf, fmt.Errorf("cannot change %s '%s': defined in yaml configuration", conf.Class, conf.Name)
+	}
+
+	previous, err := dbconfig.ConfigByID(conf.ID)
+	if err != nil {
+		return conf, err
+	}
+
+	other, err := conf.Other()
+	if err != nil {
+		return conf, err
+	}
+
+	// restore persisted configuration if the running site cannot be changed
+	restore := func() {
+		if err := dbconfig.SaveConfig(previous); err != nil {
+			log.ERROR.Printf("restoring %s '%s': %v", conf.Class, conf.Name, err)
+		}
+	}
+
+	if conf.Class == dbconfig.LoadPoint {
+		lpc, err := cp.validateLoadPoint(conf)
+		if err != nil {
+			return conf, err
+		}
+
+		stored, err := dbconfig.UpdateConfig(conf.ID, conf.Type, other)
+		if err != nil {
+			return conf, err
+		}
+
+		// loadpoints added after startup are not running yet
+		if lp, ok := cp.runtimeLoadPoint(conf.ID); ok && cp.site != nil {
+			if err := cp.site.ReconfigureLoadPoint(lp, cp, cp.vehicleList(), lpc); err != nil {
+				restore()
+				return conf, fmt.Errorf("cannot change loadpoint '%s': %w", conf.Name, err)
+			}
+		}
+
+		return stored, nil
+	}
+
+	dev, err := newDevice(conf)
+	if err != nil {
+		return conf, fmt.Errorf("cannot create %s '%s': %w", conf.Class, conf.Name, err)
+	}
+
+	stored, err := dbconfig.UpdateConfig(conf.ID, conf.Type, other)
+	if err != nil {
+		closeDevice(dev)
+		return conf, err
+	}
+
+	old, err := cp.apply(conf, dev)
+	if err != nil {
+		closeDevice(dev)
+		restore()
+		return conf, err
+	}
+
+	closeDevice(old)
+
+	return stored, nil
+}
+
+// DeleteDevice removes a runtime configuration and the device from the running site. Devices still in use cannot be removed.
+// Loadpoints are removed after restart.
+func (cp *ConfigProvider) DeleteDevice(conf dbconfig.Config) error {
+	cp.reloadMu.Lock()
+	defer cp.reloadMu.Unlock()
+
+	if cp.isStatic(conf) {
+		return fmt.Errorf("cannot delete %s '%s': defined in yaml configuration", conf.Class, conf.Name)
+	}
+
+	if err := dbconfig.DeleteConfig(conf.ID); err != nil {
+		return err
+	}
+
+	if conf.Class == dbconfig.LoadPoint {
+		return nil
+	}
+
+	old, err := cp.apply(conf, nil)
+	if err != nil {
+		if err := dbconfig.SaveConfig(conf); err != nil {
+			log.ERROR.Printf("restoring %s '%s': %v", conf.Class, conf.Name, err)
+		}
+
+		return fmt.Errorf("cannot delete %s '%s': %w", conf.Class, conf.Name, err)
+	}
+
+	closeDevice(old)
+
+	return nil
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/evcc-io/evcc/core"
 	"github.com/evcc-io/evcc/server"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 // dumpCmd represents the meter command
@@ -31,7 +32,7 @@ var (
 func init() {
 	rootCmd.AddCommand(dumpCmd)
 
-	dumpConfig = dumpCmd.Flags().Bool("cfg", false, "Dump config file")
+	dumpConfig = dumpCmd.Flags().Bool("cfg", false, "Dump config file, merged with included files")
 }
 
 func handle(device any, err error) any {
@@ -66,6 +67,13 @@ func runDump(cmd *cobra.Command, args []string) {
 			redacted = redact(string(src))
 		}
 
+		// show effective config if files have been merged
+		if mergedConfig != nil {
+			if src, err := yaml.Marshal(mergedConfig); err == nil {
+				redacted = redact(string(src))
+			}
+		}
+
 		tmpl := template.Must(
 			template.New("dump").
 				Funcs(template.FuncMap(sprig.FuncMap())).
@@ -76,6 +84,7 @@ func runDump(cmd *cobra.Command, args []string) {
 			"CfgFile":    file,
 			"CfgError":   errorString(err),
 			"CfgContent": redacted,
+			"CfgFiles":   includedFiles(),
 			"Version":    server.FormattedVersion(),
 		})
 
@@ -13,6 +13,13 @@ Konfiguration{{ if .CfgFile }} ({{ .CfgFile }}){{ end }}:
 
 {{ end -}}
 
+{{ if .CfgFiles -}}
+Eingebundene Dateien:
+
+{{ range .CfgFiles }}- {{ . }}
+{{ end }}
+{{ end -}}
+
 {{ if .Version -}}
 Version: `{{ .Version }}`
 {{ end -}}
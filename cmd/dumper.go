@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util/request"
 	"github.com/fatih/structs"
 )
 
@@ -169,6 +170,15 @@ func (d *dumper) Dump(name string, v interface{}) {
 		}
 	}
 
+	if v, ok := v.(request.Budgeter); ok && v.Budget() != nil {
+		used, limit := v.Budget().Used()
+		if limit > 0 {
+			fmt.Fprintf(w, "API requests:\t%d/%d today\n", used, limit)
+		} else {
+			fmt.Fprintf(w, "API requests:\t%d today\n", used)
+		}
+	}
+
 	if v, ok := v.(api.SocLimiter); ok {
 		if targetSoC, err := v.TargetSoC(); err != nil {
 			fmt.Fprintf(w, "Target SoC:\t%v\n", err)
@@ -11,6 +11,9 @@ const (
 	flagSqlite            = "sqlite"
 	flagSqliteDescription = "SQlite database file"
 
+	flagTemplateDir            = "template-dir"
+	flagTemplateDirDescription = "Directory with additional device templates"
+
 	flagHeaders            = "log-headers"
 	flagHeadersDescription = "Log headers"
 
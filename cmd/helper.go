@@ -49,16 +49,18 @@ func unwrap(err error) (res []string) {
 
 // redact redacts a configuration string
 func redact(src string) string {
-	secrets := []string{
-		"mac",                   // infrastructure
-		"sponsortoken", "plant", // global settings
-		"user", "password", "pin", // users
-		"token", "access", "refresh", // tokens
-		"ain", "secret", "serial", "deviceid", "machineid", // devices
-		"vin"} // vehicles
-	return regexp.
-		MustCompile(fmt.Sprintf(`\b(%s)\b.*?:.*`, strings.Join(secrets, "|"))).
-		ReplaceAllString(src, "$1: *****")
+	re := regexp.MustCompile(fmt.Sprintf(`\b(%s)\b.*?:(.*)`, strings.Join(util.SecretKeys, "|")))
+
+	return re.ReplaceAllStringFunc(src, func(line string) string {
+		match := re.FindStringSubmatch(line)
+
+		// secret references don't reveal the secret
+		if util.IsSecretRef(strings.Trim(strings.TrimSpace(match[2]), `"'`)) {
+			return line
+		}
+
+		return match[1] + ": *****"
+	})
 }
 
 func publishErrorInfo(valueChan chan<- util.Param, cfgFile string, err error) {
@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// confDir is the directory next to the config file whose files are merged into the config
+const confDir = "conf.d"
+
+var (
+	configFiles  []string       // files merged into the effective config
+	mergedConfig map[string]any // effective config if files have been merged
+)
+
+// includedFiles returns the files merged into the config file
+func includedFiles() []string {
+	if len(configFiles) < 2 {
+		return nil
+	}
+	return configFiles[1:]
+}
+
+// includeFiles returns the files referenced by the include directive followed by the conf.d files.
+// Relative paths are resolved against the config file's directory.
+func includeFiles(dir string, include []string) ([]string, error) {
+	var res []string
+
+	for _, pattern := range include {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(dir, pattern)
+		}
+
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("include %s: %w", pattern, err)
+		}
+
+		// missing files are an error unless included by pattern
+		if len(matches) == 0 && !strings.ContainsAny(pattern, `*?[`) {
+			return nil, fmt.Errorf("include %s: %w", pattern, os.ErrNotExist)
+		}
+
+		sort.Strings(matches)
+		res = append(res, matches...)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, confDir))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	// ReadDir returns entries sorted by name
+	for _, e := range entries {
+		if ext := filepath.Ext(e.Name()); !e.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			res = append(res, filepath.Join(dir, confDir, e.Name()))
+		}
+	}
+
+	return res, nil
+}
+
+func readYaml(file string) (map[string]any, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]any)
+	if err := yaml.Unmarshal(b, &res); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	return res, nil
+}
+
+// includeList returns the include directive as list
+func includeList(v any) ([]string, error) {
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{v}, nil
+	case []any:
+		res := make([]string, 0, len(v))
+		for _, s := range v {
+			str, ok := s.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid include: %v", s)
+			}
+			res = append(res, str)
+		}
+		return res, nil
+	default:
+		return nil, fmt.Errorf("invalid include: %v", v)
+	}
+}
+
+// mergeIncludes merges the included files and conf.d directory into the config file read by viper.
+// Files are merged in order, see mergeConfig for the rules.
+func mergeIncludes(file string) error {
+	configFiles = []string{file}
+	mergedConfig = nil
+
+	res, err := readYaml(file)
+	if err != nil {
+		return err
+	}
+
+	include, err := includeList(res["include"])
+	if err != nil {
+		return err
+	}
+
+	files, err := includeFiles(filepath.Dir(file), include)
+	if err != nil || len(files) == 0 {
+		return err
+	}
+
+	for _, inc := range files {
+		other, err := readYaml(inc)
+		if err != nil {
+			return err
+		}
+
+		if _, ok := other["include"]; ok {
+			return fmt.Errorf("%s: nested include not supported", inc)
+		}
+
+		res = mergeConfig(res, other)
+		configFiles = append(configFiles, inc)
+	}
+
+	mergedConfig = res
+
+	return viper.MergeConfigMap(res)
+}
+
+// mergeConfig merges other into conf:
+//   - maps are merged recursively, keys are case-insensitive
+//   - lists are appended, list entries with an existing name replace the earlier entry
+//   - other values are replaced
+func mergeConfig(conf, other map[string]any) map[string]any {
+	if conf == nil {
+		conf = make(map[string]any)
+	}
+
+	for k, v := range other {
+		key := k
+		for ck := range conf {
+			if strings.EqualFold(ck, k) {
+				key = ck
+				break
+			}
+		}
+
+		conf[key] = mergeValue(conf[key], v)
+	}
+
+	return conf
+}
+
+func mergeValue(cur, v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		if m, ok := cur.(map[string]any); ok {
+			return mergeConfig(m, v)
+		}
+
+	case []any:
+		if l, ok := cur.([]any); ok {
+			return mergeList(l, v)
+		}
+	}
+
+	return v
+}
+
+func mergeList(list, other []any) []any {
+	res := append([]any{}, list...)
+
+	for _, v := range other {
+		if name := entryName(v); name != "" {
+			if idx := indexByName(res, name); idx >= 0 {
+				res[idx] = v
+				continue
+			}
+		}
+
+		res = append(res, v)
+	}
+
+	return res
+}
+
+func entryName(v any) string {
+	if m, ok := v.(map[string]any); ok {
+		if name, ok := m["name"].(string); ok {
+			return name
+		}
+	}
+	return ""
+}
+
+func indexByName(list []any, name string) int {
+	for i, v := range list {
+		if entryName(v) == name {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestMergeConfig(t *testing.T) {
+	var conf, other map[string]any
+
+	require.NoError(t, yaml.Unmarshal([]byte(`
+interval: 10s
+site:
+  title: Home
+  meters:
+    grid: grid
+vehicles:
+- name: car
+  type: template
+  template: tesla
+- name: bike
+  type: custom
+`), &conf))
+
+	require.NoError(t, yaml.Unmarshal([]byte(`
+interval: 30s
+Site:
+  meters:
+    pv: pv
+vehicles:
+- name: car
+  type: template
+  template: bmw
+- name: van
+  type: custom
+`), &other))
+
+	res := mergeConfig(conf, other)
+
+	assert.Equal(t, "30s", res["interval"])
+	assert.Equal(t, map[string]any{
+		"title": "Home",
+		"meters": map[string]any{
+			"grid": "grid",
+			"pv":   "pv",
+		},
+	}, res["site"])
+
+	vehicles := res["vehicles"].([]any)
+	require.Len(t, vehicles, 3)
+	assert.Equal(t, []string{"car", "bike", "van"}, []string{entryName(vehicles[0]), entryName(vehicles[1]), entryName(vehicles[2])})
+	assert.Equal(t, "bmw", vehicles[0].(map[string]any)["template"])
+}
+
+func TestMergeIncludes(t *testing.T) {
+	dir := t.TempDir()
+
+	write := func(name, content string) {
+		file := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), 0o755))
+		require.NoError(t, os.WriteFile(file, []byte(content), 0o644))
+	}
+
+	write("evcc.yaml", `
+include: vehicles.yaml
+meters:
+- name: grid
+  type: custom
+`)
+	write("vehicles.yaml", `
+vehicles:
+- name: car
+  type: custom
+`)
+	write("conf.d/20-pv.yaml", `
+meters:
+- name: pv
+  type: custom
+`)
+	write("conf.d/10-grid.yaml", `
+meters:
+- name: grid
+  type: template
+`)
+	write("conf.d/readme.txt", `ignored`)
+
+	file := filepath.Join(dir, "evcc.yaml")
+
+	v := viper.GetViper()
+	t.Cleanup(viper.Reset)
+
+	v.SetConfigFile(file)
+	require.NoError(t, v.ReadInConfig())
+	require.NoError(t, mergeIncludes(file))
+
+	assert.Equal(t, []string{
+		filepath.Join(dir, "vehicles.yaml"),
+		filepath.Join(dir, "conf.d", "10-grid.yaml"),
+		filepath.Join(dir, "conf.d", "20-pv.yaml"),
+	}, includedFiles())
+
+	var conf config
+	require.NoError(t, viper.UnmarshalExact(&conf))
+
+	require.Len(t, conf.Meters, 2)
+	assert.Equal(t, "grid", conf.Meters[0].Name)
+	assert.Equal(t, "template", conf.Meters[0].Type)
+	assert.Equal(t, "pv", conf.Meters[1].Name)
+
+	require.Len(t, conf.Vehicles, 1)
+	assert.Equal(t, "car", conf.Vehicles[0].Name)
+}
+
+func TestMergeIncludesErrors(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "evcc.yaml")
+
+	t.Cleanup(viper.Reset)
+
+	require.NoError(t, os.WriteFile(file, []byte("include: missing.yaml"), 0o644))
+	assert.ErrorIs(t, mergeIncludes(file), os.ErrNotExist)
+
+	// patterns may be empty
+	require.NoError(t, os.WriteFile(file, []byte("include: sites/*.yaml"), 0o644))
+	assert.NoError(t, mergeIncludes(file))
+
+	require.NoError(t, os.WriteFile(file, []byte("include: other.yaml"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "other.yaml"), []byte("include: evcc.yaml"), 0o644))
+	assert.Error(t, mergeIncludes(file))
+}
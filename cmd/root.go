@@ -13,9 +13,13 @@ import (
 	"time"
 
 	"github.com/evcc-io/evcc/core"
+	"github.com/evcc-io/evcc/core/remote"
+	"github.com/evcc-io/evcc/detect"
 	"github.com/evcc-io/evcc/push"
 	"github.com/evcc-io/evcc/server"
+	"github.com/evcc-io/evcc/server/db"
 	"github.com/evcc-io/evcc/server/modbus"
+	"github.com/evcc-io/evcc/server/setup"
 	"github.com/evcc-io/evcc/server/updater"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/pipe"
@@ -58,6 +62,11 @@ func init() {
 
 	rootCmd.PersistentFlags().String(flagSqlite, "", flagSqliteDescription)
 
+	rootCmd.PersistentFlags().String(flagTemplateDir, "", flagTemplateDirDescription)
+	if err := viper.BindPFlag("templates", rootCmd.PersistentFlags().Lookup(flagTemplateDir)); err != nil {
+		panic(err)
+	}
+
 	// config file options
 	rootCmd.PersistentFlags().StringP("log", "l", "info", "Log level (fatal, error, warn, info, debug, trace)")
 	bindP(rootCmd, "log")
@@ -182,9 +191,20 @@ func runRoot(cmd *cobra.Command, args []string) {
 		site, err = configureSiteAndLoadpoints(conf)
 	}
 
+	// setup remote sites
+	var remotes []*remote.Site
+	if err == nil && len(conf.Remotes) > 0 {
+		remotes, err = configureRemotes(conf)
+	}
+
 	// setup database
 	if err == nil && conf.Influx.URL != "" {
-		configureInflux(conf.Influx, site.LoadPoints(), tee.Attach())
+		configureInflux(conf.Influx, site.LoadPoints(), site.Consumers(), tee.Attach())
+	}
+
+	// setup sunspec meter emulation
+	if err == nil && conf.SunSpec.Port != 0 {
+		err = configureSunSpec(conf.SunSpec, tee)
 	}
 
 	// setup mqtt publisher
@@ -242,6 +262,31 @@ func runRoot(cmd *cobra.Command, args []string) {
 	if err == nil {
 		httpd.RegisterSiteHandlers(site, cache)
 
+		if len(remotes) > 0 {
+			httpd.RegisterRemoteHandlers(remotes)
+		}
+
+		// runtime device configuration and setup wizard
+		var dc server.DeviceConfigurator
+		if db.Instance != nil {
+			cp.site = site
+			dc = cp
+			httpd.RegisterConfigHandlers(cp)
+		}
+		setup.RegisterHandlers(httpd.Router(), dc)
+
+		// backup api
+		if db.Instance != nil && conf.Backup.Token != "" {
+			httpd.RegisterBackupHandlers(conf.Backup.Token, cfgFile)
+		}
+
+		// background network discovery
+		if conf.Discovery.Interval > 0 {
+			discovery := detect.NewDiscovery(conf.Discovery.Interval)
+			setup.RegisterDiscoveryHandlers(httpd.Router(), discovery)
+			go discovery.Run()
+		}
+
 		// set channels
 		site.DumpConfig()
 		site.Prepare(valueChan, pushChan)
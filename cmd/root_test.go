@@ -37,3 +37,19 @@ func TestRedact(t *testing.T) {
 		t.Errorf("secret exposed: %v", res)
 	}
 }
+
+func TestRedactSecretRef(t *testing.T) {
+	secret := `
+	password: ${env:PASSWORD}
+	token: "${file:/run/secrets/token}"
+	user: geheim
+	`
+
+	res := redact(secret)
+	if !strings.Contains(res, "${env:PASSWORD}") || !strings.Contains(res, "${file:/run/secrets/token}") {
+		t.Errorf("secret reference redacted: %v", res)
+	}
+	if strings.Contains(res, "geheim") {
+		t.Errorf("secret exposed: %v", res)
+	}
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/spf13/cobra"
+)
+
+// secretCmd represents the secret command
+var secretCmd = &cobra.Command{
+	Use:   "secret [value]",
+	Short: "Encrypt configuration secret",
+	Long: fmt.Sprintf(`Encrypt a secret for use in the configuration file using the key from %s.
+The value is read from stdin if not given as argument. The resulting ${enc:...} reference
+is decrypted when the configuration is loaded. Alternatively, use ${env:NAME} or ${file:/path}
+references to read secrets from environment variables or files.`, util.SecretKeyEnv),
+	Args: cobra.MaximumNArgs(1),
+	Run:  runSecret,
+}
+
+func init() {
+	rootCmd.AddCommand(secretCmd)
+}
+
+func runSecret(cmd *cobra.Command, args []string) {
+	var val string
+	if len(args) == 1 {
+		val = args[0]
+	} else {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			log.FATAL.Fatal(err)
+		}
+		val = strings.TrimRight(line, "\r\n")
+	}
+
+	res, err := util.EncryptSecret(val)
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	fmt.Println(res)
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -11,14 +12,19 @@ import (
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/cmd/shutdown"
 	"github.com/evcc-io/evcc/core"
+	"github.com/evcc-io/evcc/core/consumer"
 	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/remote"
 	"github.com/evcc-io/evcc/hems"
 	"github.com/evcc-io/evcc/provider/javascript"
 	"github.com/evcc-io/evcc/provider/mqtt"
 	"github.com/evcc-io/evcc/push"
 	"github.com/evcc-io/evcc/server"
 	"github.com/evcc-io/evcc/server/db"
+	dbconfig "github.com/evcc-io/evcc/server/db/config"
 	"github.com/evcc-io/evcc/server/db/settings"
+	"github.com/evcc-io/evcc/server/db/tokens"
+	"github.com/evcc-io/evcc/server/modbus"
 	"github.com/evcc-io/evcc/tariff"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/locale"
@@ -26,8 +32,9 @@ import (
 	"github.com/evcc-io/evcc/util/pipe"
 	"github.com/evcc-io/evcc/util/request"
 	"github.com/evcc-io/evcc/util/sponsor"
+	"github.com/evcc-io/evcc/util/templates"
 	"github.com/libp2p/zeroconf/v2"
-	"github.com/samber/lo"
+	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"golang.org/x/text/currency"
@@ -35,6 +42,13 @@ import (
 
 var cp = new(ConfigProvider)
 
+// secretDecodeHook resolves secret references in addition to viper's default decode hooks
+var secretDecodeHook = mapstructure.ComposeDecodeHookFunc(
+	util.SecretHookFunc(),
+	mapstructure.StringToTimeDurationHookFunc(),
+	mapstructure.StringToSliceHookFunc(","),
+)
+
 func loadConfigFile(conf *config) error {
 	err := viper.ReadInConfig()
 
@@ -44,8 +58,15 @@ func loadConfigFile(conf *config) error {
 
 	log.INFO.Println("using config file:", cfgFile)
 
+	// merge include directive and conf.d directory
+	if ext := filepath.Ext(cfgFile); err == nil && (ext == ".yaml" || ext == ".yml") {
+		if err = mergeIncludes(cfgFile); err == nil && len(includedFiles()) > 0 {
+			log.INFO.Println("merged config files:", strings.Join(includedFiles(), ", "))
+		}
+	}
+
 	if err == nil {
-		if err = viper.UnmarshalExact(&conf); err != nil {
+		if err = viper.UnmarshalExact(&conf, viper.DecodeHook(secretDecodeHook)); err != nil {
 			err = fmt.Errorf("failed parsing config file: %w", err)
 		}
 	}
@@ -71,7 +92,9 @@ func configureEnvironment(cmd *cobra.Command, conf config) (err error) {
 
 	// setup sponsorship (allow env override)
 	if token := viper.GetString("sponsortoken"); err == nil && token != "" {
-		err = sponsor.ConfigureSponsorship(token)
+		if token, err = util.ResolveSecrets(token); err == nil {
+			err = sponsor.ConfigureSponsorship(token)
+		}
 	}
 
 	// setup translations
@@ -79,6 +102,11 @@ func configureEnvironment(cmd *cobra.Command, conf config) (err error) {
 		err = locale.Init()
 	}
 
+	// setup user templates
+	if err == nil && conf.Templates != "" {
+		err = configureTemplates(conf.Templates)
+	}
+
 	// setup persistence
 	if err == nil && conf.Database.Dsn != "" {
 		if flag := cmd.Flags().Lookup(flagSqlite); flag.Changed {
@@ -106,11 +134,35 @@ func configureEnvironment(cmd *cobra.Command, conf config) (err error) {
 	return
 }
 
+// configureTemplates loads user templates from directory
+func configureTemplates(dir string) error {
+	conflicts, err := templates.LoadDirectory(dir)
+	if err != nil {
+		return fmt.Errorf("failed loading templates: %w", err)
+	}
+
+	for _, c := range conflicts {
+		log.WARN.Println(c)
+	}
+
+	return nil
+}
+
 // configureDatabase configures session database
 func configureDatabase(conf dbConfig) error {
-	err := db.NewInstance(conf.Type, conf.Dsn)
+	err := db.NewInstance(conf.Type, conf.Dsn, conf.Prefix)
 	if err == nil {
-		if err = settings.Init(); err == nil {
+		err = db.SetPool(conf.Pool)
+	}
+	if err == nil {
+		err = settings.Init()
+		if err == nil {
+			err = dbconfig.Init()
+		}
+		if err == nil {
+			err = tokens.Init()
+		}
+		if err == nil {
 			shutdown.Register(func() {
 				if err := settings.Persist(); err != nil {
 					log.ERROR.Println("cannot save settings:", err)
@@ -122,7 +174,7 @@ func configureDatabase(conf dbConfig) error {
 }
 
 // configureInflux configures influx database
-func configureInflux(conf server.InfluxConfig, loadPoints []loadpoint.API, in <-chan util.Param) {
+func configureInflux(conf server.InfluxConfig, loadPoints []loadpoint.API, consumers []consumer.API, in <-chan util.Param) {
 	influx := server.NewInfluxClient(
 		conf.URL,
 		conf.Token,
@@ -136,7 +188,7 @@ func configureInflux(conf server.InfluxConfig, loadPoints []loadpoint.API, in <-
 	dedupe := pipe.NewDeduplicator(30*time.Minute, "vehicleCapacity", "vehicleSoC", "vehicleRange", "vehicleOdometer", "chargedEnergy", "chargeRemainingEnergy")
 	in = dedupe.Pipe(in)
 
-	go influx.Run(loadPoints, in)
+	go influx.Run(loadPoints, consumers, in)
 }
 
 // setup mqtt
@@ -155,6 +207,18 @@ func configureMQTT(conf mqttConfig) error {
 	return nil
 }
 
+// setup sunspec meter emulation
+func configureSunSpec(conf sunspecConfig, tee util.TeeAttacher) error {
+	sunspec := modbus.NewSunSpec(conf.ID)
+	if err := sunspec.Start(conf.Port); err != nil {
+		return fmt.Errorf("failed configuring sunspec: %w", err)
+	}
+
+	go sunspec.Run(tee.Attach())
+
+	return nil
+}
+
 // setup javascript
 func configureJavascript(conf []javascriptConfig) error {
 	for _, cc := range conf {
@@ -257,26 +321,26 @@ func configureSiteAndLoadpoints(conf config) (site *core.Site, err error) {
 		var loadPoints []*core.LoadPoint
 		loadPoints, err = configureLoadPoints(conf, cp)
 
+		var consumers []core.ConsumerUpdater
+		if err == nil {
+			consumers, err = configureConsumers(conf, cp)
+		}
+
 		var tariffs tariff.Tariffs
 		if err == nil {
 			tariffs, err = configureTariffs(conf.Tariffs)
 		}
 
 		if err == nil {
-			// list of vehicles
-			vehicles := lo.MapToSlice(cp.vehicles, func(_ string, v api.Vehicle) api.Vehicle {
-				return v
-			})
-
-			site, err = configureSite(conf.Site, cp, loadPoints, vehicles, tariffs)
+			site, err = configureSite(conf.Site, cp, loadPoints, consumers, cp.vehicleList(), tariffs)
 		}
 	}
 
 	return site, err
 }
 
-func configureSite(conf map[string]interface{}, cp *ConfigProvider, loadPoints []*core.LoadPoint, vehicles []api.Vehicle, tariffs tariff.Tariffs) (*core.Site, error) {
-	site, err := core.NewSiteFromConfig(log, cp, conf, loadPoints, vehicles, tariffs)
+func configureSite(conf map[string]interface{}, cp *ConfigProvider, loadPoints []*core.LoadPoint, consumers []core.ConsumerUpdater, vehicles []api.Vehicle, tariffs tariff.Tariffs) (*core.Site, error) {
+	site, err := core.NewSiteFromConfig(log, cp, conf, loadPoints, consumers, vehicles, tariffs)
 	if err != nil {
 		return nil, fmt.Errorf("failed configuring site: %w", err)
 	}
@@ -285,8 +349,21 @@ func configureSite(conf map[string]interface{}, cp *ConfigProvider, loadPoints [
 }
 
 func configureLoadPoints(conf config, cp *ConfigProvider) (loadPoints []*core.LoadPoint, err error) {
-	lpInterfaces, ok := viper.AllSettings()["loadpoints"].([]interface{})
-	if !ok || len(lpInterfaces) == 0 {
+	lpInterfaces, _ := viper.AllSettings()["loadpoints"].([]interface{})
+
+	// add loadpoints stored in the database
+	runtime, err := runtimeLoadPoints()
+	if err != nil {
+		return nil, fmt.Errorf("failed reading loadpoint configuration: %w", err)
+	}
+	static := len(lpInterfaces)
+	for _, lpc := range runtime {
+		lpInterfaces = append(lpInterfaces, lpc.other)
+	}
+
+	running := make(map[int]*core.LoadPoint)
+
+	if len(lpInterfaces) == 0 {
 		return nil, errors.New("missing loadpoints")
 	}
 
@@ -303,7 +380,53 @@ func configureLoadPoints(conf config, cp *ConfigProvider) (loadPoints []*core.Lo
 		}
 
 		loadPoints = append(loadPoints, lp)
+
+		if id >= static {
+			running[runtime[id-static].id] = lp
+		}
 	}
 
+	// added or deleted runtime loadpoints take effect after restart
+	cp.mu.Lock()
+	cp.loadpoints = running
+	cp.mu.Unlock()
+
 	return loadPoints, nil
 }
+
+func configureConsumers(conf config, cp *ConfigProvider) (consumers []core.ConsumerUpdater, err error) {
+	for id, cc := range conf.Consumers {
+		log := util.NewLogger("consumer-" + strconv.Itoa(id+1))
+		c, err := core.NewConsumerFromConfig(log, cp, cc)
+		if err != nil {
+			return nil, fmt.Errorf("failed configuring consumer: %w", err)
+		}
+
+		consumers = append(consumers, c)
+	}
+
+	for id, cc := range conf.HeatPumps {
+		log := util.NewLogger("heatpump-" + strconv.Itoa(id+1))
+		hp, err := core.NewHeatPumpFromConfig(log, cp, cc)
+		if err != nil {
+			return nil, fmt.Errorf("failed configuring heat pump: %w", err)
+		}
+
+		consumers = append(consumers, hp)
+	}
+
+	return consumers, nil
+}
+
+func configureRemotes(conf config) (remotes []*remote.Site, err error) {
+	for _, cc := range conf.Remotes {
+		s, err := remote.NewSiteFromConfig(cc)
+		if err != nil {
+			return nil, fmt.Errorf("failed configuring remote: %w", err)
+		}
+
+		remotes = append(remotes, s)
+	}
+
+	return remotes, nil
+}
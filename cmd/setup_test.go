@@ -35,3 +35,35 @@ func TestYamlOff(t *testing.T) {
 		t.Errorf("expected `off`, got %s", lp.Mode)
 	}
 }
+
+const secretSample = `
+meters:
+- name: grid
+  type: custom
+  power:
+    source: http
+    uri: http://host/power
+    auth:
+      password: ${env:EVCC_TEST_SECRET}
+`
+
+func TestYamlSecrets(t *testing.T) {
+	t.Setenv("EVCC_TEST_SECRET", "geheim")
+
+	var conf config
+	viper.SetConfigType("yaml")
+	if err := viper.ReadConfig(strings.NewReader(secretSample)); err != nil {
+		t.Error(err)
+	}
+
+	if err := viper.UnmarshalExact(&conf, viper.DecodeHook(secretDecodeHook)); err != nil {
+		t.Error(err)
+	}
+
+	power, _ := conf.Meters[0].Other["power"].(map[string]interface{})
+	auth, _ := power["auth"].(map[string]interface{})
+
+	if pw := auth["password"]; pw != "geheim" {
+		t.Errorf("expected resolved secret, got %v", pw)
+	}
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/evcc-io/evcc/server/db/tokens"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// tokensCmd represents the tokens command
+var tokensCmd = &cobra.Command{
+	Use:   "tokens",
+	Short: "List stored vehicle tokens",
+	Args:  cobra.NoArgs,
+	Run:   runTokens,
+}
+
+// tokensRevokeCmd represents the tokens revoke command
+var tokensRevokeCmd = &cobra.Command{
+	Use:   "revoke <provider> <account>",
+	Short: "Revoke stored vehicle token",
+	Long:  "Revoke removes the stored token. Running vehicles can no longer store their token and log in again with their credentials on next start.",
+	Args:  cobra.ExactArgs(2),
+	Run:   runTokensRevoke,
+}
+
+func init() {
+	rootCmd.AddCommand(tokensCmd)
+	tokensCmd.AddCommand(tokensRevokeCmd)
+}
+
+func openTokens(cmd *cobra.Command) {
+	if err := loadConfigFile(&conf); err != nil && !errors.As(err, &viper.ConfigFileNotFoundError{}) {
+		log.FATAL.Fatal(err)
+	}
+
+	if err := openDatabase(cmd, conf.Database); err != nil {
+		log.FATAL.Fatal(err)
+	}
+}
+
+func runTokens(cmd *cobra.Command, args []string) {
+	openTokens(cmd)
+
+	res, err := tokens.List()
+	if err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	if len(res) == 0 {
+		fmt.Println("no stored tokens")
+		return
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Provider\tAccount\tExpiry\tUpdated")
+
+	for _, t := range res {
+		expiry := "-"
+		if !t.Expiry.IsZero() {
+			expiry = t.Expiry.Local().Format(time.RFC3339)
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", t.Provider, t.Account, expiry, t.Updated.Local().Format(time.RFC3339))
+	}
+
+	tw.Flush()
+}
+
+func runTokensRevoke(cmd *cobra.Command, args []string) {
+	openTokens(cmd)
+
+	if err := tokens.Delete(args[0], args[1]); err != nil {
+		log.FATAL.Fatal(err)
+	}
+
+	fmt.Printf("revoked token for %s account %s\n", args[0], args[1])
+}
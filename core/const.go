@@ -6,6 +6,7 @@ const (
 	phasesActive     = "phasesActive"     // active phases as used by vehicle (1/2/3)
 
 	vehicleDetectionActive = "vehicleDetectionActive" // vehicle detection is active (bool)
+	vehicleConfidence      = "vehicleConfidence"      // vehicle signature identification confidence (0..1)
 
 	vehicleRange     = "vehicleRange"     // vehicle range
 	vehicleOdometer  = "vehicleOdometer"  // vehicle odometer
@@ -0,0 +1,282 @@
+package core
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/avast/retry-go/v3"
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/provider"
+	"github.com/evcc-io/evcc/util"
+)
+
+// Consumer is a switchable non-EV device like a heating rod or heat pump.
+// It participates in PV surplus distribution and can be operated either
+// on/off at nominal power or with a power setpoint.
+type Consumer struct {
+	sync.Mutex // guard status
+	log        *util.Logger
+	clock      clock.Clock       // mockable time
+	uiChan     chan<- util.Param // client push messages
+
+	// exposed public configuration
+	Title       string           `mapstructure:"title"`       // UI title
+	ChargerRef  string           `mapstructure:"charger"`     // Switchable device
+	MeterRef    string           `mapstructure:"meter"`       // Consumption meter
+	Mode        api.ChargeMode   `mapstructure:"mode"`        // Operating mode
+	Power       float64          `mapstructure:"power"`       // Nominal power
+	MinPower    float64          `mapstructure:"minPower"`    // Minimum setpoint power
+	Setpoint    *provider.Config `mapstructure:"setpoint"`    // Optional power setpoint
+	MinRuntime  time.Duration    `mapstructure:"minRuntime"`  // Minimum runtime once switched on
+	DailyEnergy float64          `mapstructure:"dailyEnergy"` // Daily energy demand in kWh
+
+	charger  api.Charger
+	meter    api.Meter
+	setpoint func(int64) error
+
+	// cached state
+	enabled       bool                   // Switching state
+	power         float64                // Current power
+	setpointPower float64                // Current power setpoint
+	switched      time.Time              // Last switching time
+	energy        float64                // Energy consumed today in Wh
+	updated       time.Time              // Last energy update
+	remoteDemand  loadpoint.RemoteDemand // External status demand
+}
+
+// NewConsumerFromConfig creates a new consumer
+func NewConsumerFromConfig(log *util.Logger, cp configProvider, other map[string]interface{}) (*Consumer, error) {
+	c := NewConsumer(log)
+	if err := util.DecodeOther(other, c); err != nil {
+		return nil, err
+	}
+
+	if c.Power <= 0 {
+		return nil, errors.New("missing power")
+	}
+
+	if c.ChargerRef == "" {
+		return nil, errors.New("missing charger")
+	}
+
+	var err error
+	if c.charger, err = cp.Charger(c.ChargerRef); err != nil {
+		return nil, err
+	}
+
+	if c.MeterRef != "" {
+		if c.meter, err = cp.Meter(c.MeterRef); err != nil {
+			return nil, err
+		}
+	} else if mt, ok := c.charger.(api.Meter); ok {
+		c.meter = mt
+	}
+
+	if c.Setpoint != nil {
+		if c.MinPower <= 0 || c.MinPower > c.Power {
+			return nil, errors.New("setpoint requires minPower between zero and power")
+		}
+
+		if c.setpoint, err = provider.NewIntSetterFromConfig("power", *c.Setpoint); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// NewConsumer creates a Consumer with sane defaults
+func NewConsumer(log *util.Logger) *Consumer {
+	c := &Consumer{
+		log:   log,
+		clock: clock.New(),
+		Mode:  api.ModePV,
+	}
+
+	return c
+}
+
+// publish sends values to UI and databases
+func (c *Consumer) publish(key string, val interface{}) {
+	if c.uiChan != nil {
+		c.uiChan <- util.Param{Key: key, Val: val}
+	}
+}
+
+// Prepare consumer configuration by adding missing values
+func (c *Consumer) Prepare(uiChan chan<- util.Param) {
+	c.uiChan = uiChan
+
+	c.publish("title", c.Title)
+	c.publish("mode", c.Mode)
+	c.publish("minPower", c.GetMinPower())
+	c.publish("maxPower", c.GetMaxPower())
+
+	var err error
+	if c.enabled, err = c.charger.Enabled(); err != nil {
+		c.log.ERROR.Printf("charger: %v", err)
+	}
+
+	c.publish("enabled", c.enabled)
+}
+
+// dumpConfig logs the consumer configuration
+func (c *Consumer) dumpConfig() {
+	c.log.INFO.Printf("  mode:        %s", c.GetMode())
+	c.log.INFO.Printf("  power:       min %.0fW max %.0fW setpoint %s", c.GetMinPower(), c.GetMaxPower(), presence[c.setpoint != nil])
+	c.log.INFO.Printf("  meters:      power %s", presence[c.HasMeter()])
+}
+
+// updatePower updates the consumer's power from meter or nominal values
+func (c *Consumer) updatePower() {
+	switch {
+	case c.meter != nil:
+		err := retry.Do(func() error {
+			value, err := c.meter.CurrentPower()
+			if err == nil {
+				c.power = value
+			}
+			return err
+		}, retryOptions...)
+
+		if err != nil {
+			c.log.ERROR.Printf("consumer meter: %v", err)
+		}
+
+	case !c.enabled:
+		c.power = 0
+
+	case c.setpoint != nil:
+		c.power = c.setpointPower
+
+	default:
+		c.power = c.Power
+	}
+
+	c.log.DEBUG.Printf("consumer power: %.0fW", c.power)
+	c.publish("power", c.power)
+}
+
+// updateEnergy integrates the consumed energy and resets it at midnight
+func (c *Consumer) updateEnergy() {
+	now := c.clock.Now()
+
+	if y, m, d := now.Date(); c.updated.IsZero() || c.updated.Day() != d || c.updated.Month() != m || c.updated.Year() != y {
+		c.energy = 0
+	} else {
+		c.energy += c.power * now.Sub(c.updated).Hours()
+	}
+
+	c.updated = now
+
+	c.publish("energy", c.energy)
+	c.publish("remainingEnergy", c.remainingEnergy())
+}
+
+// remainingEnergy is the remaining daily energy demand in Wh
+func (c *Consumer) remainingEnergy() float64 {
+	return math.Max(0, 1e3*c.DailyEnergy-c.energy)
+}
+
+// energyDue returns true if the remaining daily energy demand can only just be met before midnight
+func (c *Consumer) energyDue() bool {
+	remaining := c.remainingEnergy()
+	if remaining == 0 {
+		return false
+	}
+
+	now := c.clock.Now()
+	midnight := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+	required := time.Duration(remaining / c.Power * float64(time.Hour))
+
+	return midnight.Sub(now) <= required
+}
+
+// targetPower determines the consumer's target power. Required demand is not subject to minimum runtime.
+func (c *Consumer) targetPower(sitePower float64, cheap bool) (float64, bool) {
+	// power available to the consumer
+	available := c.power - sitePower
+
+	switch {
+	case c.remoteDemand == loadpoint.RemoteHardDisable || c.Mode == api.ModeOff:
+		return 0, true
+
+	case c.Mode == api.ModeNow:
+		return c.Power, true
+
+	case c.energyDue():
+		c.log.DEBUG.Printf("daily energy due: %.0fWh remaining", c.remainingEnergy())
+		return c.Power, true
+
+	// Sunny Home Manager
+	case c.remoteDemand == loadpoint.RemoteSoftDisable:
+		return 0, true
+
+	case cheap:
+		c.log.DEBUG.Println("cheap tariff")
+		return c.Power, true
+
+	case available < c.GetMinPower():
+		return 0, false
+
+	default:
+		return math.Min(available, c.Power), false
+	}
+}
+
+// Update executes the consumer control logic and returns the expected change of site power
+func (c *Consumer) Update(sitePower float64, cheap bool) float64 {
+	c.Lock()
+	defer c.Unlock()
+
+	c.updatePower()
+	c.updateEnergy()
+
+	target, required := c.targetPower(sitePower, cheap)
+
+	// on/off devices run at nominal power
+	if target > 0 && c.setpoint == nil {
+		target = c.Power
+	}
+
+	// honour minimum runtime
+	if target == 0 && !required && c.enabled && c.clock.Since(c.switched) < c.MinRuntime {
+		c.log.DEBUG.Printf("minimum runtime: %v remaining", (c.MinRuntime - c.clock.Since(c.switched)).Round(time.Second))
+		return 0
+	}
+
+	var delta float64
+
+	if target > 0 && c.setpoint != nil && target != c.setpointPower {
+		if err := c.setpoint(int64(target)); err != nil {
+			c.log.ERROR.Printf("setpoint: %v", err)
+			return 0
+		}
+
+		c.log.DEBUG.Printf("setpoint: %.0fW", target)
+		c.setpointPower = target
+		c.publish("setpoint", target)
+
+		delta = target - c.power
+	}
+
+	if enable := target > 0; enable != c.enabled {
+		if err := c.charger.Enable(enable); err != nil {
+			c.log.ERROR.Printf("charger: %v", err)
+			return delta
+		}
+
+		c.log.DEBUG.Printf("consumer enabled: %t", enable)
+		c.enabled = enable
+		c.switched = c.clock.Now()
+		c.publish("enabled", enable)
+
+		delta = target - c.power
+	}
+
+	return delta
+}
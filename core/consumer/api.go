@@ -0,0 +1,45 @@
+package consumer
+
+import (
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/loadpoint"
+)
+
+// API is the external consumer API
+type API interface {
+	// Name returns the defined consumer name
+	Name() string
+
+	//
+	// status
+	//
+
+	// GetEnabled returns the consumer's switching state
+	GetEnabled() bool
+	// GetPower returns the consumer's current power
+	GetPower() float64
+	// HasMeter determines if a physical meter is attached
+	HasMeter() bool
+	// GetRemainingEnergy is the remaining daily energy demand in Wh
+	GetRemainingEnergy() float64
+
+	//
+	// settings
+	//
+
+	// GetMode returns the operating mode
+	GetMode() api.ChargeMode
+	// SetMode sets the operating mode
+	SetMode(api.ChargeMode) error
+	// GetMinPower returns the minimum power the consumer can be operated at
+	GetMinPower() float64
+	// GetMaxPower returns the maximum power the consumer can be operated at
+	GetMaxPower() float64
+
+	//
+	// remote control
+	//
+
+	// RemoteControl sets remote status demand
+	RemoteControl(string, loadpoint.RemoteDemand)
+}
@@ -0,0 +1,47 @@
+package consumer
+
+// SGReadyState is the SG-Ready operating state
+type SGReadyState string
+
+// SG-Ready operating states
+const (
+	SGReadyBlocked     SGReadyState = "blocked"     // utility lock (1/0)
+	SGReadyNormal      SGReadyState = "normal"      // normal operation (0/0)
+	SGReadyRecommended SGReadyState = "recommended" // recommended on (0/1)
+	SGReadyForced      SGReadyState = "forced"      // forced on (1/1)
+)
+
+// Relays returns the SG-Ready relay inputs for the state
+func (s SGReadyState) Relays() (bool, bool) {
+	switch s {
+	case SGReadyBlocked:
+		return true, false
+	case SGReadyRecommended:
+		return false, true
+	case SGReadyForced:
+		return true, true
+	default:
+		return false, false
+	}
+}
+
+// Int returns the SG-Ready operating state number
+func (s SGReadyState) Int() int {
+	switch s {
+	case SGReadyBlocked:
+		return 1
+	case SGReadyRecommended:
+		return 3
+	case SGReadyForced:
+		return 4
+	default:
+		return 2
+	}
+}
+
+// HeatPump is a consumer controlled via SG-Ready inputs
+type HeatPump interface {
+	API
+	// GetSGReadyState returns the current SG-Ready state
+	GetSGReadyState() SGReadyState
+}
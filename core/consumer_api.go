@@ -0,0 +1,94 @@
+package core
+
+import (
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/consumer"
+	"github.com/evcc-io/evcc/core/loadpoint"
+)
+
+var _ consumer.API = (*Consumer)(nil)
+
+// Name returns the consumer's name
+func (c *Consumer) Name() string {
+	return c.Title
+}
+
+// GetEnabled returns the consumer's switching state
+func (c *Consumer) GetEnabled() bool {
+	c.Lock()
+	defer c.Unlock()
+	return c.enabled
+}
+
+// GetPower returns the consumer's current power
+func (c *Consumer) GetPower() float64 {
+	c.Lock()
+	defer c.Unlock()
+	return c.power
+}
+
+// HasMeter determines if a physical meter is attached
+func (c *Consumer) HasMeter() bool {
+	return c.meter != nil
+}
+
+// GetRemainingEnergy is the remaining daily energy demand in Wh
+func (c *Consumer) GetRemainingEnergy() float64 {
+	c.Lock()
+	defer c.Unlock()
+	return c.remainingEnergy()
+}
+
+// GetMode returns the operating mode
+func (c *Consumer) GetMode() api.ChargeMode {
+	c.Lock()
+	defer c.Unlock()
+	return c.Mode
+}
+
+// SetMode sets the operating mode
+func (c *Consumer) SetMode(mode api.ChargeMode) error {
+	c.Lock()
+	defer c.Unlock()
+
+	if _, err := api.ChargeModeString(mode.String()); err != nil {
+		return err
+	}
+
+	c.log.DEBUG.Println("set consumer mode:", string(mode))
+
+	if c.Mode != mode {
+		c.Mode = mode
+		c.publish("mode", mode)
+	}
+
+	return nil
+}
+
+// GetMinPower returns the minimum power the consumer can be operated at
+func (c *Consumer) GetMinPower() float64 {
+	if c.setpoint != nil {
+		return c.MinPower
+	}
+	return c.Power
+}
+
+// GetMaxPower returns the maximum power the consumer can be operated at
+func (c *Consumer) GetMaxPower() float64 {
+	return c.Power
+}
+
+// RemoteControl sets remote status demand
+func (c *Consumer) RemoteControl(source string, demand loadpoint.RemoteDemand) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.log.DEBUG.Println("remote demand:", demand)
+
+	if c.remoteDemand != demand {
+		c.remoteDemand = demand
+
+		c.publish("remoteDisabled", demand)
+		c.publish("remoteDisabledSource", source)
+	}
+}
@@ -0,0 +1,127 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/mock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestConsumer(t *testing.T) (*Consumer, *mock.MockCharger, *clock.Mock) {
+	ctrl := gomock.NewController(t)
+	charger := mock.NewMockCharger(ctrl)
+
+	clck := clock.NewMock()
+	clck.Set(time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC))
+
+	c := NewConsumer(util.NewLogger("foo"))
+	c.clock = clck
+	c.charger = charger
+	c.Power = 2000
+
+	return c, charger, clck
+}
+
+func TestConsumerSurplus(t *testing.T) {
+	c, charger, clck := newTestConsumer(t)
+	c.MinRuntime = 10 * time.Minute
+
+	// insufficient surplus
+	assert.Equal(t, 0.0, c.Update(-1500, false))
+
+	// sufficient surplus
+	charger.EXPECT().Enable(true).Return(nil)
+	assert.Equal(t, 2000.0, c.Update(-2500, false))
+	assert.True(t, c.GetEnabled())
+
+	// grid import during minimum runtime
+	clck.Add(5 * time.Minute)
+	assert.Equal(t, 0.0, c.Update(500, false))
+	assert.True(t, c.GetEnabled())
+
+	// grid import after minimum runtime
+	clck.Add(5 * time.Minute)
+	charger.EXPECT().Enable(false).Return(nil)
+	assert.Equal(t, -2000.0, c.Update(500, false))
+	assert.False(t, c.GetEnabled())
+}
+
+func TestConsumerRequired(t *testing.T) {
+	c, charger, clck := newTestConsumer(t)
+	c.MinRuntime = time.Hour
+
+	// cheap tariff
+	charger.EXPECT().Enable(true).Return(nil)
+	c.Update(1000, true)
+
+	// remote disable ignores minimum runtime
+	c.RemoteControl("test", loadpoint.RemoteSoftDisable)
+	charger.EXPECT().Enable(false).Return(nil)
+	c.Update(1000, true)
+
+	// mode now ignores remote soft disable
+	assert.NoError(t, c.SetMode(api.ModeNow))
+	charger.EXPECT().Enable(true).Return(nil)
+	c.Update(1000, false)
+
+	// mode off
+	clck.Add(time.Minute)
+	assert.NoError(t, c.SetMode(api.ModeOff))
+	charger.EXPECT().Enable(false).Return(nil)
+	c.Update(-5000, false)
+
+	// invalid mode rejected
+	assert.Error(t, c.SetMode("foo"))
+	assert.Equal(t, api.ModeOff, c.GetMode())
+}
+
+func TestConsumerEnergyDue(t *testing.T) {
+	c, charger, clck := newTestConsumer(t)
+	c.DailyEnergy = 3 // 1.5h at nominal power
+
+	// plenty of time left
+	c.Update(1000, false)
+	assert.Equal(t, 3000.0, c.GetRemainingEnergy())
+
+	// remaining demand can only just be met
+	clck.Set(time.Date(2022, 6, 1, 22, 30, 0, 0, time.UTC))
+	charger.EXPECT().Enable(true).Return(nil)
+	c.Update(1000, false)
+
+	// consumed energy reduces demand
+	clck.Add(30 * time.Minute)
+	c.Update(1000, false)
+	assert.Equal(t, 2000.0, c.GetRemainingEnergy())
+
+	// demand resets at midnight
+	clck.Set(time.Date(2022, 6, 2, 0, 0, 1, 0, time.UTC))
+	charger.EXPECT().Enable(false).Return(nil)
+	c.Update(1000, false)
+	assert.Equal(t, 3000.0, c.GetRemainingEnergy())
+}
+
+func TestConsumerSetpoint(t *testing.T) {
+	c, charger, _ := newTestConsumer(t)
+	c.MinPower = 500
+
+	var setpoint int64
+	c.setpoint = func(power int64) error {
+		setpoint = power
+		return nil
+	}
+
+	// partial surplus
+	charger.EXPECT().Enable(true).Return(nil)
+	assert.Equal(t, 800.0, c.Update(-800, false))
+	assert.Equal(t, int64(800), setpoint)
+
+	// surplus exceeds nominal power
+	assert.Equal(t, 1200.0, c.Update(-3000, false))
+	assert.Equal(t, int64(2000), setpoint)
+}
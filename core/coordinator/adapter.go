@@ -35,3 +35,7 @@ func (a *adapter) IdentifyVehicleByStatus(includeIdCapable bool) api.Vehicle {
 	available := a.c.availableDetectibleVehicles(a.lp, includeIdCapable)
 	return a.c.identifyVehicleByStatus(available)
 }
+
+func (a *adapter) AvailableVehicles() []api.Vehicle {
+	return a.c.availableVehicles(a.lp)
+}
@@ -2,8 +2,10 @@ package coordinator
 
 import (
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/geofence"
 	"github.com/evcc-io/evcc/core/loadpoint"
 	"github.com/evcc-io/evcc/util"
+	"golang.org/x/exp/slices"
 )
 
 // Coordinator coordinates vehicle access between loadpoints
@@ -11,6 +13,7 @@ type Coordinator struct {
 	log      *util.Logger
 	vehicles []api.Vehicle
 	tracked  map[api.Vehicle]loadpoint.API
+	home     *geofence.Geofence
 }
 
 // New creates a coordinator for a set of vehicles
@@ -26,9 +29,47 @@ func (c *Coordinator) GetVehicles() []api.Vehicle {
 	return c.vehicles
 }
 
+// SetHome sets the home geofence used for excluding vehicles far away from detection
+func (c *Coordinator) SetHome(home *geofence.Geofence) {
+	c.home = home
+}
+
+// far returns true if the vehicle reports a position outside the home geofence's approach distance
+func (c *Coordinator) far(vehicle api.Vehicle) bool {
+	vp, ok := vehicle.(api.VehiclePosition)
+	if !ok || !c.home.Configured() {
+		return false
+	}
+
+	lat, lon, err := vp.Position()
+	if err != nil {
+		c.log.DEBUG.Printf("vehicle position: %v (%s)", err, vehicle.Title())
+		return false
+	}
+
+	if c.home.Far(lat, lon) {
+		c.log.DEBUG.Printf("vehicle position: %.0fm from home (%s)", c.home.Distance(lat, lon), vehicle.Title())
+		return true
+	}
+
+	return false
+}
+
+// SetVehicles replaces the coordinated vehicles, releasing vehicles no longer available
+func (c *Coordinator) SetVehicles(vehicles []api.Vehicle) {
+	for v := range c.tracked {
+		if slices.IndexFunc(vehicles, func(vv api.Vehicle) bool { return vv == v }) < 0 {
+			delete(c.tracked, v)
+		}
+	}
+	c.vehicles = vehicles
+}
+
 func (c *Coordinator) acquire(owner loadpoint.API, vehicle api.Vehicle) {
 	if o, ok := c.tracked[vehicle]; ok && o != owner {
-		o.SetVehicle(nil)
+		if err := o.SetVehicle(nil); err != nil {
+			c.log.ERROR.Printf("release vehicle: %v", err)
+		}
 	}
 	c.tracked[vehicle] = owner
 }
@@ -38,7 +79,8 @@ func (c *Coordinator) release(vehicle api.Vehicle) {
 }
 
 // availableDetectibleVehicles is the list of vehicles that are currently not
-// associated to another loadpoint and have a status api that allows for detection
+// associated to another loadpoint and have a status api that allows for detection.
+// Vehicles reporting a position far away from home are excluded.
 func (c *Coordinator) availableDetectibleVehicles(owner loadpoint.API, includeIdCapable bool) []api.Vehicle {
 	var res []api.Vehicle
 
@@ -47,8 +89,8 @@ func (c *Coordinator) availableDetectibleVehicles(owner loadpoint.API, includeId
 		if _, ok := vv.(api.ChargeState); ok {
 			// available or associated to current loadpoint
 			if o, ok := c.tracked[vv]; o == owner || !ok {
-				// no identifiers configured or identifiers ignored
-				if includeIdCapable || len(vv.Identifiers()) == 0 {
+				// no identifiers configured or identifiers ignored, not far away from home
+				if (includeIdCapable || len(vv.Identifiers()) == 0) && !c.far(vv) {
 					res = append(res, vv)
 				}
 			}
@@ -58,6 +100,20 @@ func (c *Coordinator) availableDetectibleVehicles(owner loadpoint.API, includeId
 	return res
 }
 
+// availableVehicles is the list of vehicles that are currently not associated
+// to another loadpoint and not far away from home
+func (c *Coordinator) availableVehicles(owner loadpoint.API) []api.Vehicle {
+	var res []api.Vehicle
+
+	for _, vv := range c.vehicles {
+		if o, ok := c.tracked[vv]; (o == owner || !ok) && !c.far(vv) {
+			res = append(res, vv)
+		}
+	}
+
+	return res
+}
+
 // identifyVehicleByStatus finds active vehicle by charge state
 func (c *Coordinator) identifyVehicleByStatus(available []api.Vehicle) api.Vehicle {
 	var res api.Vehicle
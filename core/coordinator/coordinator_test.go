@@ -4,10 +4,12 @@ import (
 	"testing"
 
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/geofence"
 	"github.com/evcc-io/evcc/core/loadpoint"
 	"github.com/evcc-io/evcc/mock"
 	"github.com/evcc-io/evcc/util"
 	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestVehicleDetectByStatus(t *testing.T) {
@@ -65,3 +67,41 @@ func TestVehicleDetectByStatus(t *testing.T) {
 		}
 	}
 }
+
+type positionVehicle struct {
+	*mock.MockVehicle
+	*mock.MockChargeState
+	lat, lon float64
+}
+
+func (v *positionVehicle) Position() (float64, float64, error) {
+	return v.lat, v.lon, nil
+}
+
+func TestVehicleDetectExcludesFar(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	home := &geofence.Geofence{Latitude: 52.5163, Longitude: 13.3777}
+	assert.NoError(t, home.Validate())
+
+	v1 := &positionVehicle{mock.NewMockVehicle(ctrl), mock.NewMockChargeState(ctrl), 52.5170, 13.3780}
+	v2 := &positionVehicle{mock.NewMockVehicle(ctrl), mock.NewMockChargeState(ctrl), 48.1372, 11.5756}
+
+	for _, v := range []*positionVehicle{v1, v2} {
+		v.MockVehicle.EXPECT().Title().Return("v").AnyTimes()
+		v.MockVehicle.EXPECT().Identifiers().Return(nil).AnyTimes()
+	}
+
+	var lp loadpoint.API
+	c := New(util.NewLogger("foo"), []api.Vehicle{v1, v2})
+
+	// no geofence
+	assert.Len(t, c.availableDetectibleVehicles(lp, false), 2)
+
+	c.SetHome(home)
+	assert.Equal(t, []api.Vehicle{v1}, c.availableDetectibleVehicles(lp, false))
+
+	// both plugged, far vehicle ignored
+	v1.MockChargeState.EXPECT().Status().Return(api.StatusB, nil)
+	assert.Equal(t, v1, c.identifyVehicleByStatus(c.availableDetectibleVehicles(lp, false)))
+}
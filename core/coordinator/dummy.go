@@ -22,3 +22,7 @@ func (a *dummy) Release(v api.Vehicle) {}
 func (a *dummy) IdentifyVehicleByStatus(includeIdCapable bool) api.Vehicle {
 	return nil
 }
+
+func (a *dummy) AvailableVehicles() []api.Vehicle {
+	return nil
+}
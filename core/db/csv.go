@@ -0,0 +1,121 @@
+package db
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/util/locale"
+	"github.com/fatih/structs"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// writeHeader writes the localized captions of the struct's fields. Captions are looked up as <prefix>.csv.<field>.
+func writeHeader(ctx context.Context, ww *csv.Writer, prefix string, typ any) error {
+	localizer := locale.Localizer
+	if val := ctx.Value(locale.Locale).(string); val != "" {
+		localizer = i18n.NewLocalizer(locale.Bundle, val, locale.Language)
+	}
+
+	var row []string
+	for _, f := range structs.Fields(typ) {
+		csv := f.Tag("csv")
+		if csv == "-" {
+			continue
+		}
+
+		caption, err := localizer.Localize(&locale.Config{
+			MessageID: prefix + ".csv." + strings.ToLower(f.Name()),
+		})
+
+		if err != nil {
+			if csv != "" {
+				caption = csv
+			} else {
+				caption = f.Name()
+			}
+		}
+
+		row = append(row, caption)
+	}
+
+	return ww.Write(row)
+}
+
+func writeRow(ww *csv.Writer, mp *message.Printer, r any) error {
+	var row []string
+	for _, f := range structs.Fields(r) {
+		if f.Tag("csv") == "-" {
+			continue
+		}
+
+		var val string
+		format := f.Tag("format")
+
+		switch v := f.Value().(type) {
+		case float64:
+			switch format {
+			case "int":
+				val = mp.Sprint(number.Decimal(v, number.NoSeparator(), number.MaxFractionDigits(0)))
+			default:
+				val = mp.Sprint(number.Decimal(v, number.NoSeparator(), number.MaxFractionDigits(3)))
+			}
+		case time.Time:
+			if !v.IsZero() {
+				val = v.Local().Format("2006-01-02 15:04:05")
+			}
+		default:
+			val = fmt.Sprintf("%v", f.Value())
+		}
+
+		row = append(row, val)
+	}
+
+	return ww.Write(row)
+}
+
+// writeCsv writes rows of struct type typ as localized csv
+func writeCsv(ctx context.Context, w io.Writer, prefix string, typ any, rows []any) error {
+	if _, err := w.Write([]byte{0xEF, 0xBB, 0xBF}); err != nil {
+		return err
+	}
+
+	// get context language
+	lang := locale.Language
+	if language, ok := ctx.Value(locale.Locale).(string); ok && language != "" {
+		lang = language
+	}
+
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return err
+	}
+
+	ww := csv.NewWriter(w)
+
+	// set separator according to locale
+	if b, _ := tag.Base(); b.String() == language.German.String() {
+		ww.Comma = ';'
+	}
+
+	if err := writeHeader(ctx, ww, prefix, typ); err != nil {
+		return err
+	}
+
+	mp := message.NewPrinter(tag)
+	for _, r := range rows {
+		if err := writeRow(ww, mp, r); err != nil {
+			return err
+		}
+	}
+
+	ww.Flush()
+
+	return ww.Error()
+}
@@ -18,6 +18,21 @@ type Database interface {
 	Persist(session interface{})
 }
 
+// Migrate creates or updates the session and snapshot tables
+func Migrate() error {
+	var err error
+
+	// TODO deprecate
+	if table := serverdb.Prefix() + "transactions"; serverdb.Instance.Migrator().HasTable(table) {
+		err = serverdb.Instance.Migrator().RenameTable(table, new(Session))
+	}
+	if err == nil {
+		err = serverdb.Instance.AutoMigrate(new(Session), new(Snapshot))
+	}
+
+	return err
+}
+
 // New creates a database storage driver
 func New(name string) (*DB, error) {
 	db := &DB{
@@ -0,0 +1,140 @@
+package db
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	serverdb "github.com/evcc-io/evcc/server/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testDatabases(t *testing.T) map[string]string {
+	res := map[string]string{
+		"sqlite": filepath.Join(t.TempDir(), "evcc.db"),
+	}
+
+	// database servers are only tested if available
+	if dsn := os.Getenv("EVCC_TEST_POSTGRES_DSN"); dsn != "" {
+		res["postgres"] = dsn
+	}
+	if dsn := os.Getenv("EVCC_TEST_MYSQL_DSN"); dsn != "" {
+		res["mysql"] = dsn
+	}
+
+	return res
+}
+
+func TestSessions(t *testing.T) {
+	for driver, dsn := range testDatabases(t) {
+		t.Run(driver, func(t *testing.T) {
+			require.NoError(t, serverdb.NewInstance(driver, dsn, ""))
+			require.NoError(t, serverdb.SetPool(serverdb.Pool{MaxOpenConns: 2, ConnMaxLifetime: time.Minute}))
+
+			// start clean
+			_ = serverdb.Instance.Migrator().DropTable(new(Session))
+			require.NoError(t, Migrate())
+
+			db, err := New("lp-1")
+			require.NoError(t, err)
+
+			created := time.Now().Truncate(time.Second)
+
+			s := db.Session(1)
+			s.Created = created
+			s.ChargedEnergy = 5
+			db.Persist(s)
+
+			require.NotZero(t, s.ID)
+
+			var res []Session
+			require.NoError(t, serverdb.Instance.Find(&res).Error)
+			require.Len(t, res, 1)
+
+			assert.Equal(t, "lp-1", res[0].Loadpoint)
+			assert.Equal(t, 5.0, res[0].ChargedEnergy)
+			assert.True(t, created.Equal(res[0].Created), "created: %v", res[0].Created)
+
+			require.NoError(t, serverdb.Instance.Migrator().DropTable(new(Session)))
+		})
+	}
+}
+
+// transaction is the legacy session table
+type transaction struct {
+	ID        uint `gorm:"primarykey"`
+	Loadpoint string
+}
+
+func (transaction) TableName() string {
+	return "transactions"
+}
+
+func TestMigrateTransactions(t *testing.T) {
+	for driver, dsn := range testDatabases(t) {
+		t.Run(driver, func(t *testing.T) {
+			require.NoError(t, serverdb.NewInstance(driver, dsn, ""))
+
+			// start clean
+			_ = serverdb.Instance.Migrator().DropTable(new(Session), new(Snapshot), new(transaction))
+
+			require.NoError(t, serverdb.Instance.AutoMigrate(new(transaction)))
+			require.NoError(t, serverdb.Instance.Create(&transaction{Loadpoint: "lp-1"}).Error)
+
+			require.NoError(t, Migrate())
+
+			assert.False(t, serverdb.Instance.Migrator().HasTable("transactions"))
+
+			var res []Session
+			require.NoError(t, serverdb.Instance.Find(&res).Error)
+			require.Len(t, res, 1)
+			assert.Equal(t, "lp-1", res[0].Loadpoint)
+
+			// ids continue after migrated sessions
+			s := Session{Loadpoint: "lp-2"}
+			require.NoError(t, serverdb.Instance.Create(&s).Error)
+			assert.Greater(t, s.ID, res[0].ID)
+
+			require.NoError(t, serverdb.Instance.Migrator().DropTable(new(Session), new(Snapshot)))
+		})
+	}
+}
+
+func TestPrefixedSessions(t *testing.T) {
+	for driver, dsn := range testDatabases(t) {
+		t.Run(driver, func(t *testing.T) {
+			// unprefixed legacy table of other instance
+			require.NoError(t, serverdb.NewInstance(driver, dsn, ""))
+			_ = serverdb.Instance.Migrator().DropTable(new(transaction))
+			require.NoError(t, serverdb.Instance.AutoMigrate(new(transaction)))
+
+			for _, prefix := range []string{"site1_", "site2_"} {
+				require.NoError(t, serverdb.NewInstance(driver, dsn, prefix))
+
+				_ = serverdb.Instance.Migrator().DropTable(new(Session), new(Snapshot))
+				require.NoError(t, Migrate())
+
+				db, err := New(prefix + "lp")
+				require.NoError(t, err)
+				db.Persist(db.Session(1))
+			}
+
+			assert.True(t, serverdb.Instance.Migrator().HasTable("transactions"))
+
+			for _, prefix := range []string{"site1_", "site2_"} {
+				require.NoError(t, serverdb.NewInstance(driver, dsn, prefix))
+
+				var res []Session
+				require.NoError(t, serverdb.Instance.Find(&res).Error)
+				require.Len(t, res, 1)
+				assert.Equal(t, prefix+"lp", res[0].Loadpoint)
+
+				require.NoError(t, serverdb.Instance.Migrator().DropTable(new(Session), new(Snapshot)))
+			}
+
+			require.NoError(t, serverdb.Instance.Migrator().DropTable(new(transaction)))
+		})
+	}
+}
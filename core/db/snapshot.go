@@ -0,0 +1,216 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	serverdb "github.com/evcc-io/evcc/server/db"
+)
+
+// Snapshot events
+const (
+	SnapshotStart = "start" // charging session started
+	SnapshotStop  = "stop"  // vehicle disconnected after charging
+)
+
+// Snapshot is a vehicle's odometer and soc at the start or end of a charging session
+type Snapshot struct {
+	ID            uint      `json:"-" gorm:"primarykey"`
+	Created       time.Time `json:"created"`
+	Vehicle       string    `json:"vehicle" gorm:"index"`
+	Event         string    `json:"event"`
+	Odometer      float64   `json:"odometer"`                                // km
+	SoC           float64   `json:"soc"`                                     // %
+	Capacity      float64   `json:"capacity"`                                // kWh
+	ChargedEnergy float64   `json:"chargedEnergy" gorm:"column:charged_kwh"` // session energy at stop (kWh)
+}
+
+// Trip is the driving before a charging session and the charging session itself
+type Trip struct {
+	Vehicle       string    `json:"vehicle"`
+	Departed      time.Time `json:"departed"` // end of previous charging session
+	Arrived       time.Time `json:"arrived"`  // start of charging session
+	Finished      time.Time `json:"finished"` // end of charging session
+	Odometer      float64   `json:"odometer" csv:"Mileage (km)" format:"int"`
+	Distance      float64   `json:"distance" csv:"Distance (km)" format:"int"`
+	Consumption   float64   `json:"consumption" csv:"Consumption (kWh/100km)"`
+	ChargedEnergy float64   `json:"chargedEnergy" csv:"Charged Energy (kWh)"`
+	StoredEnergy  float64   `json:"storedEnergy" csv:"Stored Energy (kWh)"`
+	Losses        float64   `json:"losses" csv:"Losses (%)"`
+}
+
+// Trips is a list of trips
+type Trips []Trip
+
+var _ api.CsvWriter = (*Trips)(nil)
+
+// WriteCsv implements the api.CsvWriter interface
+func (t *Trips) WriteCsv(ctx context.Context, w io.Writer) error {
+	rows := make([]any, 0, len(*t))
+	for _, r := range *t {
+		rows = append(rows, r)
+	}
+
+	return writeCsv(ctx, w, "trips", Trip{}, rows)
+}
+
+// Statistic is the vehicle's driving and charging summary
+type Statistic struct {
+	Vehicle       string  `json:"vehicle"`
+	Sessions      int     `json:"sessions"`
+	Distance      float64 `json:"distance" csv:"Distance (km)" format:"int"`
+	Consumption   float64 `json:"consumption" csv:"Consumption (kWh/100km)"`
+	ChargedEnergy float64 `json:"chargedEnergy" csv:"Charged Energy (kWh)"`
+	StoredEnergy  float64 `json:"storedEnergy" csv:"Stored Energy (kWh)"`
+	Losses        float64 `json:"losses" csv:"Losses (%)"`
+}
+
+// Statistics is a list of vehicle statistics
+type Statistics []Statistic
+
+var _ api.CsvWriter = (*Statistics)(nil)
+
+// WriteCsv implements the api.CsvWriter interface
+func (t *Statistics) WriteCsv(ctx context.Context, w io.Writer) error {
+	rows := make([]any, 0, len(*t))
+	for _, r := range *t {
+		rows = append(rows, r)
+	}
+
+	return writeCsv(ctx, w, "statistics", Statistic{}, rows)
+}
+
+func snapshots(vehicle string) ([]Snapshot, error) {
+	if serverdb.Instance == nil {
+		return nil, errors.New("database offline")
+	}
+
+	var res []Snapshot
+	tx := serverdb.Instance.Order("vehicle, created")
+	if vehicle != "" {
+		tx = tx.Where(&Snapshot{Vehicle: vehicle})
+	}
+
+	err := tx.Find(&res).Error
+	return res, err
+}
+
+// TripsFromSnapshots derives trips from snapshots ordered by vehicle and time.
+// Driving distance and consumption are calculated between the end of the previous charging session and the start of the next,
+// charging losses from the charged energy and the soc increase during the session.
+func TripsFromSnapshots(snapshots []Snapshot) Trips {
+	res := make(Trips, 0)
+
+	var start, stop *Snapshot
+	for i := range snapshots {
+		s := &snapshots[i]
+
+		// vehicle changed
+		if start != nil && start.Vehicle != s.Vehicle || stop != nil && stop.Vehicle != s.Vehicle {
+			start, stop = nil, nil
+		}
+
+		switch s.Event {
+		case SnapshotStart:
+			start = s
+
+		case SnapshotStop:
+			if start == nil {
+				stop = s
+				continue
+			}
+
+			t := Trip{
+				Vehicle:       s.Vehicle,
+				Arrived:       start.Created,
+				Finished:      s.Created,
+				Odometer:      start.Odometer,
+				ChargedEnergy: s.ChargedEnergy,
+			}
+
+			if s.Capacity > 0 && s.SoC > start.SoC {
+				t.StoredEnergy = (s.SoC - start.SoC) / 100 * s.Capacity
+			}
+
+			if t.ChargedEnergy > 0 && t.StoredEnergy > 0 {
+				t.Losses = 100 * (t.ChargedEnergy - t.StoredEnergy) / t.ChargedEnergy
+			}
+
+			if stop != nil {
+				t.Departed = stop.Created
+
+				// odometer not available if zero
+				if stop.Odometer > 0 && start.Odometer > stop.Odometer {
+					t.Distance = start.Odometer - stop.Odometer
+
+					if start.Capacity > 0 && stop.SoC > start.SoC {
+						consumed := (stop.SoC - start.SoC) / 100 * start.Capacity
+						t.Consumption = 100 * consumed / t.Distance
+					}
+				}
+			}
+
+			res = append(res, t)
+			start, stop = nil, s
+		}
+	}
+
+	return res
+}
+
+// StatisticsFromTrips summarizes trips per vehicle
+func StatisticsFromTrips(trips Trips) Statistics {
+	res := make(Statistics, 0)
+
+	// distance used for consumption
+	var consumed, consumedDistance float64
+
+	for _, t := range trips {
+		if len(res) == 0 || res[len(res)-1].Vehicle != t.Vehicle {
+			res = append(res, Statistic{Vehicle: t.Vehicle})
+			consumed, consumedDistance = 0, 0
+		}
+
+		s := &res[len(res)-1]
+
+		s.Sessions++
+		s.Distance += t.Distance
+		s.ChargedEnergy += t.ChargedEnergy
+		s.StoredEnergy += t.StoredEnergy
+
+		if t.Consumption > 0 {
+			consumed += t.Consumption * t.Distance / 100
+			consumedDistance += t.Distance
+			s.Consumption = 100 * consumed / consumedDistance
+		}
+
+		if s.ChargedEnergy > 0 && s.StoredEnergy > 0 {
+			s.Losses = 100 * (s.ChargedEnergy - s.StoredEnergy) / s.ChargedEnergy
+		}
+	}
+
+	return res
+}
+
+// VehicleTrips returns the vehicle's trips or all vehicles' trips if vehicle is empty
+func VehicleTrips(vehicle string) (Trips, error) {
+	res, err := snapshots(vehicle)
+	if err != nil {
+		return nil, err
+	}
+
+	return TripsFromSnapshots(res), nil
+}
+
+// VehicleStatistics returns the driving and charging statistics per vehicle
+func VehicleStatistics() (Statistics, error) {
+	trips, err := VehicleTrips("")
+	if err != nil {
+		return nil, err
+	}
+
+	return StatisticsFromTrips(trips), nil
+}
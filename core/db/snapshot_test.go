@@ -0,0 +1,97 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	serverdb "github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/util/locale"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestTripsFromSnapshots(t *testing.T) {
+	t0 := time.Date(2022, 10, 1, 18, 0, 0, 0, time.UTC)
+
+	snapshots := []Snapshot{
+		// first session without previous trip
+		{Created: t0, Vehicle: "a", Event: SnapshotStart, Odometer: 1000, SoC: 20, Capacity: 50},
+		{Created: t0.Add(4 * time.Hour), Vehicle: "a", Event: SnapshotStop, Odometer: 1000, SoC: 80, Capacity: 50, ChargedEnergy: 33.3},
+		// 150km using 30% of 50kWh
+		{Created: t0.Add(24 * time.Hour), Vehicle: "a", Event: SnapshotStart, Odometer: 1150, SoC: 50, Capacity: 50},
+		{Created: t0.Add(26 * time.Hour), Vehicle: "a", Event: SnapshotStop, Odometer: 1150, SoC: 70, Capacity: 50, ChargedEnergy: 11},
+		// other vehicle without odometer
+		{Created: t0, Vehicle: "b", Event: SnapshotStart, SoC: 40, Capacity: 40},
+		{Created: t0.Add(time.Hour), Vehicle: "b", Event: SnapshotStop, SoC: 50, Capacity: 40, ChargedEnergy: 5},
+	}
+
+	trips := TripsFromSnapshots(snapshots)
+	require.Len(t, trips, 3)
+
+	assert.Equal(t, 0.0, trips[0].Distance)
+	assert.InDelta(t, 30, trips[0].StoredEnergy, 1e-6)
+	assert.InDelta(t, 9.91, trips[0].Losses, 0.01)
+
+	assert.True(t, trips[1].Departed.Equal(t0.Add(4*time.Hour)))
+	assert.Equal(t, 150.0, trips[1].Distance)
+	assert.InDelta(t, 10, trips[1].Consumption, 1e-6)
+	assert.InDelta(t, 10, trips[1].StoredEnergy, 1e-6)
+	assert.InDelta(t, 9.09, trips[1].Losses, 0.01)
+
+	assert.Equal(t, "b", trips[2].Vehicle)
+	assert.True(t, trips[2].Departed.IsZero())
+	assert.InDelta(t, 20, trips[2].Losses, 1e-6)
+
+	stats := StatisticsFromTrips(trips)
+	require.Len(t, stats, 2)
+
+	assert.Equal(t, "a", stats[0].Vehicle)
+	assert.Equal(t, 2, stats[0].Sessions)
+	assert.Equal(t, 150.0, stats[0].Distance)
+	assert.InDelta(t, 10, stats[0].Consumption, 1e-6)
+	assert.InDelta(t, 44.3, stats[0].ChargedEnergy, 1e-6)
+	assert.InDelta(t, 40, stats[0].StoredEnergy, 1e-6)
+
+	assert.Equal(t, 1, stats[1].Sessions)
+	assert.Equal(t, 0.0, stats[1].Consumption)
+}
+
+func TestVehicleTrips(t *testing.T) {
+	require.NoError(t, serverdb.NewInstance("sqlite", filepath.Join(t.TempDir(), "evcc.db"), ""))
+	require.NoError(t, Migrate())
+
+	db, err := New("lp-1")
+	require.NoError(t, err)
+
+	t0 := time.Now().Truncate(time.Second)
+	db.Persist(&Snapshot{Created: t0, Vehicle: "a", Event: SnapshotStart, Odometer: 1000, SoC: 20, Capacity: 50})
+	db.Persist(&Snapshot{Created: t0.Add(time.Hour), Vehicle: "a", Event: SnapshotStop, Odometer: 1000, SoC: 40, Capacity: 50, ChargedEnergy: 11})
+	db.Persist(&Snapshot{Created: t0, Vehicle: "b", Event: SnapshotStart, SoC: 20, Capacity: 50})
+
+	res, err := VehicleTrips("a")
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	assert.Equal(t, 1000.0, res[0].Odometer)
+
+	stats, err := VehicleStatistics()
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+
+	// captions from csv tags
+	locale.Bundle = i18n.NewBundle(language.English)
+	locale.Localizer = i18n.NewLocalizer(locale.Bundle)
+
+	var b bytes.Buffer
+	ctx := context.WithValue(context.Background(), locale.Locale, "en")
+	require.NoError(t, stats.WriteCsv(ctx, &b))
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[1], "a,1,0,0,11,10,")
+}
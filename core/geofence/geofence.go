@@ -0,0 +1,69 @@
+package geofence
+
+import (
+	"errors"
+	"math"
+)
+
+const (
+	earthRadius = 6371e3 // m
+
+	defaultRadius   = 200  // m
+	defaultApproach = 5000 // m
+)
+
+// Geofence is a circular area around the home location
+type Geofence struct {
+	Latitude  float64 `mapstructure:"latitude"`
+	Longitude float64 `mapstructure:"longitude"`
+	Radius    float64 `mapstructure:"radius"`   // home radius (m)
+	Approach  float64 `mapstructure:"approach"` // approaching vehicles are tracked within this distance (m)
+}
+
+// Configured returns true if the geofence has a location
+func (g *Geofence) Configured() bool {
+	return g != nil && (g.Latitude != 0 || g.Longitude != 0)
+}
+
+// Validate checks the location and applies default distances
+func (g *Geofence) Validate() error {
+	if math.Abs(g.Latitude) > 90 || math.Abs(g.Longitude) > 180 {
+		return errors.New("invalid home location")
+	}
+
+	if g.Radius <= 0 {
+		g.Radius = defaultRadius
+	}
+
+	if g.Approach < g.Radius {
+		g.Approach = defaultApproach
+		if g.Approach < g.Radius {
+			g.Approach = g.Radius
+		}
+	}
+
+	return nil
+}
+
+// Distance returns the great circle distance of the position from home in meters
+func (g *Geofence) Distance(lat, lon float64) float64 {
+	rad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := rad(lat - g.Latitude)
+	dLon := rad(lon - g.Longitude)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(rad(g.Latitude))*math.Cos(rad(lat))*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// Home returns true if the position is within the home radius
+func (g *Geofence) Home(lat, lon float64) bool {
+	return g.Distance(lat, lon) <= g.Radius
+}
+
+// Far returns true if the position is outside the approach distance
+func (g *Geofence) Far(lat, lon float64) bool {
+	return g.Distance(lat, lon) > g.Approach
+}
@@ -0,0 +1,39 @@
+package geofence
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistance(t *testing.T) {
+	// Brandenburg Gate
+	g := &Geofence{Latitude: 52.5163, Longitude: 13.3777}
+	assert.NoError(t, g.Validate())
+
+	assert.Equal(t, 0.0, g.Distance(g.Latitude, g.Longitude))
+
+	// Berlin Central Station ~1.2km
+	d := g.Distance(52.5251, 13.3694)
+	assert.InDelta(t, 1130, d, 50)
+
+	assert.True(t, g.Home(52.5170, 13.3780))
+	assert.False(t, g.Home(52.5251, 13.3694))
+	assert.False(t, g.Far(52.5251, 13.3694))
+
+	// Munich
+	assert.True(t, g.Far(48.1372, 11.5756))
+}
+
+func TestValidate(t *testing.T) {
+	var g *Geofence
+	assert.False(t, g.Configured())
+
+	g = &Geofence{Latitude: 91}
+	assert.Error(t, g.Validate())
+
+	g = &Geofence{Latitude: 52, Longitude: 13, Radius: 10000}
+	assert.NoError(t, g.Validate())
+	assert.True(t, g.Configured())
+	assert.Equal(t, 10000.0, g.Approach)
+}
@@ -0,0 +1,246 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/avast/retry-go/v3"
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/consumer"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/provider"
+	"github.com/evcc-io/evcc/util"
+)
+
+// HeatPump is a heat pump controlled via its two SG-Ready relay inputs.
+// Depending on site surplus and tariff it is switched between normal,
+// recommended-on and forced-on operation.
+type HeatPump struct {
+	sync.Mutex // guard status
+	log        *util.Logger
+	clock      clock.Clock       // mockable time
+	uiChan     chan<- util.Param // client push messages
+
+	// exposed public configuration
+	Title            string          `mapstructure:"title"`         // UI title
+	MeterRef         string          `mapstructure:"meter"`         // Consumption meter
+	Mode             api.ChargeMode  `mapstructure:"mode"`          // Operating mode
+	Power            float64         `mapstructure:"power"`         // Typical power when boosted
+	Relay1           provider.Config `mapstructure:"relay1"`        // SG-Ready input 1
+	Relay2           provider.Config `mapstructure:"relay2"`        // SG-Ready input 2
+	RecommendedPower float64         `mapstructure:"recommended"`   // Surplus required for recommended-on
+	ForcedPower      float64         `mapstructure:"forced"`        // Surplus required for forced-on, zero to disable
+	GuardDuration    time.Duration   `mapstructure:"guardDuration"` // Minimum hold time of SG-Ready state
+
+	meter  api.Meter
+	relay1 func(bool) error
+	relay2 func(bool) error
+
+	// cached state
+	state        consumer.SGReadyState  // SG-Ready state
+	power        float64                // Current power
+	switched     time.Time              // Last state change
+	remoteDemand loadpoint.RemoteDemand // External status demand
+}
+
+// NewHeatPumpFromConfig creates a new SG-Ready heat pump
+func NewHeatPumpFromConfig(log *util.Logger, cp configProvider, other map[string]interface{}) (*HeatPump, error) {
+	hp := NewHeatPump(log)
+	if err := util.DecodeOther(other, hp); err != nil {
+		return nil, err
+	}
+
+	if hp.Power <= 0 {
+		return nil, errors.New("missing power")
+	}
+
+	if hp.RecommendedPower == 0 {
+		hp.RecommendedPower = hp.Power
+	}
+
+	if hp.ForcedPower > 0 && hp.ForcedPower < hp.RecommendedPower {
+		return nil, errors.New("forced power must be larger than recommended power")
+	}
+
+	var err error
+	if hp.MeterRef != "" {
+		if hp.meter, err = cp.Meter(hp.MeterRef); err != nil {
+			return nil, err
+		}
+	}
+
+	if hp.relay1, err = provider.NewBoolSetterFromConfig("relay1", hp.Relay1); err != nil {
+		return nil, fmt.Errorf("relay1: %w", err)
+	}
+
+	if hp.relay2, err = provider.NewBoolSetterFromConfig("relay2", hp.Relay2); err != nil {
+		return nil, fmt.Errorf("relay2: %w", err)
+	}
+
+	return hp, nil
+}
+
+// NewHeatPump creates a HeatPump with sane defaults
+func NewHeatPump(log *util.Logger) *HeatPump {
+	hp := &HeatPump{
+		log:           log,
+		clock:         clock.New(),
+		Mode:          api.ModePV,
+		GuardDuration: 10 * time.Minute,
+	}
+
+	return hp
+}
+
+// publish sends values to UI and databases
+func (hp *HeatPump) publish(key string, val interface{}) {
+	if hp.uiChan != nil {
+		hp.uiChan <- util.Param{Key: key, Val: val}
+	}
+}
+
+// Prepare heat pump by applying normal operation
+func (hp *HeatPump) Prepare(uiChan chan<- util.Param) {
+	hp.uiChan = uiChan
+
+	hp.publish("title", hp.Title)
+	hp.publish("mode", hp.Mode)
+	hp.publish("minPower", hp.GetMinPower())
+	hp.publish("maxPower", hp.GetMaxPower())
+
+	if err := hp.setState(consumer.SGReadyNormal); err != nil {
+		hp.log.ERROR.Printf("sg-ready: %v", err)
+	}
+}
+
+// dumpConfig logs the heat pump configuration
+func (hp *HeatPump) dumpConfig() {
+	hp.log.INFO.Printf("  mode:        %s", hp.GetMode())
+	hp.log.INFO.Printf("  sg-ready:    recommended %.0fW forced %.0fW guard %v", hp.RecommendedPower, hp.ForcedPower, hp.GuardDuration)
+	hp.log.INFO.Printf("  meters:      power %s", presence[hp.HasMeter()])
+}
+
+// setState applies the SG-Ready state to the relay inputs
+func (hp *HeatPump) setState(state consumer.SGReadyState) error {
+	r1, r2 := state.Relays()
+
+	err := hp.relay1(r1)
+	if err == nil {
+		err = hp.relay2(r2)
+	}
+
+	if err == nil {
+		hp.log.DEBUG.Printf("sg-ready: %s", state)
+		hp.state = state
+		hp.publish("sgReady", state)
+		hp.publish("sgReadyState", state.Int())
+		hp.publish("enabled", hp.boosted())
+	}
+
+	return err
+}
+
+// boosted returns true if the heat pump is asked to consume additional energy
+func (hp *HeatPump) boosted() bool {
+	return hp.state == consumer.SGReadyRecommended || hp.state == consumer.SGReadyForced
+}
+
+// updatePower updates the heat pump's power from meter or nominal values
+func (hp *HeatPump) updatePower() {
+	switch {
+	case hp.meter != nil:
+		err := retry.Do(func() error {
+			value, err := hp.meter.CurrentPower()
+			if err == nil {
+				hp.power = value
+			}
+			return err
+		}, retryOptions...)
+
+		if err != nil {
+			hp.log.ERROR.Printf("heat pump meter: %v", err)
+		}
+
+	case hp.boosted():
+		hp.power = hp.Power
+
+	default:
+		hp.power = 0
+	}
+
+	hp.log.DEBUG.Printf("heat pump power: %.0fW", hp.power)
+	hp.publish("power", hp.power)
+}
+
+// targetState determines the SG-Ready state. Required states are not subject to guard duration.
+func (hp *HeatPump) targetState(sitePower float64, cheap bool) (consumer.SGReadyState, bool) {
+	// power available to the heat pump
+	available := hp.power - sitePower
+
+	switch {
+	case hp.remoteDemand == loadpoint.RemoteHardDisable:
+		return consumer.SGReadyBlocked, true
+
+	case hp.Mode == api.ModeOff:
+		return consumer.SGReadyNormal, true
+
+	case hp.Mode == api.ModeNow:
+		return consumer.SGReadyForced, true
+
+	// Sunny Home Manager
+	case hp.remoteDemand == loadpoint.RemoteSoftDisable:
+		return consumer.SGReadyNormal, true
+
+	case hp.ForcedPower > 0 && available >= hp.ForcedPower:
+		return consumer.SGReadyForced, false
+
+	case cheap:
+		hp.log.DEBUG.Println("cheap tariff")
+		return consumer.SGReadyRecommended, false
+
+	case available >= hp.RecommendedPower:
+		return consumer.SGReadyRecommended, false
+
+	default:
+		return consumer.SGReadyNormal, false
+	}
+}
+
+// Update executes the heat pump control logic and returns the expected change of site power
+func (hp *HeatPump) Update(sitePower float64, cheap bool) float64 {
+	hp.Lock()
+	defer hp.Unlock()
+
+	hp.updatePower()
+
+	state, required := hp.targetState(sitePower, cheap)
+	if state == hp.state {
+		return 0
+	}
+
+	if remaining := (hp.GuardDuration - hp.clock.Since(hp.switched)).Truncate(time.Second); remaining > 0 && !required {
+		hp.log.DEBUG.Printf("sg-ready: %s, guard duration: %v remaining", hp.state, remaining)
+		return 0
+	}
+
+	boosted := hp.boosted()
+
+	if err := hp.setState(state); err != nil {
+		hp.log.ERROR.Printf("sg-ready: %v", err)
+		return 0
+	}
+
+	hp.switched = hp.clock.Now()
+
+	switch {
+	case !boosted && hp.boosted():
+		return hp.Power - hp.power
+	case boosted && !hp.boosted():
+		return -hp.power
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,96 @@
+package core
+
+import (
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/consumer"
+	"github.com/evcc-io/evcc/core/loadpoint"
+)
+
+var _ consumer.HeatPump = (*HeatPump)(nil)
+
+// Name returns the heat pump's name
+func (hp *HeatPump) Name() string {
+	return hp.Title
+}
+
+// GetSGReadyState returns the current SG-Ready state
+func (hp *HeatPump) GetSGReadyState() consumer.SGReadyState {
+	hp.Lock()
+	defer hp.Unlock()
+	return hp.state
+}
+
+// GetEnabled returns true if the heat pump is asked to consume additional energy
+func (hp *HeatPump) GetEnabled() bool {
+	hp.Lock()
+	defer hp.Unlock()
+	return hp.boosted()
+}
+
+// GetPower returns the heat pump's current power
+func (hp *HeatPump) GetPower() float64 {
+	hp.Lock()
+	defer hp.Unlock()
+	return hp.power
+}
+
+// HasMeter determines if a physical meter is attached
+func (hp *HeatPump) HasMeter() bool {
+	return hp.meter != nil
+}
+
+// GetRemainingEnergy is the remaining daily energy demand in Wh. Heat pumps don't have a fixed demand.
+func (hp *HeatPump) GetRemainingEnergy() float64 {
+	return 0
+}
+
+// GetMode returns the operating mode
+func (hp *HeatPump) GetMode() api.ChargeMode {
+	hp.Lock()
+	defer hp.Unlock()
+	return hp.Mode
+}
+
+// SetMode sets the operating mode
+func (hp *HeatPump) SetMode(mode api.ChargeMode) error {
+	hp.Lock()
+	defer hp.Unlock()
+
+	if _, err := api.ChargeModeString(mode.String()); err != nil {
+		return err
+	}
+
+	hp.log.DEBUG.Println("set heat pump mode:", string(mode))
+
+	if hp.Mode != mode {
+		hp.Mode = mode
+		hp.publish("mode", mode)
+	}
+
+	return nil
+}
+
+// GetMinPower returns the minimum power the heat pump can be operated at
+func (hp *HeatPump) GetMinPower() float64 {
+	return hp.Power
+}
+
+// GetMaxPower returns the maximum power the heat pump can be operated at
+func (hp *HeatPump) GetMaxPower() float64 {
+	return hp.Power
+}
+
+// RemoteControl sets remote status demand
+func (hp *HeatPump) RemoteControl(source string, demand loadpoint.RemoteDemand) {
+	hp.Lock()
+	defer hp.Unlock()
+
+	hp.log.DEBUG.Println("remote demand:", demand)
+
+	if hp.remoteDemand != demand {
+		hp.remoteDemand = demand
+
+		hp.publish("remoteDisabled", demand)
+		hp.publish("remoteDisabledSource", source)
+	}
+}
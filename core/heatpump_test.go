@@ -0,0 +1,101 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/consumer"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestHeatPump() (*HeatPump, *[2]bool, *clock.Mock) {
+	clck := clock.NewMock()
+
+	var relays [2]bool
+
+	hp := NewHeatPump(util.NewLogger("foo"))
+	hp.clock = clck
+	hp.Power = 1000
+	hp.RecommendedPower = 1000
+	hp.ForcedPower = 3000
+	hp.relay1 = func(b bool) error { relays[0] = b; return nil }
+	hp.relay2 = func(b bool) error { relays[1] = b; return nil }
+
+	hp.Prepare(nil)
+
+	return hp, &relays, clck
+}
+
+func TestSGReadyRelays(t *testing.T) {
+	tc := []struct {
+		state  consumer.SGReadyState
+		r1, r2 bool
+	}{
+		{consumer.SGReadyBlocked, true, false},
+		{consumer.SGReadyNormal, false, false},
+		{consumer.SGReadyRecommended, false, true},
+		{consumer.SGReadyForced, true, true},
+	}
+
+	for _, tc := range tc {
+		r1, r2 := tc.state.Relays()
+		assert.Equal(t, tc.r1, r1, tc.state)
+		assert.Equal(t, tc.r2, r2, tc.state)
+	}
+}
+
+func TestHeatPumpSurplus(t *testing.T) {
+	hp, relays, clck := newTestHeatPump()
+	assert.Equal(t, consumer.SGReadyNormal, hp.GetSGReadyState())
+	assert.Equal(t, [2]bool{false, false}, *relays)
+
+	// recommended
+	assert.Equal(t, 1000.0, hp.Update(-1500, false))
+	assert.Equal(t, consumer.SGReadyRecommended, hp.GetSGReadyState())
+	assert.Equal(t, [2]bool{false, true}, *relays)
+
+	// forced during guard duration
+	assert.Equal(t, 0.0, hp.Update(-3000, false))
+	assert.Equal(t, consumer.SGReadyRecommended, hp.GetSGReadyState())
+
+	// forced after guard duration
+	clck.Add(hp.GuardDuration)
+	assert.Equal(t, 0.0, hp.Update(-3000, false))
+	assert.Equal(t, consumer.SGReadyForced, hp.GetSGReadyState())
+	assert.Equal(t, [2]bool{true, true}, *relays)
+
+	// grid import
+	clck.Add(hp.GuardDuration)
+	assert.Equal(t, -1000.0, hp.Update(500, false))
+	assert.Equal(t, consumer.SGReadyNormal, hp.GetSGReadyState())
+}
+
+func TestHeatPumpRequired(t *testing.T) {
+	hp, relays, clck := newTestHeatPump()
+
+	// cheap tariff
+	hp.Update(0, true)
+	assert.Equal(t, consumer.SGReadyRecommended, hp.GetSGReadyState())
+
+	// utility lock ignores guard duration
+	hp.RemoteControl("test", loadpoint.RemoteHardDisable)
+	hp.Update(0, true)
+	assert.Equal(t, consumer.SGReadyBlocked, hp.GetSGReadyState())
+	assert.Equal(t, [2]bool{true, false}, *relays)
+
+	// mode now
+	hp.RemoteControl("test", loadpoint.RemoteEnable)
+	hp.SetMode(api.ModeNow)
+	hp.Update(0, false)
+	assert.Equal(t, consumer.SGReadyForced, hp.GetSGReadyState())
+
+	// mode off
+	clck.Add(time.Minute)
+	hp.SetMode(api.ModeOff)
+	hp.Update(-5000, false)
+	assert.Equal(t, consumer.SGReadyNormal, hp.GetSGReadyState())
+}
@@ -46,3 +46,10 @@ func vehicleTitles(vehicles []api.Vehicle) []string {
 		return v.Title()
 	})
 }
+
+// containsVehicle returns true if the vehicle is part of the list of vehicles
+func containsVehicle(vehicles []api.Vehicle, vehicle api.Vehicle) bool {
+	return lo.ContainsBy(vehicles, func(v api.Vehicle) bool {
+		return v == vehicle
+	})
+}
@@ -11,13 +11,18 @@ import (
 	"time"
 
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/api/store"
 	"github.com/evcc-io/evcc/core/coordinator"
 	"github.com/evcc-io/evcc/core/db"
+	"github.com/evcc-io/evcc/core/geofence"
 	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/signature"
 	"github.com/evcc-io/evcc/core/soc"
 	"github.com/evcc-io/evcc/core/wrapper"
 	"github.com/evcc-io/evcc/provider"
 	"github.com/evcc-io/evcc/push"
+	serverdb "github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/server/db/settings"
 	"github.com/evcc-io/evcc/util"
 
 	evbus "github.com/asaskevich/EventBus"
@@ -51,6 +56,9 @@ const (
 	minActiveCurrent      = 1.0 // minimum current at which a phase is treated as active
 	vehicleDetectInterval = 1 * time.Minute
 	vehicleDetectDuration = 10 * time.Minute
+	socLimitRetryDelay    = 1 * time.Minute // retry vehicle charge limit if vehicle is not ready
+	socLimitMaxAttempts   = 10              // give up vehicle charge limit after failed attempts
+	remoteLimitTimeout    = 5 * time.Minute // remote power limit expires unless repeated
 
 	guardGracePeriod = 10 * time.Second // allow out of sync during this timespan
 )
@@ -60,18 +68,19 @@ var elapsed = time.Unix(0, 1)
 
 // PollConfig defines the vehicle polling mode and interval
 type PollConfig struct {
-	Mode     string        `mapstructure:"mode"`     // polling mode charging (default), connected, always
+	Mode     string        `mapstructure:"mode"`     // polling mode charging (default), connected, home, always
 	Interval time.Duration `mapstructure:"interval"` // interval when not charging
 }
 
 // SoCConfig defines soc settings, estimation and update behaviour
 type SoCConfig struct {
-	Poll     PollConfig `mapstructure:"poll"`
-	Estimate *bool      `mapstructure:"estimate"`
-	Min_     int        `mapstructure:"min"`    // TODO deprecated
-	Target_  int        `mapstructure:"target"` // TODO deprecated
-	min      int        // Default minimum SoC, guarded by mutex
-	target   int        // Default target SoC, guarded by mutex
+	Poll         PollConfig `mapstructure:"poll"`
+	Estimate     *bool      `mapstructure:"estimate"`
+	VehicleLimit bool       `mapstructure:"vehicleLimit"` // apply target soc as vehicle charge limit
+	Min_         int        `mapstructure:"min"`          // TODO deprecated
+	Target_      int        `mapstructure:"target"`       // TODO deprecated
+	min          int        // Default minimum SoC, guarded by mutex
+	target       int        // Default target SoC, guarded by mutex
 }
 
 // Poll modes
@@ -79,6 +88,7 @@ const (
 	pollCharging  = "charging"
 	pollConnected = "connected"
 	pollAlways    = "always"
+	pollHome      = "home"
 
 	pollInterval = 60 * time.Minute
 )
@@ -112,12 +122,15 @@ type LoadPoint struct {
 	SoC               SoCConfig
 	Enable, Disable   ThresholdConfig
 	ResetOnDisconnect bool `mapstructure:"resetOnDisconnect"`
+	Signature         bool `mapstructure:"signature"` // identify vehicle by charging power signature
 	onDisconnect      api.ActionConfig
 	targetEnergy      float64 // Target charge energy for dumb vehicles
 
 	MinCurrent    float64       // PV mode: start current	Min+PV mode: min current
 	MaxCurrent    float64       // Max allowed current. Physically ensured by the charger
 	GuardDuration time.Duration // charger enable/disable minimum holding time
+	Precondition  time.Duration // start vehicle climatisation before target time
+	WakeUp        WakeUpConfig  `mapstructure:"wakeup"` // vehicle wake-up strategy
 
 	enabled             bool      // Charger enabled state
 	phases              int       // Charger enabled phases, guarded by mutex
@@ -141,14 +154,33 @@ type LoadPoint struct {
 	socTimer       *soc.Timer
 
 	// cached state
-	status         api.ChargeStatus       // Charger status
-	remoteDemand   loadpoint.RemoteDemand // External status demand
-	chargePower    float64                // Charging power
-	chargeCurrents []float64              // Phase currents
-	connectedTime  time.Time              // Time when vehicle was connected
-	pvTimer        time.Time              // PV enabled/disable timer
-	phaseTimer     time.Time              // 1p3p switch timer
-	wakeUpTimer    *Timer                 // Vehicle wake-up timeout
+	status           api.ChargeStatus       // Charger status
+	remoteDemand     loadpoint.RemoteDemand // External status demand
+	remotePowerLimit float64                // External pv mode power limit
+	remoteLimitTime  time.Time              // External pv mode power limit timestamp
+	chargePower      float64                // Charging power
+	chargeCurrents   []float64              // Phase currents
+	connectedTime    time.Time              // Time when vehicle was connected
+	pvTimer          time.Time              // PV enabled/disable timer
+	phaseTimer       time.Time              // 1p3p switch timer
+	wakeUpTimer      *Timer                 // Vehicle wake-up timeout
+	wakeUpAttempts   int                    // Vehicle wake-up attempts since charger enabled
+	wakeUpNext       time.Time              // Next vehicle wake-up retry
+	wakeUpPulsed     bool                   // Charger disabled by phase pulse, retries continue when re-enabled
+	home             *geofence.Geofence     // Home location for geofenced polling
+	homeDistance     float64                // Vehicle's last known distance from home
+	homeChecked      time.Time              // Last vehicle position check
+	socLimitPending  bool                   // Target soc must be applied as vehicle charge limit, guarded by mutex
+	socLimitRetry    time.Time              // Next vehicle charge limit attempt, guarded by mutex
+	socLimitAttempts int                    // Failed vehicle charge limit attempts, guarded by mutex
+	departure        time.Time              // Target time for preconditioning, guarded by mutex
+	preconditioning  bool                   // Climatisation started for departure
+	preconditionTry  time.Time              // Last climatisation start attempt
+	observation      *signature.Observation // Charging signature of the connected vehicle
+	signatureChecked bool                   // Vehicle identified by charging signature
+	vehicleConfirmed bool                   // Active vehicle confirmed by id, status or user selection, guarded by mutex
+	startSnapshot    *db.Snapshot           // Start snapshot pending until soc and odometer are read
+	signatureStore   store.Provider         // Learned vehicle charging signatures
 
 	// charge progress
 	vehicleSoc              float64       // Vehicle SoC
@@ -174,7 +206,7 @@ func NewLoadPointFromConfig(log *util.Logger, cp configProvider, other map[strin
 
 	// set vehicle polling mode
 	switch lp.SoC.Poll.Mode = strings.ToLower(lp.SoC.Poll.Mode); lp.SoC.Poll.Mode {
-	case pollCharging:
+	case pollCharging, pollHome:
 	case pollConnected, pollAlways:
 		lp.log.WARN.Printf("poll mode '%s' may deplete your battery or lead to API misuse. USE AT YOUR OWN RISK.", lp.SoC.Poll)
 	default:
@@ -193,6 +225,10 @@ func NewLoadPointFromConfig(log *util.Logger, cp configProvider, other map[strin
 		}
 	}
 
+	if err := lp.WakeUp.validate(); err != nil {
+		return nil, err
+	}
+
 	if lp.MinCurrent == 0 {
 		lp.log.WARN.Println("minCurrent must not be zero")
 	}
@@ -281,6 +317,7 @@ func NewLoadPoint(log *util.Logger) *LoadPoint {
 		Enable:        ThresholdConfig{Delay: time.Minute, Threshold: 0},     // t, W
 		Disable:       ThresholdConfig{Delay: 3 * time.Minute, Threshold: 0}, // t, W
 		GuardDuration: 5 * time.Minute,
+		WakeUp:        WakeUpConfig{Backoff: wakeUpBackoff},
 		progress:      NewProgress(0, 10),     // soc progress indicator
 		coordinator:   coordinator.NewDummy(), // dummy vehicle coordinator
 		tasks:         aq.New(),               // task queue
@@ -365,6 +402,85 @@ func (lp *LoadPoint) configureChargerType(charger api.Charger) {
 	lp.wakeUpTimer = NewTimer()
 }
 
+// subscribeEvents registers the loadpoint's event handlers
+func (lp *LoadPoint) subscribeEvents() {
+	_ = lp.bus.Subscribe(evChargeStart, lp.evChargeStartHandler)
+	_ = lp.bus.Subscribe(evChargeStop, lp.evChargeStopHandler)
+	_ = lp.bus.Subscribe(evVehicleConnect, lp.evVehicleConnectHandler)
+	_ = lp.bus.Subscribe(evVehicleDisconnect, lp.evVehicleDisconnectHandler)
+	_ = lp.bus.Subscribe(evChargeCurrent, lp.evChargeCurrentHandler)
+	_ = lp.bus.Subscribe(evVehicleSoC, lp.evVehicleSoCProgressHandler)
+}
+
+// reconfigure re-resolves the loadpoint's device references after runtime configuration changes.
+// Charger and meter wrappers are only recreated if the charger or charge meter have changed.
+func (lp *LoadPoint) reconfigure(cp configProvider, vehicles []api.Vehicle) error {
+	charger, err := cp.Charger(lp.ChargerRef)
+	if err != nil {
+		return err
+	}
+
+	var meter api.Meter
+	if lp.MeterRef != "" {
+		if meter, err = cp.Meter(lp.MeterRef); err != nil {
+			return err
+		}
+	}
+
+	var defaultVehicle api.Vehicle
+	if lp.VehicleRef != "" {
+		if defaultVehicle, err = cp.Vehicle(lp.VehicleRef); err != nil {
+			return err
+		}
+	}
+
+	if charger != lp.charger || meter != nil && meter != lp.chargeMeter {
+		lp.log.INFO.Println("reconfiguring charger")
+
+		// drop wrapper subscriptions by starting with a fresh event bus
+		lp.bus = evbus.New()
+		lp.charger = charger
+		lp.chargeMeter = meter
+		lp.configureChargerType(charger)
+		lp.subscribeEvents()
+	}
+
+	lp.defaultVehicle = defaultVehicle
+
+	// restart detection if active vehicle has been removed
+	if lp.vehicle != nil && !containsVehicle(vehicles, lp.vehicle) {
+		if err := lp.StartVehicleDetection(); err != nil {
+			lp.log.ERROR.Printf("vehicle detection: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// applyConfig takes over the runtime settings of a loadpoint created from changed configuration.
+// Device references are resolved by reconfigure.
+func (lp *LoadPoint) applyConfig(conf *LoadPoint) {
+	lp.Lock()
+	lp.Title = conf.Title
+	lp.ChargerRef = conf.ChargerRef
+	lp.MeterRef = conf.MeterRef
+	lp.VehicleRef = conf.VehicleRef
+	lp.SoC.Poll = conf.SoC.Poll
+	lp.SoC.Estimate = conf.SoC.Estimate
+	lp.SoC.VehicleLimit = conf.SoC.VehicleLimit
+	lp.Enable, lp.Disable = conf.Enable, conf.Disable
+	lp.ResetOnDisconnect = conf.ResetOnDisconnect
+	lp.Signature = conf.Signature
+	lp.GuardDuration = conf.GuardDuration
+	lp.Precondition = conf.Precondition
+	lp.WakeUp = conf.WakeUp
+	lp.publish("title", lp.Title)
+	lp.Unlock()
+
+	_ = lp.SetMinCurrent(conf.MinCurrent)
+	_ = lp.SetMaxCurrent(conf.MaxCurrent)
+}
+
 // pushEvent sends push messages to clients
 func (lp *LoadPoint) pushEvent(event string) {
 	lp.pushChan <- push.Event{Event: event}
@@ -382,17 +498,31 @@ func (lp *LoadPoint) evChargeStartHandler() {
 	lp.log.INFO.Println("start charging ->")
 	lp.pushEvent(evChargeStart)
 
-	lp.wakeUpTimer.Stop()
-
 	// soc update reset
 	lp.socUpdated = time.Time{}
 
 	// set created when first charging session segment starts
+	first := lp.session != nil && lp.session.Created.IsZero()
 	lp.updateSession(func(session *db.Session) {
 		if session.Created.IsZero() {
 			session.Created = lp.clock.Now()
 		}
 	})
+
+	// start snapshot is persisted once soc and odometer have been read
+	if first && lp.db != nil {
+		lp.startSnapshot = &db.Snapshot{Event: db.SnapshotStart}
+		lp.addTask(lp.vehicleOdometer)
+	}
+
+	// record and stop vehicle wake-up
+	lp.wakeUpSucceeded()
+	lp.stopWakeUp()
+
+	// observe charging signature from first charge start
+	if lp.observation == nil {
+		lp.observation = signature.NewObservation(lp.clock.Now())
+	}
 }
 
 // evChargeStopHandler sends external stop event
@@ -438,6 +568,11 @@ func (lp *LoadPoint) evVehicleConnectHandler() {
 	// immediately allow pv mode activity
 	lp.elapsePVTimer()
 
+	// apply vehicle charge limit to connected vehicle
+	lp.Lock()
+	lp.requestVehicleSocLimit()
+	lp.Unlock()
+
 	// create charging session
 	lp.createSession()
 }
@@ -447,7 +582,15 @@ func (lp *LoadPoint) evVehicleDisconnectHandler() {
 	lp.log.INFO.Println("car disconnected")
 
 	// session is persisted during evChargeStopHandler which runs before
+	if lp.session != nil && !lp.session.Created.IsZero() {
+		lp.persistSnapshot(&db.Snapshot{
+			Event:    db.SnapshotStop,
+			Odometer: lp.session.Odometer,
+			SoC:      lp.vehicleSoc,
+		})
+	}
 	lp.clearSession()
+	lp.startSnapshot = nil
 
 	// phases are unknown when vehicle disconnects
 	lp.resetMeasuredPhases()
@@ -456,6 +599,12 @@ func (lp *LoadPoint) evVehicleDisconnectHandler() {
 	lp.publish("chargedEnergy", lp.getChargedEnergy())
 	lp.publish("connectedDuration", lp.clock.Since(lp.connectedTime))
 
+	// learn charging signature before vehicle is removed
+	lp.learnSignature()
+
+	// preconditioning ends with the session
+	lp.resetPreconditioning()
+
 	// remove charger vehicle id and stop potential detection
 	lp.setVehicleIdentifier("")
 	lp.stopVehicleDetection()
@@ -516,19 +665,29 @@ func (lp *LoadPoint) evChargeCurrentWrappedMeterHandler(current float64) {
 // applyAction executes the action
 func (lp *LoadPoint) applyAction(actionCfg api.ActionConfig) {
 	if actionCfg.Mode != nil {
-		lp.SetMode(*actionCfg.Mode)
+		if err := lp.SetMode(*actionCfg.Mode); err != nil {
+			lp.log.ERROR.Printf("action mode: %v", err)
+		}
 	}
 	if min := actionCfg.MinCurrent; min != nil && *min >= *lp.onDisconnect.MinCurrent {
-		lp.SetMinCurrent(*min)
+		if err := lp.SetMinCurrent(*min); err != nil {
+			lp.log.ERROR.Printf("action min current: %v", err)
+		}
 	}
 	if max := actionCfg.MaxCurrent; max != nil && *max <= *lp.onDisconnect.MaxCurrent {
-		lp.SetMaxCurrent(*max)
+		if err := lp.SetMaxCurrent(*max); err != nil {
+			lp.log.ERROR.Printf("action max current: %v", err)
+		}
 	}
 	if actionCfg.MinSoC != nil {
-		lp.SetMinSoC(*actionCfg.MinSoC)
+		if err := lp.SetMinSoC(*actionCfg.MinSoC); err != nil {
+			lp.log.ERROR.Printf("action min soc: %v", err)
+		}
 	}
 	if actionCfg.TargetSoC != nil {
-		lp.SetTargetSoC(*actionCfg.TargetSoC)
+		if err := lp.SetTargetSoC(*actionCfg.TargetSoC); err != nil {
+			lp.log.ERROR.Printf("action target soc: %v", err)
+		}
 	}
 }
 
@@ -544,12 +703,7 @@ func (lp *LoadPoint) Prepare(uiChan chan<- util.Param, pushChan chan<- push.Even
 	lp.lpChan = lpChan
 
 	// event handlers
-	_ = lp.bus.Subscribe(evChargeStart, lp.evChargeStartHandler)
-	_ = lp.bus.Subscribe(evChargeStop, lp.evChargeStopHandler)
-	_ = lp.bus.Subscribe(evVehicleConnect, lp.evVehicleConnectHandler)
-	_ = lp.bus.Subscribe(evVehicleDisconnect, lp.evVehicleDisconnectHandler)
-	_ = lp.bus.Subscribe(evChargeCurrent, lp.evChargeCurrentHandler)
-	_ = lp.bus.Subscribe(evVehicleSoC, lp.evVehicleSoCProgressHandler)
+	lp.subscribeEvents()
 
 	// publish initial values
 	lp.publish("title", lp.Title)
@@ -663,11 +817,10 @@ func (lp *LoadPoint) setLimit(chargeCurrent float64, force bool) error {
 
 		// start/stop vehicle wake-up timer
 		if enabled {
-			lp.log.DEBUG.Printf("wake-up timer: start")
-			lp.wakeUpTimer.Start()
+			lp.startWakeUp()
 		} else {
 			lp.log.DEBUG.Printf("wake-up timer: stop")
-			lp.wakeUpTimer.Stop()
+			lp.stopWakeUp()
 		}
 
 		// remote start
@@ -809,6 +962,7 @@ func (lp *LoadPoint) identifyVehicle() {
 		if vehicle := lp.selectVehicleByID(id); vehicle != nil {
 			lp.stopVehicleDetection()
 			lp.setActiveVehicle(vehicle)
+			lp.confirmVehicle()
 		}
 	}
 }
@@ -845,6 +999,13 @@ func (lp *LoadPoint) selectVehicleByID(id string) api.Vehicle {
 	return nil
 }
 
+// confirmVehicle marks the active vehicle as confirmed
+func (lp *LoadPoint) confirmVehicle() {
+	lp.Lock()
+	defer lp.Unlock()
+	lp.vehicleConfirmed = lp.vehicle != nil
+}
+
 // setActiveVehicle assigns currently active vehicle, configures soc estimator
 // and adds an odometer task
 func (lp *LoadPoint) setActiveVehicle(vehicle api.Vehicle) {
@@ -867,12 +1028,25 @@ func (lp *LoadPoint) setActiveVehicle(vehicle api.Vehicle) {
 	}
 	lp.log.INFO.Printf("vehicle updated: %s -> %s", from, to)
 
+	// preconditioning belongs to the previous vehicle
+	lp.resetPreconditioning()
+
+	// new vehicle is unconfirmed until identified by id, status or user selection
+	lp.vehicleConfirmed = false
+
+	// pending start snapshot must not mix values of different vehicles
+	if lp.startSnapshot != nil {
+		lp.startSnapshot = &db.Snapshot{Event: db.SnapshotStart}
+	}
+
 	// reset minSoC and targetSoC before change
 	lp.setMinSoC(0)
 	lp.setTargetSoC(100)
 
 	if lp.vehicle = vehicle; vehicle != nil {
 		lp.socUpdated = time.Time{}
+		lp.homeDistance = 0
+		lp.homeChecked = time.Time{}
 
 		// resolve optional config
 		var estimate bool
@@ -881,6 +1055,11 @@ func (lp *LoadPoint) setActiveVehicle(vehicle api.Vehicle) {
 		}
 		lp.socEstimator = soc.NewEstimator(lp.log, lp.charger, vehicle, estimate)
 
+		// learned capacity and charging curve
+		if serverdb.Instance != nil {
+			lp.socEstimator.SetProfileStore(settings.NewStore(fmt.Sprintf("vehicle.%s.profile", vehicle.Title())))
+		}
+
 		lp.publish("vehiclePresent", true)
 		lp.publish("vehicleTitle", lp.vehicle.Title())
 		lp.publish("vehicleIcon", lp.vehicle.Icon())
@@ -924,23 +1103,6 @@ func (lp *LoadPoint) setActiveVehicle(vehicle api.Vehicle) {
 	})
 }
 
-func (lp *LoadPoint) wakeUpVehicle() {
-	// charger
-	if c, ok := lp.charger.(api.Resurrector); ok {
-		if err := c.WakeUp(); err != nil {
-			lp.log.ERROR.Printf("wake-up charger: %v", err)
-		}
-		return
-	}
-
-	// vehicle
-	if vs, ok := lp.vehicle.(api.Resurrector); ok {
-		if err := vs.WakeUp(); err != nil {
-			lp.log.ERROR.Printf("wake-up vehicle: %v", err)
-		}
-	}
-}
-
 // unpublishVehicle resets published vehicle data
 func (lp *LoadPoint) unpublishVehicle() {
 	lp.vehicleSoc = 0
@@ -1040,6 +1202,7 @@ func (lp *LoadPoint) identifyVehicleByStatus() {
 	if vehicle := lp.coordinator.IdentifyVehicleByStatus(!ok); vehicle != nil {
 		lp.stopVehicleDetection()
 		lp.setActiveVehicle(vehicle)
+		lp.confirmVehicle()
 		return
 	}
 
@@ -1060,6 +1223,9 @@ func (lp *LoadPoint) vehicleOdometer() {
 			lp.updateSession(func(session *db.Session) {
 				session.Odometer = odo
 			})
+			lp.updateStartSnapshot(func(snapshot *db.Snapshot) {
+				snapshot.Odometer = odo
+			})
 		} else {
 			lp.log.ERROR.Printf("vehicle odometer: %v", err)
 		}
@@ -1357,6 +1523,25 @@ func (lp *LoadPoint) publishTimer(name string, delay time.Duration, action strin
 	}
 }
 
+// remoteLimitedSitePower caps the power available for charging at the remote power limit.
+// The limit is applied as site power such that pv timers and phase switching still apply.
+func (lp *LoadPoint) remoteLimitedSitePower(sitePower float64) float64 {
+	limit := lp.getRemotePowerLimit()
+	if limit <= 0 {
+		return sitePower
+	}
+
+	// charge power matching the effective current pv mode adjusts from
+	chargePower := lp.effectiveCurrent() * float64(lp.activePhases()) * Voltage
+
+	if availablePower := chargePower - sitePower; availablePower > limit {
+		lp.log.DEBUG.Printf("remote power limit: %.0fW < %.0fW available", limit, availablePower)
+		return chargePower - limit
+	}
+
+	return sitePower
+}
+
 // pvMaxCurrent calculates the maximum target current for PV mode
 func (lp *LoadPoint) pvMaxCurrent(mode api.ChargeMode, sitePower float64, batteryBuffered bool) float64 {
 	// read only once to simplify testing
@@ -1555,17 +1740,29 @@ func (lp *LoadPoint) publishChargeProgress() {
 
 // socPollAllowed validates charging state against polling mode
 func (lp *LoadPoint) socPollAllowed() bool {
-	remaining := lp.SoC.Poll.Interval - lp.clock.Since(lp.socUpdated)
+	budget := lp.vehicleBudget()
+	if budget != nil && budget.Exhausted() {
+		lp.log.DEBUG.Printf("vehicle api budget exhausted until %v", budget.Reset().Round(time.Minute))
+		lp.publishBudget(budget)
+		return false
+	}
+
+	interval := lp.socPollInterval(budget)
+	remaining := interval - lp.clock.Since(lp.socUpdated)
 
 	honourUpdateInterval := lp.SoC.Poll.Mode == pollAlways ||
 		lp.SoC.Poll.Mode == pollConnected && lp.connected() ||
-		lp.SoC.Poll.Mode == pollCharging && lp.connected() && (lp.vehicleSoc < float64(lp.SoC.target))
+		lp.SoC.Poll.Mode == pollCharging && lp.connected() && (lp.vehicleSoc < float64(lp.SoC.target)) ||
+		lp.SoC.Poll.Mode == pollHome && (lp.connected() || remaining <= 0 && lp.vehicleNearHome())
 
 	if honourUpdateInterval && remaining > 0 {
 		lp.log.DEBUG.Printf("next soc poll remaining time: %v", remaining.Truncate(time.Second))
 	}
 
-	return lp.charging() || honourUpdateInterval && (remaining <= 0) || lp.connected() && lp.socUpdated.IsZero()
+	// honour the interval while charging if the api budget is used too fast
+	charging := lp.charging() && (budget == nil || budget.Pressure() <= 1 || remaining <= 0)
+
+	return charging || honourUpdateInterval && (remaining <= 0) || lp.connected() && lp.socUpdated.IsZero()
 }
 
 // checks if the connected charger can provide SoC to the connected vehicle
@@ -1584,6 +1781,11 @@ func (lp *LoadPoint) publishSoCAndRange() {
 		return
 	}
 
+	// learn charging curve from actual vs offered power
+	if lp.charging() {
+		lp.socEstimator.Learn(lp.chargePower, Voltage*lp.chargeCurrent*float64(lp.activePhases()))
+	}
+
 	if lp.socPollAllowed() || lp.socProvidedByCharger() {
 		var f float64
 		var err error
@@ -1609,6 +1811,11 @@ func (lp *LoadPoint) publishSoCAndRange() {
 		lp.vehicleSoc = math.Trunc(f)
 		lp.log.DEBUG.Printf("vehicle soc: %.0f%%", lp.vehicleSoc)
 		lp.publish("vehicleSoC", lp.vehicleSoc)
+		lp.publishBudget(lp.vehicleBudget())
+
+		lp.updateStartSnapshot(func(snapshot *db.Snapshot) {
+			snapshot.SoC = lp.vehicleSoc
+		})
 
 		// vehicle target soc
 		targetSoC := 100.0
@@ -1651,6 +1858,69 @@ func (lp *LoadPoint) publishSoCAndRange() {
 	}
 }
 
+// requestVehicleSocLimit marks the target soc to be applied as vehicle charge limit (no mutex)
+func (lp *LoadPoint) requestVehicleSocLimit() {
+	if lp.SoC.VehicleLimit {
+		lp.socLimitPending = true
+		lp.socLimitRetry = time.Time{}
+		lp.socLimitAttempts = 0
+	}
+}
+
+// vehicleSocLimit applies the requested target soc as vehicle charge limit to the connected vehicle.
+// Failed attempts are retried until socLimitMaxAttempts is reached.
+func (lp *LoadPoint) vehicleSocLimit() {
+	lp.Lock()
+	pending, retry, soc := lp.socLimitPending, lp.socLimitRetry, lp.SoC.target
+	lp.Unlock()
+
+	if !pending || lp.vehicle == nil || !lp.connected() || lp.clock.Now().Before(retry) {
+		return
+	}
+
+	vs, ok := lp.vehicle.(api.SocLimitSetter)
+
+	var err error
+	if ok {
+		if err = vs.SetTargetSoC(soc); err != nil {
+			lp.Lock()
+			lp.socLimitAttempts++
+			attempts := lp.socLimitAttempts
+			lp.socLimitRetry = lp.clock.Now().Add(socLimitRetryDelay)
+			lp.Unlock()
+
+			if attempts < socLimitMaxAttempts {
+				if errors.Is(err, api.ErrMustRetry) {
+					lp.log.DEBUG.Printf("vehicle target soc: retrying in %v", socLimitRetryDelay)
+				} else {
+					lp.log.WARN.Printf("vehicle target soc: %v, retrying in %v", err, socLimitRetryDelay)
+				}
+
+				return
+			}
+
+			err = fmt.Errorf("giving up after %d attempts: %w", attempts, err)
+		}
+	}
+
+	lp.Lock()
+	// keep request if target has changed meanwhile
+	if lp.SoC.target == soc {
+		lp.socLimitPending = false
+	}
+	lp.Unlock()
+
+	switch {
+	case !ok:
+		return
+	case err != nil:
+		lp.log.ERROR.Printf("vehicle target soc: %v", err)
+	default:
+		lp.log.DEBUG.Printf("vehicle target soc set: %d%%", soc)
+		lp.publish(vehicleTargetSoC, float64(soc))
+	}
+}
+
 // addTask adds a single task to the queue
 func (lp *LoadPoint) addTask(task func()) {
 	// test guard
@@ -1678,6 +1948,9 @@ func (lp *LoadPoint) processTasks() {
 func (lp *LoadPoint) Update(sitePower float64, cheap, batteryBuffered bool) {
 	lp.processTasks()
 
+	// start climatisation before departure
+	lp.precondition()
+
 	mode := lp.GetMode()
 	lp.publish("mode", mode)
 
@@ -1712,6 +1985,12 @@ func (lp *LoadPoint) Update(sitePower float64, cheap, batteryBuffered bool) {
 		}
 	}
 
+	// observe charging signature and identify vehicle once complete
+	lp.observeSignature()
+
+	// apply target soc as vehicle charge limit
+	lp.vehicleSocLimit()
+
 	// publish soc after updating charger status to make sure
 	// initial update of connected state matches charger status
 	lp.publishSoCAndRange()
@@ -1778,6 +2057,11 @@ func (lp *LoadPoint) Update(sitePower float64, cheap, batteryBuffered bool) {
 		}
 
 	case mode == api.ModeMinPV || mode == api.ModePV:
+		// Sunny Home Manager power recommendation
+		if mode == api.ModePV {
+			sitePower = lp.remoteLimitedSitePower(sitePower)
+		}
+
 		targetCurrent := lp.pvMaxCurrent(mode, sitePower, batteryBuffered)
 
 		var required bool // false
@@ -1806,7 +2090,7 @@ func (lp *LoadPoint) Update(sitePower float64, cheap, batteryBuffered bool) {
 
 	// Wake-up checks
 	if lp.enabled && lp.status == api.StatusB &&
-		int(lp.vehicleSoc) < lp.SoC.target && lp.wakeUpTimer.Expired() {
+		int(lp.vehicleSoc) < lp.SoC.target && lp.wakeUpDue() {
 		lp.wakeUpVehicle()
 	}
 
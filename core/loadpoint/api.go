@@ -30,28 +30,32 @@ type API interface {
 	// GetMode returns the charge mode
 	GetMode() api.ChargeMode
 	// SetMode sets the charge mode
-	SetMode(api.ChargeMode)
+	SetMode(api.ChargeMode) error
 	// GetTargetEnergy returns the charge target energy
 	GetTargetEnergy() float64
 	// SetTargetEnergy sets the charge target energy
-	SetTargetEnergy(float64)
+	SetTargetEnergy(float64) error
 	// GetTargetSoC returns the charge target soc
 	GetTargetSoC() int
 	// SetTargetSoC sets the charge target soc
-	SetTargetSoC(int)
+	SetTargetSoC(int) error
 	// GetMinSoC returns the charge minimum soc
 	GetMinSoC() int
 	// SetMinSoC sets the charge minimum soc
-	SetMinSoC(int)
+	SetMinSoC(int) error
 	// GetPhases returns the enabled phases
 	GetPhases() int
 	// SetPhases sets the enabled phases
 	SetPhases(int) error
 
+	// GetTargetTime returns the target charge time
+	GetTargetTime() time.Time
 	// SetTargetCharge sets the charge targetSoC
-	SetTargetCharge(time.Time, int)
+	SetTargetCharge(time.Time, int) error
 	// RemoteControl sets remote status demand
-	RemoteControl(string, RemoteDemand)
+	RemoteControl(string, RemoteDemand) error
+	// RemotePowerLimit sets remote power limit for pv mode, zero removes the limit
+	RemotePowerLimit(string, float64) error
 
 	//
 	// power and energy
@@ -64,11 +68,11 @@ type API interface {
 	// GetMinCurrent returns the min charging current
 	GetMinCurrent() float64
 	// SetMinCurrent sets the min charging current
-	SetMinCurrent(float64)
+	SetMinCurrent(float64) error
 	// GetMaxCurrent returns the max charging current
 	GetMaxCurrent() float64
 	// SetMaxCurrent sets the max charging current
-	SetMaxCurrent(float64)
+	SetMaxCurrent(float64) error
 	// GetMinPower returns the min charging power for a single phase
 	GetMinPower() float64
 	// GetMaxPower returns the max charging power taking active phases into account
@@ -88,7 +92,7 @@ type API interface {
 	//
 
 	// SetVehicle sets the active vehicle
-	SetVehicle(vehicle api.Vehicle)
+	SetVehicle(vehicle api.Vehicle) error
 	// StartVehicleDetection allows triggering vehicle detection for debugging purposes
-	StartVehicleDetection()
+	StartVehicleDetection() error
 }
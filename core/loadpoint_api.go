@@ -26,13 +26,12 @@ func (lp *LoadPoint) GetMode() api.ChargeMode {
 }
 
 // SetMode sets loadpoint charge mode
-func (lp *LoadPoint) SetMode(mode api.ChargeMode) {
+func (lp *LoadPoint) SetMode(mode api.ChargeMode) error {
 	lp.Lock()
 	defer lp.Unlock()
 
 	if _, err := api.ChargeModeString(mode.String()); err != nil {
-		lp.log.ERROR.Printf("invalid charge mode: %s", string(mode))
-		return
+		return err
 	}
 
 	lp.log.DEBUG.Printf("set charge mode: %s", string(mode))
@@ -47,6 +46,8 @@ func (lp *LoadPoint) SetMode(mode api.ChargeMode) {
 
 		lp.requestUpdate()
 	}
+
+	return nil
 }
 
 // getChargedEnergy returns loadpoint charge target energy
@@ -81,7 +82,7 @@ func (lp *LoadPoint) setTargetEnergy(energy float64) {
 }
 
 // SetTargetEnergy sets loadpoint charge target energy
-func (lp *LoadPoint) SetTargetEnergy(energy float64) {
+func (lp *LoadPoint) SetTargetEnergy(energy float64) error {
 	lp.Lock()
 	defer lp.Unlock()
 
@@ -92,6 +93,8 @@ func (lp *LoadPoint) SetTargetEnergy(energy float64) {
 		lp.setTargetEnergy(energy)
 		lp.requestUpdate()
 	}
+
+	return nil
 }
 
 // GetTargetSoC returns loadpoint charge target soc
@@ -104,6 +107,7 @@ func (lp *LoadPoint) GetTargetSoC() int {
 // setTargetSoC sets loadpoint charge target soc (no mutex)
 func (lp *LoadPoint) setTargetSoC(soc int) {
 	lp.SoC.target = soc
+	lp.requestVehicleSocLimit()
 	// test guard
 	if lp.socTimer != nil {
 		lp.socTimer.SoC = soc
@@ -112,7 +116,7 @@ func (lp *LoadPoint) setTargetSoC(soc int) {
 }
 
 // SetTargetSoC sets loadpoint charge target soc
-func (lp *LoadPoint) SetTargetSoC(soc int) {
+func (lp *LoadPoint) SetTargetSoC(soc int) error {
 	lp.Lock()
 	defer lp.Unlock()
 
@@ -123,6 +127,8 @@ func (lp *LoadPoint) SetTargetSoC(soc int) {
 		lp.setTargetSoC(soc)
 		lp.requestUpdate()
 	}
+
+	return nil
 }
 
 // GetMinSoC returns loadpoint charge minimum soc
@@ -139,7 +145,7 @@ func (lp *LoadPoint) setMinSoC(soc int) {
 }
 
 // SetMinSoC sets loadpoint charge minimum soc
-func (lp *LoadPoint) SetMinSoC(soc int) {
+func (lp *LoadPoint) SetMinSoC(soc int) error {
 	lp.Lock()
 	defer lp.Unlock()
 
@@ -150,6 +156,8 @@ func (lp *LoadPoint) SetMinSoC(soc int) {
 		lp.setMinSoC(soc)
 		lp.requestUpdate()
 	}
+
+	return nil
 }
 
 // GetPhases returns loadpoint enabled phases
@@ -184,13 +192,23 @@ func (lp *LoadPoint) SetPhases(phases int) error {
 	return nil
 }
 
+// GetTargetTime returns the target charge time
+func (lp *LoadPoint) GetTargetTime() time.Time {
+	lp.Lock()
+	defer lp.Unlock()
+	return lp.socTimer.Time
+}
+
 // SetTargetCharge sets loadpoint charge targetSoC
-func (lp *LoadPoint) SetTargetCharge(finishAt time.Time, soc int) {
+func (lp *LoadPoint) SetTargetCharge(finishAt time.Time, soc int) error {
 	lp.Lock()
 	defer lp.Unlock()
 
 	lp.log.DEBUG.Printf("set target charge: %d @ %v", soc, finishAt)
 
+	// departure is kept after target soc is reached
+	lp.departure = finishAt
+
 	// apply immediately
 	if lp.socTimer.Time != finishAt || lp.SoC.target != soc {
 		lp.socTimer.Set(finishAt)
@@ -201,10 +219,12 @@ func (lp *LoadPoint) SetTargetCharge(finishAt time.Time, soc int) {
 			lp.requestUpdate()
 		}
 	}
+
+	return nil
 }
 
 // RemoteControl sets remote status demand
-func (lp *LoadPoint) RemoteControl(source string, demand loadpoint.RemoteDemand) {
+func (lp *LoadPoint) RemoteControl(source string, demand loadpoint.RemoteDemand) error {
 	lp.Lock()
 	defer lp.Unlock()
 
@@ -219,6 +239,42 @@ func (lp *LoadPoint) RemoteControl(source string, demand loadpoint.RemoteDemand)
 
 		lp.requestUpdate()
 	}
+
+	return nil
+}
+
+// RemotePowerLimit sets remote power limit for pv mode, zero removes the limit
+func (lp *LoadPoint) RemotePowerLimit(source string, power float64) error {
+	lp.Lock()
+	defer lp.Unlock()
+
+	lp.log.DEBUG.Printf("remote power limit: %.0fW", power)
+
+	// limit expires unless repeated
+	lp.remoteLimitTime = lp.clock.Now()
+
+	// apply immediately
+	if lp.remotePowerLimit != power {
+		lp.remotePowerLimit = power
+		lp.publish("remotePowerLimit", power)
+		lp.requestUpdate()
+	}
+
+	return nil
+}
+
+// getRemotePowerLimit returns the remote power limit, zero if expired
+func (lp *LoadPoint) getRemotePowerLimit() float64 {
+	lp.Lock()
+	defer lp.Unlock()
+
+	if lp.remotePowerLimit > 0 && lp.clock.Since(lp.remoteLimitTime) > remoteLimitTimeout {
+		lp.log.DEBUG.Println("remote power limit: expired")
+		lp.remotePowerLimit = 0
+		lp.publish("remotePowerLimit", 0.0)
+	}
+
+	return lp.remotePowerLimit
 }
 
 // HasChargeMeter determines if a physical charge meter is attached
@@ -242,7 +298,7 @@ func (lp *LoadPoint) GetMinCurrent() float64 {
 }
 
 // SetMinCurrent sets the min loadpoint current
-func (lp *LoadPoint) SetMinCurrent(current float64) {
+func (lp *LoadPoint) SetMinCurrent(current float64) error {
 	lp.Lock()
 	defer lp.Unlock()
 
@@ -252,6 +308,8 @@ func (lp *LoadPoint) SetMinCurrent(current float64) {
 		lp.MinCurrent = current
 		lp.publish("minCurrent", lp.MinCurrent)
 	}
+
+	return nil
 }
 
 // GetMaxCurrent returns the max loadpoint current
@@ -262,7 +320,7 @@ func (lp *LoadPoint) GetMaxCurrent() float64 {
 }
 
 // SetMaxCurrent sets the max loadpoint current
-func (lp *LoadPoint) SetMaxCurrent(current float64) {
+func (lp *LoadPoint) SetMaxCurrent(current float64) error {
 	lp.Lock()
 	defer lp.Unlock()
 
@@ -272,6 +330,8 @@ func (lp *LoadPoint) SetMaxCurrent(current float64) {
 		lp.MaxCurrent = current
 		lp.publish("maxCurrent", lp.MaxCurrent)
 	}
+
+	return nil
 }
 
 // GetMinPower returns the min loadpoint power for a single phase
@@ -318,7 +378,7 @@ func (lp *LoadPoint) GetRemainingEnergy() float64 {
 }
 
 // SetVehicle sets the active vehicle
-func (lp *LoadPoint) SetVehicle(vehicle api.Vehicle) {
+func (lp *LoadPoint) SetVehicle(vehicle api.Vehicle) error {
 	// TODO develop universal locking approach
 	// setActiveVehicle is protected by lock, hence no locking here
 
@@ -328,12 +388,17 @@ func (lp *LoadPoint) SetVehicle(vehicle api.Vehicle) {
 	lp.Lock()
 	defer lp.Unlock()
 
+	// user selection confirms the vehicle
+	lp.vehicleConfirmed = vehicle != nil
+
 	// disable auto-detect
 	lp.stopVehicleDetection()
+
+	return nil
 }
 
 // StartVehicleDetection allows triggering vehicle detection for debugging purposes
-func (lp *LoadPoint) StartVehicleDetection() {
+func (lp *LoadPoint) StartVehicleDetection() error {
 	// reset vehicle
 	lp.setActiveVehicle(nil)
 
@@ -342,4 +407,6 @@ func (lp *LoadPoint) StartVehicleDetection() {
 
 	// start auto-detect
 	lp.startVehicleDetection()
+
+	return nil
 }
@@ -0,0 +1,60 @@
+package core
+
+import (
+	"time"
+
+	"github.com/evcc-io/evcc/util/request"
+)
+
+const (
+	urgentSoC      = 10            // soc below target considered urgent
+	urgentDuration = 2 * time.Hour // time before target considered urgent
+)
+
+// vehicleBudget returns the vehicle's api request budget if available
+func (lp *LoadPoint) vehicleBudget() *request.Budget {
+	if b, ok := lp.vehicle.(request.Budgeter); ok {
+		return b.Budget()
+	}
+	return nil
+}
+
+// socPollUrgent returns true if the vehicle is close to the target soc or target time
+func (lp *LoadPoint) socPollUrgent() bool {
+	if lp.vehicleSoc >= float64(lp.SoC.target-urgentSoC) && lp.vehicleSoc < float64(lp.SoC.target) {
+		return true
+	}
+
+	return !lp.socTimer.Time.IsZero() && lp.clock.Until(lp.socTimer.Time) < urgentDuration
+}
+
+// socPollInterval returns the poll interval adapted to the vehicle's api request budget.
+// The vehicle is polled less often if the budget is used faster than it lasts for the day
+// and more often if the target soc or target time is close.
+func (lp *LoadPoint) socPollInterval(b *request.Budget) time.Duration {
+	interval := lp.SoC.Poll.Interval
+	if b == nil {
+		return interval
+	}
+
+	if pressure := b.Pressure(); pressure > 1 {
+		return time.Duration(float64(interval) * pressure)
+	}
+
+	if lp.socPollUrgent() {
+		return interval / 2
+	}
+
+	return interval
+}
+
+// publishBudget publishes the vehicle's api request budget
+func (lp *LoadPoint) publishBudget(b *request.Budget) {
+	if b == nil {
+		return
+	}
+
+	used, limit := b.Used()
+	lp.publish("vehicleApiRequests", used)
+	lp.publish("vehicleApiLimit", limit)
+}
@@ -0,0 +1,69 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/soc"
+	"github.com/evcc-io/evcc/mock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type budgetVehicle struct {
+	*mock.MockVehicle
+	budget *request.Budget
+}
+
+func (v *budgetVehicle) Budget() *request.Budget {
+	return v.budget
+}
+
+func TestSoCPollBudget(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	clck := clock.NewMock()
+
+	vehicle := &budgetVehicle{
+		MockVehicle: mock.NewMockVehicle(ctrl),
+		budget:      request.NewBudget("core-test", 1000000),
+	}
+
+	lp := &LoadPoint{
+		clock:   clck,
+		log:     util.NewLogger("foo"),
+		status:  api.StatusC,
+		vehicle: vehicle,
+		SoC: SoCConfig{
+			Poll: PollConfig{
+				Mode:     pollCharging,
+				Interval: time.Hour,
+			},
+			target: 80,
+		},
+	}
+
+	lp.socTimer = soc.NewTimer(lp.log, &adapter{LoadPoint: lp})
+
+	// not urgent
+	lp.vehicleSoc = 50
+	assert.Equal(t, time.Hour, lp.socPollInterval(vehicle.budget))
+	assert.True(t, lp.socPollAllowed())
+
+	// close to target soc
+	lp.vehicleSoc = 75
+	assert.Equal(t, 30*time.Minute, lp.socPollInterval(vehicle.budget))
+
+	// close to target time
+	lp.vehicleSoc = 50
+	lp.socTimer.Time = clck.Now().Add(time.Hour)
+	assert.Equal(t, 30*time.Minute, lp.socPollInterval(vehicle.budget))
+
+	// exhausted
+	vehicle.budget = request.NewBudget("core-test-exhausted", 1)
+	vehicle.budget.Add()
+	assert.False(t, lp.socPollAllowed())
+}
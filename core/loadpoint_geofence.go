@@ -0,0 +1,52 @@
+package core
+
+import (
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// positionInterval is the minimum interval between vehicle position checks near home
+const positionInterval = 5 * time.Minute
+
+// positionCheckInterval returns the interval between vehicle position checks.
+// Position is checked at poll interval while away and more often once the vehicle is near home.
+func (lp *LoadPoint) positionCheckInterval() time.Duration {
+	interval := lp.SoC.Poll.Interval
+	if lp.homeDistance > 0 && lp.homeDistance <= lp.home.Approach && positionInterval < interval {
+		interval = positionInterval
+	}
+	return interval
+}
+
+// vehicleNearHome returns true if the vehicle is within the home geofence or approaching home.
+// Vehicles without home location or position are considered away.
+func (lp *LoadPoint) vehicleNearHome() bool {
+	vp, ok := lp.vehicle.(api.VehiclePosition)
+	if !ok || !lp.home.Configured() {
+		return false
+	}
+
+	if !lp.homeChecked.IsZero() && lp.clock.Since(lp.homeChecked) < lp.positionCheckInterval() {
+		return false
+	}
+	lp.homeChecked = lp.clock.Now()
+
+	lat, lon, err := vp.Position()
+	if err != nil {
+		lp.log.ERROR.Printf("vehicle position: %v", err)
+		return false
+	}
+
+	dist := lp.home.Distance(lat, lon)
+	prev := lp.homeDistance
+	lp.homeDistance = dist
+
+	atHome := dist <= lp.home.Radius
+	approaching := !atHome && dist <= lp.home.Approach && prev > 0 && dist < prev
+
+	lp.log.DEBUG.Printf("vehicle position: %.0fm from home (at home: %t, approaching: %t)", dist, atHome, approaching)
+	lp.publish("vehicleAtHome", atHome)
+
+	return atHome || approaching
+}
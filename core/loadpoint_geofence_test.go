@@ -0,0 +1,111 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/geofence"
+	"github.com/evcc-io/evcc/mock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type positionVehicle struct {
+	*mock.MockVehicle
+	lat, lon float64
+}
+
+func (v *positionVehicle) Position() (float64, float64, error) {
+	return v.lat, v.lon, nil
+}
+
+func TestSoCPollHome(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	clck := clock.NewMock()
+
+	home := &geofence.Geofence{Latitude: 52.5163, Longitude: 13.3777}
+	assert.NoError(t, home.Validate())
+
+	// far away
+	vehicle := &positionVehicle{MockVehicle: mock.NewMockVehicle(ctrl), lat: 48.1372, lon: 11.5756}
+
+	lp := &LoadPoint{
+		clock:   clck,
+		log:     util.NewLogger("foo"),
+		status:  api.StatusA,
+		vehicle: vehicle,
+		home:    home,
+		SoC: SoCConfig{
+			Poll: PollConfig{
+				Mode:     pollHome,
+				Interval: time.Hour,
+			},
+		},
+	}
+
+	// populate channels
+	x, y, z := createChannels(t)
+	attachChannels(lp, x, y, z)
+
+	clck.Add(time.Hour)
+	assert.False(t, lp.socPollAllowed())
+
+	// approaching but position not checked again yet
+	vehicle.lat, vehicle.lon = 52.5251, 13.3694
+	clck.Add(time.Minute)
+	assert.False(t, lp.socPollAllowed())
+
+	// position checked at poll interval while away
+	clck.Add(positionInterval)
+	assert.False(t, lp.socPollAllowed())
+
+	// approaching
+	clck.Add(time.Hour)
+	assert.True(t, lp.socPollAllowed())
+	lp.socUpdated = clck.Now()
+
+	// poll interval honoured
+	vehicle.lat, vehicle.lon = 52.5170, 13.3780
+	clck.Add(positionInterval)
+	assert.False(t, lp.socPollAllowed())
+
+	// at home
+	clck.Add(time.Hour)
+	assert.True(t, lp.socPollAllowed())
+	lp.socUpdated = clck.Now()
+
+	// leaving
+	vehicle.lat, vehicle.lon = 52.5251, 13.3694
+	clck.Add(time.Hour)
+	assert.False(t, lp.socPollAllowed())
+}
+
+func TestPositionCheckInterval(t *testing.T) {
+	home := &geofence.Geofence{Latitude: 52.5163, Longitude: 13.3777}
+	assert.NoError(t, home.Validate())
+
+	lp := &LoadPoint{
+		home: home,
+		SoC: SoCConfig{
+			Poll: PollConfig{Interval: time.Hour},
+		},
+	}
+
+	// unknown position
+	assert.Equal(t, time.Hour, lp.positionCheckInterval())
+
+	// away
+	lp.homeDistance = 2 * home.Approach
+	assert.Equal(t, time.Hour, lp.positionCheckInterval())
+
+	// near home
+	lp.homeDistance = home.Approach / 2
+	assert.Equal(t, positionInterval, lp.positionCheckInterval())
+
+	// near home rate does not exceed poll interval
+	lp.SoC.Poll.Interval = time.Minute
+	assert.Equal(t, time.Minute, lp.positionCheckInterval())
+}
@@ -0,0 +1,87 @@
+package core
+
+import (
+	"errors"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// preconditionRetry is the minimum interval between climatisation start attempts
+const preconditionRetry = 5 * time.Minute
+
+// getDeparture provides synchronized access to the departure time
+func (lp *LoadPoint) getDeparture() time.Time {
+	lp.Lock()
+	defer lp.Unlock()
+	return lp.departure
+}
+
+// setPreconditioning updates and publishes the preconditioning state
+func (lp *LoadPoint) setPreconditioning(active bool) {
+	lp.preconditioning = active
+	lp.publish("preconditioning", active)
+}
+
+// resetPreconditioning clears the preconditioning state when the vehicle is disconnected or changed.
+// Running climatisation is left to the vehicle.
+func (lp *LoadPoint) resetPreconditioning() {
+	lp.preconditionTry = time.Time{}
+	if lp.preconditioning {
+		lp.setPreconditioning(false)
+	}
+}
+
+// precondition starts vehicle climatisation the configured duration before the target time.
+// Climatisation is only started while the vehicle is connected to use charger instead of battery energy.
+// Climatisation is stopped if the target time is removed before departure.
+// Start is not deferred for PV surplus or cheap grid energy as the vehicle must be ready at departure.
+func (lp *LoadPoint) precondition() {
+	cc, ok := lp.vehicle.(api.VehicleClimateController)
+	if !ok || lp.Precondition == 0 {
+		return
+	}
+
+	departure := lp.getDeparture()
+	now := lp.clock.Now()
+
+	switch {
+	case departure.IsZero():
+		// target time removed
+		if lp.preconditioning {
+			if err := cc.StopClimate(); err != nil {
+				lp.log.ERROR.Printf("precondition: %v", err)
+				return
+			}
+
+			lp.log.DEBUG.Println("precondition: stopped")
+			lp.setPreconditioning(false)
+		}
+
+	case !now.Before(departure):
+		// departed, climatisation is left to the vehicle
+		lp.Lock()
+		if lp.departure.Equal(departure) {
+			lp.departure = time.Time{}
+		}
+		lp.Unlock()
+
+		if lp.preconditioning {
+			lp.setPreconditioning(false)
+		}
+
+	case !lp.preconditioning && lp.connected() && now.After(departure.Add(-lp.Precondition)) &&
+		now.Sub(lp.preconditionTry) >= preconditionRetry:
+		lp.preconditionTry = now
+
+		if err := cc.StartClimate(); err != nil {
+			if !errors.Is(err, api.ErrMustRetry) {
+				lp.log.ERROR.Printf("precondition: %v", err)
+			}
+			return
+		}
+
+		lp.log.INFO.Printf("precondition: started for departure at %v", departure.Round(time.Minute).Local())
+		lp.setPreconditioning(true)
+	}
+}
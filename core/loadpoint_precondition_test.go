@@ -0,0 +1,128 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/mock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type climateVehicle struct {
+	*mock.MockVehicle
+	active bool
+	starts int
+}
+
+func (v *climateVehicle) StartClimate() error {
+	v.active = true
+	v.starts++
+	return nil
+}
+
+func (v *climateVehicle) StopClimate() error {
+	v.active = false
+	return nil
+}
+
+func TestPrecondition(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	clck := clock.NewMock()
+
+	vehicle := &climateVehicle{MockVehicle: mock.NewMockVehicle(ctrl)}
+
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.clock = clck
+	lp.vehicle = vehicle
+	lp.status = api.StatusB
+	lp.Precondition = 15 * time.Minute
+
+	// populate channels
+	x, y, z := createChannels(t)
+	attachChannels(lp, x, y, z)
+
+	departure := clck.Now().Add(time.Hour)
+	lp.SetTargetCharge(departure, 80)
+
+	// too early
+	lp.precondition()
+	assert.False(t, vehicle.active)
+
+	// within precondition window
+	clck.Add(50 * time.Minute)
+	lp.precondition()
+	assert.True(t, vehicle.active)
+	assert.True(t, lp.preconditioning)
+
+	// started only once
+	clck.Add(5 * time.Minute)
+	lp.precondition()
+	assert.Equal(t, 1, vehicle.starts)
+
+	// departed, climatisation left running
+	clck.Add(5 * time.Minute)
+	lp.precondition()
+	assert.True(t, vehicle.active)
+	assert.False(t, lp.preconditioning)
+	assert.True(t, lp.departure.IsZero())
+
+	// target removed while preconditioning
+	lp.SetTargetCharge(clck.Now().Add(10*time.Minute), 80)
+	lp.precondition()
+	assert.Equal(t, 2, vehicle.starts)
+
+	lp.SetTargetCharge(time.Time{}, 80)
+	lp.precondition()
+	assert.False(t, vehicle.active)
+	assert.False(t, lp.preconditioning)
+}
+
+func TestPreconditionDisconnected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	clck := clock.NewMock()
+
+	vehicle := &climateVehicle{MockVehicle: mock.NewMockVehicle(ctrl)}
+
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.clock = clck
+	lp.vehicle = vehicle
+	lp.status = api.StatusA
+	lp.Precondition = 15 * time.Minute
+
+	x, y, z := createChannels(t)
+	attachChannels(lp, x, y, z)
+
+	lp.SetTargetCharge(clck.Now().Add(10*time.Minute), 80)
+	lp.precondition()
+	assert.False(t, vehicle.active)
+}
+
+func TestPreconditionReset(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	clck := clock.NewMock()
+
+	vehicle := &climateVehicle{MockVehicle: mock.NewMockVehicle(ctrl)}
+	vehicle.MockVehicle.EXPECT().Title().Return("foo").AnyTimes()
+
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.clock = clck
+	lp.vehicle = vehicle
+	lp.status = api.StatusB
+	lp.Precondition = 15 * time.Minute
+
+	x, y, z := createChannels(t)
+	attachChannels(lp, x, y, z)
+
+	lp.SetTargetCharge(clck.Now().Add(10*time.Minute), 80)
+	lp.precondition()
+	assert.True(t, lp.preconditioning)
+
+	// vehicle changed
+	lp.setActiveVehicle(nil)
+	assert.False(t, lp.preconditioning)
+	assert.True(t, lp.preconditionTry.IsZero())
+}
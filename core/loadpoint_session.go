@@ -103,3 +103,48 @@ func (lp *LoadPoint) clearSession() {
 
 	lp.session = nil
 }
+
+// updateStartSnapshot updates the pending start snapshot and persists it once soc and odometer of the session are known
+func (lp *LoadPoint) updateStartSnapshot(opt func(*db.Snapshot)) {
+	if lp.startSnapshot == nil {
+		return
+	}
+
+	opt(lp.startSnapshot)
+
+	if lp.snapshotComplete(lp.startSnapshot) {
+		lp.persistSnapshot(lp.startSnapshot)
+		lp.startSnapshot = nil
+	}
+}
+
+// snapshotComplete checks if soc and, if supported by the vehicle, odometer are available
+func (lp *LoadPoint) snapshotComplete(snapshot *db.Snapshot) bool {
+	if snapshot.SoC == 0 {
+		return false
+	}
+
+	_, ok := lp.vehicle.(api.VehicleOdometer)
+	return !ok || snapshot.Odometer > 0
+}
+
+// persistSnapshot persists the vehicle's odometer and soc for trip and efficiency statistics.
+// Incomplete snapshots are discarded.
+func (lp *LoadPoint) persistSnapshot(snapshot *db.Snapshot) {
+	// test guard
+	if lp.db == nil || lp.session == nil || lp.vehicle == nil {
+		return
+	}
+
+	if !lp.snapshotComplete(snapshot) {
+		lp.log.DEBUG.Printf("%s snapshot: soc or odometer not available", snapshot.Event)
+		return
+	}
+
+	snapshot.Created = lp.clock.Now()
+	snapshot.Vehicle = lp.vehicle.Title()
+	snapshot.Capacity = lp.vehicle.Capacity()
+	snapshot.ChargedEnergy = lp.session.ChargedEnergy
+
+	lp.db.Persist(snapshot)
+}
@@ -0,0 +1,85 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/core/db"
+	"github.com/evcc-io/evcc/mock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type testDatabase struct {
+	persisted []any
+}
+
+func (d *testDatabase) Session(meter float64) *db.Session {
+	return &db.Session{MeterStart: meter}
+}
+
+func (d *testDatabase) Persist(session any) {
+	d.persisted = append(d.persisted, session)
+}
+
+type odometerVehicle struct {
+	*mock.MockVehicle
+	odometer float64
+}
+
+func (v *odometerVehicle) Odometer() (float64, error) {
+	return v.odometer, nil
+}
+
+func TestStartSnapshot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	vehicle := &odometerVehicle{MockVehicle: mock.NewMockVehicle(ctrl), odometer: 1000}
+	vehicle.MockVehicle.EXPECT().Title().Return("foo").AnyTimes()
+	vehicle.MockVehicle.EXPECT().Capacity().Return(50.0).AnyTimes()
+
+	database := new(testDatabase)
+
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.clock = clock.NewMock()
+	lp.vehicle = vehicle
+	lp.db = database
+	lp.session = database.Session(0)
+
+	x, y, z := createChannels(t)
+	attachChannels(lp, x, y, z)
+
+	lp.startSnapshot = &db.Snapshot{Event: db.SnapshotStart}
+
+	// soc not read yet
+	lp.vehicleOdometer()
+	assert.Empty(t, database.persisted)
+
+	lp.vehicleSoc = 40
+	lp.updateStartSnapshot(func(snapshot *db.Snapshot) {
+		snapshot.SoC = lp.vehicleSoc
+	})
+
+	// persisted once
+	lp.vehicleOdometer()
+	assert.Nil(t, lp.startSnapshot)
+
+	var snapshots []*db.Snapshot
+	for _, p := range database.persisted {
+		if s, ok := p.(*db.Snapshot); ok {
+			snapshots = append(snapshots, s)
+		}
+	}
+
+	if assert.Len(t, snapshots, 1) {
+		assert.Equal(t, db.SnapshotStart, snapshots[0].Event)
+		assert.Equal(t, 1000.0, snapshots[0].Odometer)
+		assert.Equal(t, 40.0, snapshots[0].SoC)
+		assert.Equal(t, "foo", snapshots[0].Vehicle)
+	}
+
+	// zero values are never persisted
+	lp.persistSnapshot(&db.Snapshot{Event: db.SnapshotStop, Odometer: 1000})
+	assert.Len(t, database.persisted, 1)
+}
@@ -0,0 +1,94 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/signature"
+)
+
+// minSignatureConfidence is the confidence required for identifying a vehicle by its charging signature
+const minSignatureConfidence = 0.8
+
+// signatureKey returns the settings key of the vehicle's learned charging signature
+func signatureKey(vehicle api.Vehicle) string {
+	return fmt.Sprintf("vehicle.%s.signature", vehicle.Title())
+}
+
+// loadSignature returns the vehicle's learned charging signature
+func (lp *LoadPoint) loadSignature(vehicle api.Vehicle) signature.Signature {
+	var res signature.Signature
+	if lp.signatureStore != nil {
+		// signature not yet learned
+		_ = lp.signatureStore(signatureKey(vehicle)).Load(&res)
+	}
+	return res
+}
+
+// learnSignature merges the observed charging signature into the active vehicle's signature
+// and resets the observation. Only confirmed vehicles learn, default vehicles and signature
+// matches could otherwise reinforce a wrong identity.
+func (lp *LoadPoint) learnSignature() {
+	// identity must be confirmed again for the next session
+	lp.Lock()
+	confirmed := lp.vehicleConfirmed
+	lp.vehicleConfirmed = false
+	lp.Unlock()
+
+	if o := lp.observation; o != nil && o.Complete() && confirmed && lp.vehicle != nil && lp.signatureStore != nil {
+		res := lp.loadSignature(lp.vehicle)
+		res.Learn(o.Signature())
+
+		if err := lp.signatureStore(signatureKey(lp.vehicle)).Save(res); err != nil {
+			lp.log.ERROR.Printf("vehicle signature: %v", err)
+		}
+	}
+
+	lp.observation = nil
+	lp.signatureChecked = false
+}
+
+// observeSignature samples the charging currents and identifies the vehicle once the observation is complete
+func (lp *LoadPoint) observeSignature() {
+	if lp.observation == nil || !lp.charging() || lp.chargeCurrents == nil {
+		return
+	}
+
+	lp.observation.Add(lp.clock.Now(), lp.chargeCurrents, lp.chargeCurrent, lp.GetPhases())
+
+	if lp.Signature && !lp.signatureChecked && lp.observation.Complete() {
+		lp.signatureChecked = true
+		lp.identifyVehicleBySignature()
+	}
+}
+
+// identifyVehicleBySignature selects the vehicle best matching the observed charging signature.
+// Vehicles identified by id or status are retained, the default vehicle remains active if no vehicle matches.
+func (lp *LoadPoint) identifyVehicleBySignature() {
+	if lp.vehicleIdentifier != "" || lp.vehicle != nil && lp.vehicle != lp.defaultVehicle {
+		return
+	}
+
+	vehicles := lp.coordinator.AvailableVehicles()
+
+	learned := make([]signature.Signature, 0, len(vehicles))
+	for _, v := range vehicles {
+		learned = append(learned, lp.loadSignature(v))
+	}
+
+	observed := lp.observation.Signature()
+
+	idx, confidence := signature.Best(observed, learned)
+	lp.publish(vehicleConfidence, confidence)
+
+	if idx < 0 || confidence < minSignatureConfidence {
+		lp.log.DEBUG.Printf("vehicle signature: no match for %dp, %.1fA, %v ramp-up", observed.Phases, observed.MaxCurrent, observed.RampUp)
+		return
+	}
+
+	vehicle := vehicles[idx]
+	lp.log.DEBUG.Printf("vehicle signature: %s (%.0f%% confidence)", vehicle.Title(), 100*confidence)
+
+	lp.stopVehicleDetection()
+	lp.setActiveVehicle(vehicle)
+}
@@ -0,0 +1,144 @@
+package core
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/api/store"
+	"github.com/evcc-io/evcc/core/coordinator"
+	"github.com/evcc-io/evcc/core/signature"
+	"github.com/evcc-io/evcc/mock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memStore map[string][]byte
+
+func (m memStore) provider(key string) store.Store {
+	return &memStoreKey{m, key}
+}
+
+type memStoreKey struct {
+	m   memStore
+	key string
+}
+
+func (s *memStoreKey) Load(res any) error {
+	return json.Unmarshal(s.m[s.key], res)
+}
+
+func (s *memStoreKey) Save(val any) error {
+	var err error
+	s.m[s.key], err = json.Marshal(val)
+	return err
+}
+
+func TestIdentifyVehicleBySignature(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	clck := clock.NewMock()
+
+	newVehicle := func(title string) *mock.MockVehicle {
+		v := mock.NewMockVehicle(ctrl)
+		v.EXPECT().Title().Return(title).AnyTimes()
+		v.EXPECT().Icon().Return("").AnyTimes()
+		v.EXPECT().Capacity().AnyTimes()
+		v.EXPECT().Phases().AnyTimes()
+		v.EXPECT().OnIdentified().AnyTimes()
+		return v
+	}
+
+	dflt := newVehicle("default")
+	v1 := newVehicle("single")
+	v2 := newVehicle("triple")
+
+	st := make(memStore)
+	for v, s := range map[api.Vehicle]signature.Signature{
+		v1: {Phases: 1, MaxCurrent: 16, RampUp: time.Minute, Samples: 1},
+		v2: {Phases: 3, MaxCurrent: 11, RampUp: 2 * time.Minute, Samples: 1},
+	} {
+		require.NoError(t, st.provider(signatureKey(v)).Save(s))
+	}
+
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.clock = clck
+	lp.coordinator = coordinator.NewAdapter(lp, coordinator.New(util.NewLogger("foo"), []api.Vehicle{dflt, v1, v2}))
+	lp.defaultVehicle = dflt
+	lp.signatureStore = st.provider
+	lp.Signature = true
+	lp.status = api.StatusC
+	lp.phases = 3
+	lp.chargeCurrent = 16
+
+	// populate channels
+	x, y, z := createChannels(t)
+	attachChannels(lp, x, y, z)
+
+	lp.setActiveVehicle(dflt)
+	lp.observation = signature.NewObservation(clck.Now())
+
+	for _, cur := range []float64{3, 7, 10.9} {
+		lp.chargeCurrents = []float64{cur, cur, cur}
+		lp.observeSignature()
+		assert.Same(t, dflt, lp.vehicle)
+		clck.Add(time.Minute)
+	}
+
+	// observation complete
+	lp.chargeCurrents = []float64{11, 11, 11}
+	lp.observeSignature()
+	assert.Same(t, v2, lp.vehicle)
+
+	// signature not learned for vehicle identified by signature only
+	lp.learnSignature()
+	assert.Equal(t, 1, lp.loadSignature(v2).Samples)
+	assert.Nil(t, lp.observation)
+}
+
+func TestLearnSignature(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	clck := clock.NewMock()
+
+	vehicle := mock.NewMockVehicle(ctrl)
+	vehicle.EXPECT().Title().Return("foo").AnyTimes()
+
+	st := make(memStore)
+
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.clock = clck
+	lp.vehicle = vehicle
+	lp.signatureStore = st.provider
+	lp.status = api.StatusC
+	lp.phases = 1
+	lp.chargeCurrent = 16
+
+	observe := func() {
+		lp.observation = signature.NewObservation(clck.Now())
+		for _, cur := range []float64{8, 16} {
+			lp.chargeCurrents = []float64{cur, 0, 0}
+			lp.observeSignature()
+			clck.Add(3 * time.Minute)
+		}
+		require.True(t, lp.observation.Complete())
+	}
+
+	// unconfirmed vehicle
+	observe()
+	lp.learnSignature()
+	assert.Equal(t, 0, lp.loadSignature(vehicle).Samples)
+
+	// confirmed vehicle
+	observe()
+	lp.confirmVehicle()
+	lp.learnSignature()
+	assert.Equal(t, 1, lp.loadSignature(vehicle).Samples)
+
+	// confirmation ends with the session
+	observe()
+	lp.learnSignature()
+	assert.Equal(t, 1, lp.loadSignature(vehicle).Samples)
+}
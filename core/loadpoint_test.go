@@ -694,6 +694,19 @@ func TestSoCPoll(t *testing.T) {
 		{pollConnected, api.StatusC, tNoRefresh, true}, // cached by vehicle
 		{pollConnected, api.StatusC, tRefresh, true},
 
+		// pollHome without vehicle position
+		{pollHome, api.StatusA, -1, false},
+		{pollHome, api.StatusA, 0, false},
+		{pollHome, api.StatusA, tRefresh, false},
+		{pollHome, api.StatusB, -1, true},
+		{pollHome, api.StatusB, 0, false},
+		{pollHome, api.StatusB, tNoRefresh, false},
+		{pollHome, api.StatusB, tRefresh, true},
+		{pollHome, api.StatusC, -1, true},
+		{pollHome, api.StatusC, 0, true},
+		{pollHome, api.StatusC, tNoRefresh, true}, // cached by vehicle
+		{pollHome, api.StatusC, tRefresh, true},
+
 		// pollAlways
 		{pollAlways, api.StatusA, -1, true},
 		{pollAlways, api.StatusA, 0, false},
@@ -771,3 +784,64 @@ func TestMinSoC(t *testing.T) {
 		}
 	}
 }
+
+func TestRemotePowerLimit(t *testing.T) {
+	clck := clock.NewMock()
+	ctrl := gomock.NewController(t)
+	charger := mock.NewMockCharger(ctrl)
+
+	lp := &LoadPoint{
+		log:         util.NewLogger("foo"),
+		bus:         evbus.New(),
+		clock:       clck,
+		charger:     charger,
+		chargeMeter: &Null{}, // silence nil panics
+		chargeRater: &Null{}, // silence nil panics
+		chargeTimer: &Null{}, // silence nil panics
+		wakeUpTimer: NewTimer(),
+		MinCurrent:  minA,
+		MaxCurrent:  maxA,
+		phases:      1,
+		status:      api.StatusC, // no status change
+		Mode:        api.ModePV,
+		Disable:     ThresholdConfig{Delay: time.Minute},
+	}
+
+	attachListeners(t, lp)
+
+	lp.RemotePowerLimit("test", 8*230)
+
+	charger.EXPECT().Status().Return(api.StatusC, nil).AnyTimes()
+	charger.EXPECT().Enabled().Return(true, nil)
+	charger.EXPECT().MaxCurrent(int64(8)).Return(nil)
+
+	lp.Update(-10000, false, false)
+	ctrl.Finish()
+
+	// limit below min current starts pv disable timer
+	lp.RemotePowerLimit("test", 4*230)
+
+	charger.EXPECT().Enabled().Return(true, nil).AnyTimes()
+	charger.EXPECT().MaxCurrent(int64(minA)).Return(nil)
+
+	lp.Update(-10000, false, false)
+	ctrl.Finish()
+
+	clck.Add(time.Minute)
+	lp.RemotePowerLimit("test", 4*230)
+
+	charger.EXPECT().Enable(false).Return(nil)
+
+	lp.Update(-10000, false, false)
+	ctrl.Finish()
+
+	// limit expires unless repeated
+	if limit := lp.getRemotePowerLimit(); limit != 4*230 {
+		t.Errorf("expected limit, got %.0fW", limit)
+	}
+
+	clck.Add(remoteLimitTimeout + time.Second)
+	if limit := lp.getRemotePowerLimit(); limit != 0 {
+		t.Errorf("expected expired limit, got %.0fW", limit)
+	}
+}
@@ -387,3 +387,113 @@ func TestReconnectVehicle(t *testing.T) {
 		t.Error("vehicle should be detected")
 	}
 }
+
+type socLimitVehicle struct {
+	*mock.MockVehicle
+	soc int
+	err error
+}
+
+func (v *socLimitVehicle) SetTargetSoC(soc int) error {
+	if v.err != nil {
+		return v.err
+	}
+	v.soc = soc
+	return nil
+}
+
+func TestVehicleSocLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	clck := clock.NewMock()
+
+	vehicle := &socLimitVehicle{MockVehicle: mock.NewMockVehicle(ctrl)}
+
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.clock = clck
+	lp.vehicle = vehicle
+	lp.status = api.StatusB
+
+	// populate channels
+	x, y, z := createChannels(t)
+	attachChannels(lp, x, y, z)
+
+	// disabled
+	lp.SetTargetSoC(90)
+	lp.vehicleSocLimit()
+	assert.Equal(t, 0, vehicle.soc)
+
+	// enabled
+	lp.SoC.VehicleLimit = true
+	lp.SetTargetSoC(80)
+	lp.vehicleSocLimit()
+	assert.Equal(t, 80, vehicle.soc)
+
+	// unchanged target
+	vehicle.soc = 0
+	lp.SetTargetSoC(80)
+	lp.vehicleSocLimit()
+	assert.Equal(t, 0, vehicle.soc)
+
+	// not connected
+	lp.status = api.StatusA
+	lp.SetTargetSoC(70)
+	lp.vehicleSocLimit()
+	assert.Equal(t, 0, vehicle.soc)
+
+	// applied when connected
+	lp.status = api.StatusB
+	lp.vehicleSocLimit()
+	assert.Equal(t, 70, vehicle.soc)
+
+	// re-applied on connect
+	vehicle.soc = 0
+	lp.Lock()
+	lp.requestVehicleSocLimit()
+	lp.Unlock()
+	lp.vehicleSocLimit()
+	assert.Equal(t, 70, vehicle.soc)
+
+	// retry
+	vehicle.soc = 0
+	vehicle.err = api.ErrMustRetry
+	lp.SetTargetSoC(60)
+	lp.vehicleSocLimit()
+	assert.Equal(t, 0, vehicle.soc)
+
+	vehicle.err = nil
+	lp.vehicleSocLimit()
+	assert.Equal(t, 0, vehicle.soc, "retry delay")
+
+	clck.Add(socLimitRetryDelay)
+	lp.vehicleSocLimit()
+	assert.Equal(t, 60, vehicle.soc)
+
+	// other errors are retried
+	vehicle.soc = 0
+	vehicle.err = errors.New("foo")
+	lp.SetTargetSoC(50)
+	lp.vehicleSocLimit()
+
+	vehicle.err = nil
+	clck.Add(socLimitRetryDelay)
+	lp.vehicleSocLimit()
+	assert.Equal(t, 50, vehicle.soc)
+
+	// retries are limited
+	vehicle.soc = 0
+	vehicle.err = api.ErrMustRetry
+	lp.SetTargetSoC(40)
+	for i := 0; i < socLimitMaxAttempts; i++ {
+		lp.vehicleSocLimit()
+		clck.Add(socLimitRetryDelay)
+	}
+
+	vehicle.err = nil
+	lp.vehicleSocLimit()
+	assert.Equal(t, 0, vehicle.soc, "given up")
+
+	// new request is attempted again
+	lp.SetTargetSoC(30)
+	lp.vehicleSocLimit()
+	assert.Equal(t, 30, vehicle.soc)
+}
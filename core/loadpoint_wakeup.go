@@ -0,0 +1,216 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/db"
+)
+
+// Wake-up methods
+const (
+	wakeUpCharger = "charger" // charger CP interrupt
+	wakeUpVehicle = "vehicle" // vehicle api
+	wakeUpPhases  = "phases"  // phase switching pulse
+
+	wakeUpBackoff    = 2 * time.Minute
+	wakeUpMaxBackoff = 2 * time.Hour
+)
+
+// WakeUpConfig defines the vehicle wake-up strategy
+type WakeUpConfig struct {
+	Methods []string      `mapstructure:"methods"` // methods used in turn, defaults to charger or vehicle
+	Retries int           `mapstructure:"retries"` // attempts after the first one
+	Backoff time.Duration `mapstructure:"backoff"` // delay before the first retry, doubled with each further retry
+}
+
+// validate normalizes the wake-up methods
+func (c *WakeUpConfig) validate() error {
+	for i, m := range c.Methods {
+		switch c.Methods[i] = strings.ToLower(m); c.Methods[i] {
+		case wakeUpCharger, wakeUpVehicle, wakeUpPhases:
+		default:
+			return fmt.Errorf("invalid wake-up method: %s", m)
+		}
+	}
+
+	if c.Retries < 0 {
+		c.Retries = 0
+	}
+
+	return nil
+}
+
+// wakeUpMethods returns the configured wake-up methods supported by charger and vehicle
+func (lp *LoadPoint) wakeUpMethods() []string {
+	_, charger := lp.charger.(api.Resurrector)
+	_, vehicle := lp.vehicle.(api.Resurrector)
+	_, phases := lp.charger.(api.PhaseSwitcher)
+
+	// default to a single method, charger first
+	if len(lp.WakeUp.Methods) == 0 {
+		switch {
+		case charger:
+			return []string{wakeUpCharger}
+		case vehicle:
+			return []string{wakeUpVehicle}
+		default:
+			return nil
+		}
+	}
+
+	var res []string
+	for _, m := range lp.WakeUp.Methods {
+		if m == wakeUpCharger && charger || m == wakeUpVehicle && vehicle || m == wakeUpPhases && phases {
+			res = append(res, m)
+		}
+	}
+
+	return res
+}
+
+// startWakeUp starts the wake-up timer for the first attempt
+func (lp *LoadPoint) startWakeUp() {
+	// charger re-enabled after phase pulse, continue pending retries
+	if lp.wakeUpPulsed {
+		lp.wakeUpPulsed = false
+		return
+	}
+
+	lp.log.DEBUG.Printf("wake-up timer: start")
+	lp.wakeUpAttempts = 0
+	lp.wakeUpTimer.Start()
+}
+
+// stopWakeUp stops the wake-up timer and cancels pending retries
+func (lp *LoadPoint) stopWakeUp() {
+	lp.wakeUpPulsed = false
+	lp.wakeUpAttempts = 0
+	lp.wakeUpTimer.Stop()
+}
+
+// wakeUpDue returns true if the next wake-up attempt is due
+func (lp *LoadPoint) wakeUpDue() bool {
+	if lp.wakeUpAttempts == 0 {
+		return lp.wakeUpTimer.Expired()
+	}
+
+	return lp.wakeUpAttempts <= lp.WakeUp.Retries && !lp.clock.Now().Before(lp.wakeUpNext)
+}
+
+// wakeUpDelay returns the backoff delay after given number of attempts
+func (lp *LoadPoint) wakeUpDelay(attempts int) time.Duration {
+	res := lp.WakeUp.Backoff
+	for i := 1; i < attempts && res < wakeUpMaxBackoff; i++ {
+		res *= 2
+	}
+
+	if res > wakeUpMaxBackoff {
+		res = wakeUpMaxBackoff
+	}
+
+	return res
+}
+
+// wakeUpVehicle executes the next wake-up attempt, rotating through the available methods
+func (lp *LoadPoint) wakeUpVehicle() {
+	methods := lp.wakeUpMethods()
+	if len(methods) == 0 {
+		return
+	}
+
+	method := methods[lp.wakeUpAttempts%len(methods)]
+
+	lp.wakeUpAttempts++
+	lp.wakeUpNext = lp.clock.Now().Add(lp.wakeUpDelay(lp.wakeUpAttempts))
+
+	var err error
+	switch method {
+	case wakeUpCharger:
+		err = lp.charger.(api.Resurrector).WakeUp()
+	case wakeUpVehicle:
+		err = lp.vehicle.(api.Resurrector).WakeUp()
+	case wakeUpPhases:
+		err = lp.wakeUpPulse()
+	}
+
+	outcome := "ok"
+	if err != nil {
+		lp.log.ERROR.Printf("wake-up %s: %v", method, err)
+		outcome = err.Error()
+	} else {
+		lp.log.DEBUG.Printf("wake-up %s: attempt %d", method, lp.wakeUpAttempts)
+	}
+
+	lp.updateSession(func(session *db.Session) {
+		session.WakeUps++
+		session.WakeUpLog = appendWakeUpLog(session.WakeUpLog, fmt.Sprintf("%s: %s", method, outcome))
+	})
+}
+
+// wakeUpSucceeded records charging started after wake-up attempts
+func (lp *LoadPoint) wakeUpSucceeded() {
+	if lp.wakeUpAttempts == 0 {
+		return
+	}
+
+	lp.log.DEBUG.Printf("wake-up: charging after %d attempt(s)", lp.wakeUpAttempts)
+
+	lp.updateSession(func(session *db.Session) {
+		session.WakeUpLog = appendWakeUpLog(session.WakeUpLog, "charging")
+	})
+}
+
+// appendWakeUpLog appends the entry to the session's wake-up log
+func appendWakeUpLog(log, entry string) string {
+	if log == "" {
+		return entry
+	}
+	return log + ", " + entry
+}
+
+// wakeUpPulse switches to the other phase configuration for a single update cycle
+func (lp *LoadPoint) wakeUpPulse() error {
+	phases := lp.GetPhases()
+	if phases == 0 {
+		return errors.New("unknown phases")
+	}
+
+	pulse := 3
+	if phases == 3 {
+		pulse = 1
+	}
+
+	if err := lp.wakeUpScalePhases(pulse); err != nil {
+		return err
+	}
+
+	lp.addTask(func() {
+		lp.wakeUpRestorePhases(phases)
+	})
+
+	return nil
+}
+
+// wakeUpScalePhases switches phases while keeping pending wake-up retries.
+// The charger is disabled while switching and re-enabled by the next update.
+func (lp *LoadPoint) wakeUpScalePhases(phases int) error {
+	attempts, next := lp.wakeUpAttempts, lp.wakeUpNext
+
+	err := lp.scalePhases(phases)
+
+	lp.wakeUpAttempts, lp.wakeUpNext = attempts, next
+	lp.wakeUpPulsed = !lp.enabled
+
+	return err
+}
+
+// wakeUpRestorePhases switches back to the enabled phases after a wake-up pulse
+func (lp *LoadPoint) wakeUpRestorePhases(phases int) {
+	if err := lp.wakeUpScalePhases(phases); err != nil {
+		lp.log.ERROR.Printf("wake-up %s: %v", wakeUpPhases, err)
+	}
+}
@@ -0,0 +1,126 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/mock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type resurrectorCharger struct {
+	*mock.MockCharger
+	*mock.MockPhaseSwitcher
+	wakeUps int
+}
+
+func (c *resurrectorCharger) WakeUp() error {
+	c.wakeUps++
+	return nil
+}
+
+type resurrectorVehicle struct {
+	*mock.MockVehicle
+	wakeUps int
+}
+
+func (v *resurrectorVehicle) WakeUp() error {
+	v.wakeUps++
+	return nil
+}
+
+func TestWakeUpMethods(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	charger := &resurrectorCharger{MockCharger: mock.NewMockCharger(ctrl), MockPhaseSwitcher: mock.NewMockPhaseSwitcher(ctrl)}
+	vehicle := &resurrectorVehicle{MockVehicle: mock.NewMockVehicle(ctrl)}
+
+	lp := &LoadPoint{charger: charger, vehicle: vehicle}
+	assert.Equal(t, []string{wakeUpCharger}, lp.wakeUpMethods())
+
+	lp.charger = charger.MockCharger
+	assert.Equal(t, []string{wakeUpVehicle}, lp.wakeUpMethods())
+
+	// unsupported methods are skipped
+	lp.WakeUp.Methods = []string{wakeUpPhases, wakeUpCharger, wakeUpVehicle}
+	assert.Equal(t, []string{wakeUpVehicle}, lp.wakeUpMethods())
+
+	assert.Error(t, (&WakeUpConfig{Methods: []string{"foo"}}).validate())
+}
+
+func TestWakeUpRetries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	clck := clock.NewMock()
+
+	charger := &resurrectorCharger{MockCharger: mock.NewMockCharger(ctrl), MockPhaseSwitcher: mock.NewMockPhaseSwitcher(ctrl)}
+	vehicle := &resurrectorVehicle{MockVehicle: mock.NewMockVehicle(ctrl)}
+	vehicle.MockVehicle.EXPECT().Phases().AnyTimes()
+
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.clock = clck
+	lp.wakeUpTimer = &Timer{clck: clck}
+	lp.charger = charger
+	lp.vehicle = vehicle
+	lp.phases = 1
+	lp.enabled = true
+	lp.WakeUp = WakeUpConfig{
+		Methods: []string{wakeUpVehicle, wakeUpCharger, wakeUpPhases},
+		Retries: 3,
+		Backoff: time.Minute,
+	}
+
+	lp.startWakeUp()
+	assert.False(t, lp.wakeUpDue())
+
+	// vehicle after timeout
+	clck.Add(wakeupTimeout)
+	assert.True(t, lp.wakeUpDue())
+	lp.wakeUpVehicle()
+	assert.Equal(t, 1, vehicle.wakeUps)
+
+	// charger after backoff
+	clck.Add(30 * time.Second)
+	assert.False(t, lp.wakeUpDue())
+	clck.Add(30 * time.Second)
+	assert.True(t, lp.wakeUpDue())
+	lp.wakeUpVehicle()
+	assert.Equal(t, 1, charger.wakeUps)
+
+	// 3p pulse after doubled backoff, restored with next task
+	clck.Add(time.Minute)
+	assert.False(t, lp.wakeUpDue())
+	clck.Add(time.Minute)
+	assert.True(t, lp.wakeUpDue())
+
+	charger.MockCharger.EXPECT().Enable(false)
+	charger.MockPhaseSwitcher.EXPECT().Phases1p3p(3)
+	lp.wakeUpVehicle()
+	assert.Equal(t, 3, lp.GetPhases())
+	assert.False(t, lp.enabled)
+
+	charger.MockPhaseSwitcher.EXPECT().Phases1p3p(1)
+	lp.processTasks()
+	assert.Equal(t, 1, lp.GetPhases())
+
+	// pending retries kept when charger is re-enabled
+	lp.startWakeUp()
+	assert.Equal(t, 3, lp.wakeUpAttempts)
+
+	// vehicle again, retries exhausted afterwards
+	clck.Add(4 * time.Minute)
+	assert.True(t, lp.wakeUpDue())
+	lp.wakeUpVehicle()
+	assert.Equal(t, 2, vehicle.wakeUps)
+
+	clck.Add(time.Hour)
+	assert.False(t, lp.wakeUpDue())
+
+	// restart when charger is enabled again
+	lp.stopWakeUp()
+	lp.startWakeUp()
+	clck.Add(wakeupTimeout)
+	assert.True(t, lp.wakeUpDue())
+}
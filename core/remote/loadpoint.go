@@ -0,0 +1,205 @@
+package remote
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/loadpoint"
+)
+
+// voltage is the nominal voltage used for power estimation
+const voltage = 230
+
+// LoadPoint is a remote site's loadpoint
+type LoadPoint struct {
+	site *Site
+	id   int
+}
+
+var _ loadpoint.API = (*LoadPoint)(nil)
+
+// get returns a cached loadpoint value
+func (lp *LoadPoint) get(key string) interface{} {
+	return lp.site.cache.Get(strconv.Itoa(lp.id) + "." + key).Val
+}
+
+// post forwards a setter to the remote loadpoint api
+func (lp *LoadPoint) post(method, path string) error {
+	err := lp.site.post(method, fmt.Sprintf("loadpoints/%d/%s", lp.id, path))
+	if err != nil {
+		lp.site.log.WARN.Printf("%s loadpoint %d: %v", lp.site.title, lp.id+1, err)
+	}
+	return err
+}
+
+// Name returns the remote loadpoint's name
+func (lp *LoadPoint) Name() string {
+	return stringVal(lp.get("title"))
+}
+
+// GetStatus returns the charging status
+func (lp *LoadPoint) GetStatus() api.ChargeStatus {
+	switch {
+	case boolVal(lp.get("charging")):
+		return api.StatusC
+	case boolVal(lp.get("connected")):
+		return api.StatusB
+	default:
+		return api.StatusA
+	}
+}
+
+// GetMode returns the charge mode
+func (lp *LoadPoint) GetMode() api.ChargeMode {
+	return api.ChargeMode(stringVal(lp.get("mode")))
+}
+
+// SetMode sets the charge mode
+func (lp *LoadPoint) SetMode(mode api.ChargeMode) error {
+	return lp.post(http.MethodPost, "mode/"+string(mode))
+}
+
+// GetTargetEnergy returns the charge target energy
+func (lp *LoadPoint) GetTargetEnergy() float64 {
+	return floatVal(lp.get("targetEnergy"))
+}
+
+// SetTargetEnergy sets the charge target energy
+func (lp *LoadPoint) SetTargetEnergy(energy float64) error {
+	return lp.post(http.MethodPost, fmt.Sprintf("targetenergy/%g", energy))
+}
+
+// GetTargetSoC returns the charge target soc
+func (lp *LoadPoint) GetTargetSoC() int {
+	return intVal(lp.get("targetSoC"))
+}
+
+// SetTargetSoC sets the charge target soc
+func (lp *LoadPoint) SetTargetSoC(soc int) error {
+	return lp.post(http.MethodPost, fmt.Sprintf("targetsoc/%d", soc))
+}
+
+// GetMinSoC returns the charge minimum soc
+func (lp *LoadPoint) GetMinSoC() int {
+	return intVal(lp.get("minSoC"))
+}
+
+// SetMinSoC sets the charge minimum soc
+func (lp *LoadPoint) SetMinSoC(soc int) error {
+	return lp.post(http.MethodPost, fmt.Sprintf("minsoc/%d", soc))
+}
+
+// GetPhases returns the enabled phases
+func (lp *LoadPoint) GetPhases() int {
+	return intVal(lp.get("phasesConfigured"))
+}
+
+// SetPhases sets the enabled phases
+func (lp *LoadPoint) SetPhases(phases int) error {
+	return lp.post(http.MethodPost, fmt.Sprintf("phases/%d", phases))
+}
+
+// GetTargetTime returns the target charge time
+func (lp *LoadPoint) GetTargetTime() time.Time {
+	t, _ := time.Parse(time.RFC3339, stringVal(lp.get("targetTime")))
+	return t
+}
+
+// SetTargetCharge sets the charge targetSoC
+func (lp *LoadPoint) SetTargetCharge(finishAt time.Time, soc int) error {
+	if finishAt.IsZero() {
+		return lp.post(http.MethodDelete, "targetcharge")
+	}
+
+	return lp.post(http.MethodPost, fmt.Sprintf("targetcharge/%d/%s", soc, finishAt.UTC().Format(time.RFC3339)))
+}
+
+// RemoteControl sets remote status demand
+func (lp *LoadPoint) RemoteControl(source string, demand loadpoint.RemoteDemand) error {
+	if demand == loadpoint.RemoteEnable {
+		demand = "enable"
+	}
+	return lp.post(http.MethodPost, fmt.Sprintf("remotedemand/%s/%s", demand, url.PathEscape(source)))
+}
+
+// RemotePowerLimit sets remote power limit for pv mode. Not supported by the remote api.
+func (lp *LoadPoint) RemotePowerLimit(source string, power float64) error {
+	return api.ErrNotAvailable
+}
+
+// HasChargeMeter determines if a physical charge meter is attached
+func (lp *LoadPoint) HasChargeMeter() bool {
+	return boolVal(lp.get("chargeConfigured"))
+}
+
+// GetChargePower returns the current charging power
+func (lp *LoadPoint) GetChargePower() float64 {
+	return floatVal(lp.get("chargePower"))
+}
+
+// GetMinCurrent returns the min charging current
+func (lp *LoadPoint) GetMinCurrent() float64 {
+	return floatVal(lp.get("minCurrent"))
+}
+
+// SetMinCurrent sets the min charging current
+func (lp *LoadPoint) SetMinCurrent(current float64) error {
+	return lp.post(http.MethodPost, fmt.Sprintf("mincurrent/%g", current))
+}
+
+// GetMaxCurrent returns the max charging current
+func (lp *LoadPoint) GetMaxCurrent() float64 {
+	return floatVal(lp.get("maxCurrent"))
+}
+
+// SetMaxCurrent sets the max charging current
+func (lp *LoadPoint) SetMaxCurrent(current float64) error {
+	return lp.post(http.MethodPost, fmt.Sprintf("maxcurrent/%g", current))
+}
+
+// GetMinPower returns the min charging power for a single phase
+func (lp *LoadPoint) GetMinPower() float64 {
+	return voltage * lp.GetMinCurrent()
+}
+
+// GetMaxPower returns the max charging power taking active phases into account
+func (lp *LoadPoint) GetMaxPower() float64 {
+	phases := intVal(lp.get("phasesActive"))
+	if phases == 0 {
+		phases = 3
+	}
+	return voltage * lp.GetMaxCurrent() * float64(phases)
+}
+
+// GetRemainingDuration is the estimated remaining charging duration
+func (lp *LoadPoint) GetRemainingDuration() time.Duration {
+	return time.Duration(floatVal(lp.get("chargeRemainingDuration"))) * time.Second
+}
+
+// GetRemainingEnergy is the remaining charge energy in Wh
+func (lp *LoadPoint) GetRemainingEnergy() float64 {
+	return floatVal(lp.get("chargeRemainingEnergy"))
+}
+
+// SetVehicle sets the active vehicle by its title
+func (lp *LoadPoint) SetVehicle(vehicle api.Vehicle) error {
+	if vehicle == nil {
+		return lp.post(http.MethodDelete, "vehicle")
+	}
+
+	id, err := lp.site.vehicleIndex(vehicle.Title())
+	if err != nil {
+		return err
+	}
+
+	return lp.post(http.MethodPost, fmt.Sprintf("vehicle/%d", id))
+}
+
+// StartVehicleDetection allows triggering vehicle detection for debugging purposes
+func (lp *LoadPoint) StartVehicleDetection() error {
+	return lp.post(http.MethodPatch, "vehicle")
+}
@@ -0,0 +1,329 @@
+package remote
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/consumer"
+	"github.com/evcc-io/evcc/core/db"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	retryDelay = 5 * time.Second
+	maxDelay   = 5 * time.Minute // reconnect backoff limit
+	timeout    = 2 * time.Minute // remote considered unhealthy without updates
+)
+
+// ErrReadOnly is returned when controlling a read-only remote site
+var ErrReadOnly = errors.New("remote site is read-only")
+
+// Site is a remote evcc instance mirrored from its websocket stream.
+// It implements site.API. Setters are forwarded to the remote api if control is enabled.
+type Site struct {
+	*request.Helper
+	log     *util.Logger
+	title   string
+	uri     string
+	control bool
+
+	mu         sync.Mutex
+	cache      *util.Cache
+	updated    time.Time
+	loadpoints []loadpoint.API
+}
+
+var _ site.API = (*Site)(nil)
+
+// NewSiteFromConfig creates a remote site from configuration
+func NewSiteFromConfig(other map[string]interface{}) (*Site, error) {
+	cc := struct {
+		Title   string
+		URI     string
+		Control bool
+	}{}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	if cc.URI == "" {
+		return nil, errors.New("missing uri")
+	}
+
+	s := NewSite(cc.Title, cc.URI, cc.Control)
+	go s.listen()
+
+	return s, nil
+}
+
+// NewSite creates a remote site without connecting to it
+func NewSite(title, uri string, control bool) *Site {
+	log := util.NewLogger("remote")
+
+	uri = strings.TrimSuffix(util.DefaultScheme(uri, "http"), "/")
+	if title == "" {
+		title = uri
+	}
+
+	return &Site{
+		Helper:  request.NewHelper(log),
+		log:     log,
+		title:   title,
+		uri:     uri,
+		control: control,
+		cache:   util.NewCache(),
+	}
+}
+
+// Title returns the remote site's title
+func (s *Site) Title() string {
+	return s.title
+}
+
+// URI returns the remote site's base uri
+func (s *Site) URI() string {
+	return s.uri
+}
+
+// Controllable returns true if setters are forwarded to the remote site
+func (s *Site) Controllable() bool {
+	return s.control
+}
+
+// State provides a structured copy of the remote site's state in util.Cache format
+func (s *Site) State() map[string]interface{} {
+	return s.cache.State()
+}
+
+func (s *Site) listen() {
+	ws := "ws" + strings.TrimPrefix(s.uri, "http") + "/ws"
+
+	dialer := &websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: request.Timeout,
+	}
+
+	delay := retryDelay
+
+	for {
+		client, _, err := dialer.Dial(ws, nil)
+		if err != nil {
+			s.log.ERROR.Printf("%s: %v", s.title, err)
+			delay = s.backoff(delay)
+			continue
+		}
+
+		for {
+			_, b, err := client.ReadMessage()
+			if err != nil {
+				s.log.TRACE.Println("read:", err)
+				_ = client.Close()
+				break
+			}
+
+			// connection is established, reset backoff
+			delay = retryDelay
+
+			if err := s.update(b); err != nil {
+				s.log.ERROR.Printf("%s: %v", s.title, err)
+			}
+		}
+
+		delay = s.backoff(delay)
+	}
+}
+
+// backoff waits for delay and returns the doubled delay limited to maxDelay
+func (s *Site) backoff(delay time.Duration) time.Duration {
+	time.Sleep(delay)
+
+	if delay *= 2; delay > maxDelay {
+		delay = maxDelay
+	}
+
+	return delay
+}
+
+// update adds the websocket message's values to the cache
+func (s *Site) update(b []byte) error {
+	var msg map[string]interface{}
+	if err := json.Unmarshal(b, &msg); err != nil {
+		return err
+	}
+
+	for key, val := range msg {
+		p := util.Param{Key: key, Val: val}
+
+		if segs := strings.SplitN(key, ".", 3); len(segs) == 3 {
+			id, err := strconv.Atoi(segs[1])
+			if err != nil {
+				continue
+			}
+
+			switch segs[0] {
+			case "loadpoints":
+				p.LoadPoint = &id
+			case "consumers":
+				p.Consumer = &id
+			default:
+				continue
+			}
+
+			p.Key = segs[2]
+		}
+
+		s.cache.Add(p.UniqueID(), p)
+	}
+
+	s.mu.Lock()
+	s.updated = time.Now()
+	s.mu.Unlock()
+
+	return nil
+}
+
+// get returns a cached site value
+func (s *Site) get(key string) interface{} {
+	return s.cache.Get(key).Val
+}
+
+// post forwards a setter to the remote api
+func (s *Site) post(method, path string) error {
+	if !s.control {
+		return ErrReadOnly
+	}
+
+	req, err := request.New(method, s.uri+"/api/"+path, nil, request.JSONEncoding)
+	if err == nil {
+		_, err = s.DoBody(req)
+	}
+
+	return err
+}
+
+// Healthy returns true if the remote site is connected and sends updates
+func (s *Site) Healthy() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return time.Since(s.updated) < timeout
+}
+
+// LoadPoints returns the remote site's loadpoints
+func (s *Site) LoadPoints() []loadpoint.API {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := s.cache.State()
+	lps, _ := state["loadpoints"].([]map[string]interface{})
+
+	for id := len(s.loadpoints); id < len(lps); id++ {
+		s.loadpoints = append(s.loadpoints, &LoadPoint{site: s, id: id})
+	}
+
+	return s.loadpoints
+}
+
+// Consumers returns the remote site's consumers. Remote consumers are not mirrored.
+func (s *Site) Consumers() []consumer.API {
+	return nil
+}
+
+// GetBufferSoC returns the remote site's buffer soc
+func (s *Site) GetBufferSoC() float64 {
+	return floatVal(s.get("bufferSoC"))
+}
+
+// SetBufferSoC sets the remote site's buffer soc
+func (s *Site) SetBufferSoC(soc float64) error {
+	return s.post(http.MethodPost, fmt.Sprintf("buffersoc/%g", soc))
+}
+
+// GetPrioritySoC returns the remote site's priority soc
+func (s *Site) GetPrioritySoC() float64 {
+	return floatVal(s.get("prioritySoC"))
+}
+
+// SetPrioritySoC sets the remote site's priority soc
+func (s *Site) SetPrioritySoC(soc float64) error {
+	return s.post(http.MethodPost, fmt.Sprintf("prioritysoc/%g", soc))
+}
+
+// GetResidualPower returns the remote site's residual power
+func (s *Site) GetResidualPower() float64 {
+	return floatVal(s.get("residualPower"))
+}
+
+// SetResidualPower sets the remote site's residual power
+func (s *Site) SetResidualPower(power float64) error {
+	return s.post(http.MethodPost, fmt.Sprintf("residualpower/%g", power))
+}
+
+// GetVehicles returns the remote site's vehicles. Remote vehicles are not available as api.Vehicle.
+func (s *Site) GetVehicles() []api.Vehicle {
+	return nil
+}
+
+// vehicleIndex returns the remote index of the vehicle title
+func (s *Site) vehicleIndex(title string) (int, error) {
+	titles, _ := s.get("vehicles").([]interface{})
+	for id, t := range titles {
+		if t == title {
+			return id, nil
+		}
+	}
+
+	return 0, fmt.Errorf("vehicle not found: %s", title)
+}
+
+// Sessions returns the remote site's charging sessions.
+// Loadpoint names are prefixed with the site title.
+func (s *Site) Sessions() (db.Sessions, error) {
+	var res struct {
+		Result db.Sessions
+		Error  string
+	}
+
+	if err := s.GetJSON(s.uri+"/api/sessions", &res); err != nil {
+		if res.Error != "" {
+			err = errors.New(res.Error)
+		}
+		return nil, err
+	}
+
+	for i := range res.Result {
+		res.Result[i].Loadpoint = s.title + ": " + res.Result[i].Loadpoint
+	}
+
+	return res.Result, nil
+}
+
+func floatVal(v interface{}) float64 {
+	f, _ := v.(float64)
+	return f
+}
+
+func intVal(v interface{}) int {
+	return int(floatVal(v))
+}
+
+func boolVal(v interface{}) bool {
+	b, _ := v.(bool)
+	return b
+}
+
+func stringVal(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
@@ -0,0 +1,57 @@
+package remote
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSiteUpdate(t *testing.T) {
+	s := NewSite("", "192.0.2.1:7070", false)
+	assert.Equal(t, "http://192.0.2.1:7070", s.Title())
+	assert.False(t, s.Healthy())
+
+	msg := `{
+		"bufferSoC": 80,
+		"vehicles": ["foo", "bar"],
+		"loadpoints.0.title": "Garage",
+		"loadpoints.0.mode": "pv",
+		"loadpoints.0.connected": true,
+		"loadpoints.0.charging": true,
+		"loadpoints.0.targetSoC": 90,
+		"loadpoints.0.maxCurrent": 16,
+		"loadpoints.0.phasesActive": 1,
+		"loadpoints.0.chargeRemainingDuration": 60,
+		"loadpoints.1.title": "Carport"
+	}`
+
+	assert.NoError(t, s.update([]byte(msg)))
+	assert.True(t, s.Healthy())
+	assert.Equal(t, 80.0, s.GetBufferSoC())
+
+	lps := s.LoadPoints()
+	assert.Len(t, lps, 2)
+
+	lp := lps[0]
+	assert.Equal(t, "Garage", lp.Name())
+	assert.Equal(t, api.ModePV, lp.GetMode())
+	assert.Equal(t, api.StatusC, lp.GetStatus())
+	assert.Equal(t, 90, lp.GetTargetSoC())
+	assert.Equal(t, 230*16.0, lp.GetMaxPower())
+	assert.Equal(t, time.Minute, lp.GetRemainingDuration())
+	assert.Equal(t, api.StatusA, lps[1].GetStatus())
+
+	id, err := s.vehicleIndex("bar")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, id)
+}
+
+func TestSiteReadOnly(t *testing.T) {
+	s := NewSite("foo", "http://192.0.2.1:7070", false)
+	assert.ErrorIs(t, s.SetBufferSoC(50), ErrReadOnly)
+
+	_ = s.update([]byte(`{"loadpoints.0.title": "Garage"}`))
+	assert.ErrorIs(t, s.LoadPoints()[0].SetPhases(1), ErrReadOnly)
+}
@@ -0,0 +1,179 @@
+package signature
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	minActiveCurrent = 1.0              // A, minimum current for an active phase
+	limitTolerance   = 1.0              // A, current headroom required for a vehicle limited max current
+	rampUpThreshold  = 0.9              // share of the max current considered ramped up
+	learnWeight      = 0.2              // weight of new observations
+	minDuration      = 3 * time.Minute  // observation time required for a signature
+	maxRampUp        = 10 * time.Minute // ramp-up times above are not comparable
+	minMargin        = 0.1              // score margin between best and second best match
+)
+
+// Signature is the learned charging behaviour used for identifying a vehicle
+type Signature struct {
+	Phases     int           `json:"phases"`     // max phases drawn
+	MaxCurrent float64       `json:"maxCurrent"` // vehicle limited max current, 0 if unknown
+	RampUp     time.Duration `json:"rampUp"`     // time from charge start until the max current is reached
+	Samples    int           `json:"samples"`    // number of learned observations
+}
+
+// Learned returns true if the signature contains at least one observation
+func (s Signature) Learned() bool {
+	return s.Samples > 0
+}
+
+// average updates the value with the new sample
+func average(val, sample float64) float64 {
+	if val == 0 {
+		return sample
+	}
+	return (1-learnWeight)*val + learnWeight*sample
+}
+
+// Learn merges the observed signature
+func (s *Signature) Learn(o Signature) {
+	if o.Phases > 0 {
+		s.Phases = o.Phases
+	}
+	if o.MaxCurrent > 0 {
+		s.MaxCurrent = average(s.MaxCurrent, o.MaxCurrent)
+	}
+	if o.RampUp > 0 {
+		s.RampUp = time.Duration(average(float64(s.RampUp), float64(o.RampUp)))
+	}
+	s.Samples++
+}
+
+// similarity returns the relative similarity of two positive values in the range 0..1
+func similarity(a, b float64) float64 {
+	if a == b {
+		return 1
+	}
+	return 1 - math.Abs(a-b)/math.Max(a, b)
+}
+
+// Match returns the likelihood in the range 0..1 that the observed signature belongs to the
+// learned signature. Characteristics unknown to either signature are not taken into account.
+func (s Signature) Match(o Signature) float64 {
+	var score, weight float64
+
+	if s.Phases > 0 && o.Phases > 0 {
+		weight += 0.5
+		if s.Phases == o.Phases {
+			score += 0.5
+		}
+	}
+
+	if s.MaxCurrent > 0 && o.MaxCurrent > 0 {
+		weight += 0.3
+		score += 0.3 * math.Max(0, 1-2*math.Abs(s.MaxCurrent-o.MaxCurrent)/s.MaxCurrent)
+	}
+
+	if s.RampUp > 0 && o.RampUp > 0 {
+		weight += 0.2
+		score += 0.2 * similarity(float64(s.RampUp), float64(o.RampUp))
+	}
+
+	if weight == 0 {
+		return 0
+	}
+
+	return score / weight
+}
+
+// Best returns the index of the learned signature best matching the observed signature
+// and its confidence. If no signature matches unambiguously, index -1 is returned.
+func Best(observed Signature, learned []Signature) (int, float64) {
+	res, best, second := -1, 0.0, 0.0
+
+	for i, s := range learned {
+		if !s.Learned() {
+			continue
+		}
+
+		switch score := s.Match(observed); {
+		case score > best:
+			res, best, second = i, score, best
+		case score > second:
+			second = score
+		}
+	}
+
+	if res < 0 || best-second < minMargin {
+		return -1, 0
+	}
+
+	return res, best
+}
+
+// Observation records the charging behaviour from charge start
+type Observation struct {
+	start      time.Time
+	updated    time.Time
+	phases     int
+	maxCurrent float64
+	offered    float64
+	rampUp     time.Duration
+}
+
+// NewObservation creates an observation starting at given time
+func NewObservation(start time.Time) *Observation {
+	return &Observation{start: start}
+}
+
+// Add samples the phase currents drawn at the offered current. Phases are only
+// recorded if the charger was able to supply the vehicle's maximum phases.
+func (o *Observation) Add(ts time.Time, currents []float64, offered float64, phases int) {
+	o.updated = ts
+	o.offered = math.Max(o.offered, offered)
+
+	var active int
+	var current float64
+	for _, i := range currents {
+		if i > minActiveCurrent {
+			active++
+		}
+		current = math.Max(current, i)
+	}
+
+	if phases != 1 && active > o.phases {
+		o.phases = active
+	}
+
+	// ramp-up completes when the current first comes close to the maximum current
+	if current > o.maxCurrent {
+		if rampUpThreshold*current > o.maxCurrent {
+			o.rampUp = ts.Sub(o.start)
+		}
+		o.maxCurrent = current
+	}
+}
+
+// Complete returns true if the observation is sufficient for matching or learning
+func (o *Observation) Complete() bool {
+	return o.maxCurrent > minActiveCurrent && o.updated.Sub(o.start) >= minDuration
+}
+
+// Signature returns the observed signature.
+// Max current is only known if the vehicle did not draw the offered current.
+func (o *Observation) Signature() Signature {
+	res := Signature{
+		Phases: o.phases,
+	}
+
+	if o.maxCurrent < o.offered-limitTolerance {
+		res.MaxCurrent = o.maxCurrent
+	}
+
+	if o.rampUp <= maxRampUp {
+		res.RampUp = o.rampUp
+	}
+
+	return res
+}
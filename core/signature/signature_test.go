@@ -0,0 +1,62 @@
+package signature
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObservation(t *testing.T) {
+	start := time.Now()
+	o := NewObservation(start)
+
+	for i, cur := range []float64{2, 6, 10, 15.5, 15.8, 16} {
+		o.Add(start.Add(time.Duration(i)*time.Minute), []float64{cur, cur, 0}, 16, 3)
+	}
+
+	assert.True(t, o.Complete())
+	assert.Equal(t, Signature{Phases: 2, RampUp: 3 * time.Minute}, o.Signature())
+
+	// vehicle limited max current
+	o = NewObservation(start)
+	for i := 0; i < 4; i++ {
+		o.Add(start.Add(time.Duration(i)*time.Minute), []float64{10.5, 0, 0}, 16, 1)
+	}
+	assert.Equal(t, Signature{Phases: 0, MaxCurrent: 10.5}, o.Signature())
+}
+
+func TestLearn(t *testing.T) {
+	var s Signature
+	s.Learn(Signature{Phases: 3, MaxCurrent: 10, RampUp: time.Minute})
+	s.Learn(Signature{Phases: 3, RampUp: 2 * time.Minute})
+
+	assert.Equal(t, 3, s.Phases)
+	assert.Equal(t, 10.0, s.MaxCurrent)
+	assert.Equal(t, 72*time.Second, s.RampUp)
+	assert.Equal(t, 2, s.Samples)
+}
+
+func TestBest(t *testing.T) {
+	learned := []Signature{
+		{Phases: 1, MaxCurrent: 16, RampUp: time.Minute, Samples: 1},
+		{Phases: 3, MaxCurrent: 11, RampUp: 2 * time.Minute, Samples: 1},
+		{Phases: 3, MaxCurrent: 16, RampUp: time.Minute},
+	}
+
+	idx, confidence := Best(Signature{Phases: 3, MaxCurrent: 11, RampUp: 2 * time.Minute}, learned)
+	assert.Equal(t, 1, idx)
+	assert.Equal(t, 1.0, confidence)
+
+	// unlearned signature is ignored
+	idx, _ = Best(Signature{Phases: 3, MaxCurrent: 16, RampUp: time.Minute}, learned)
+	assert.Equal(t, 1, idx)
+
+	// ambiguous
+	idx, confidence = Best(Signature{RampUp: time.Minute}, []Signature{
+		{Phases: 3, RampUp: time.Minute, Samples: 1},
+		{Phases: 1, RampUp: time.Minute, Samples: 1},
+	})
+	assert.Equal(t, -1, idx)
+	assert.Equal(t, 0.0, confidence)
+}
@@ -10,11 +10,14 @@ import (
 	"github.com/avast/retry-go/v3"
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/cmd/shutdown"
+	"github.com/evcc-io/evcc/core/consumer"
 	"github.com/evcc-io/evcc/core/coordinator"
 	"github.com/evcc-io/evcc/core/db"
+	"github.com/evcc-io/evcc/core/geofence"
 	"github.com/evcc-io/evcc/core/loadpoint"
 	"github.com/evcc-io/evcc/push"
 	serverdb "github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/server/db/settings"
 	"github.com/evcc-io/evcc/tariff"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/telemetry"
@@ -27,10 +30,20 @@ type Updater interface {
 	Update(availablePower float64, cheapRate, batteryBuffered bool)
 }
 
+// ConsumerUpdater abstracts the consumer implementations controlled by the site
+type ConsumerUpdater interface {
+	consumer.API
+	Prepare(uiChan chan<- util.Param)
+	Update(sitePower float64, cheap bool) float64
+	dumpConfig()
+}
+
 // Site is the main configuration container. A site can host multiple loadpoints.
 type Site struct {
 	uiChan       chan<- util.Param // client push messages
 	lpUpdateChan chan *LoadPoint
+	reconfigureC chan func()   // runtime configuration changes
+	doneC        chan struct{} // closed when the control loop has stopped
 
 	*Health
 
@@ -38,13 +51,14 @@ type Site struct {
 	log *util.Logger
 
 	// configuration
-	Title                             string       `mapstructure:"title"`         // UI title
-	Voltage                           float64      `mapstructure:"voltage"`       // Operating voltage. 230V for Germany.
-	ResidualPower                     float64      `mapstructure:"residualPower"` // PV meter only: household usage. Grid meter: household safety margin
-	Meters                            MetersConfig // Meter references
-	PrioritySoC                       float64      `mapstructure:"prioritySoC"`                       // prefer battery up to this SoC
-	BufferSoC                         float64      `mapstructure:"bufferSoC"`                         // ignore battery above this SoC
-	MaxGridSupplyWhileBatteryCharging float64      `mapstructure:"maxGridSupplyWhileBatteryCharging"` // ignore battery charging if AC consumption is above this value
+	Title                             string             `mapstructure:"title"`         // UI title
+	Voltage                           float64            `mapstructure:"voltage"`       // Operating voltage. 230V for Germany.
+	ResidualPower                     float64            `mapstructure:"residualPower"` // PV meter only: household usage. Grid meter: household safety margin
+	Meters                            MetersConfig       // Meter references
+	PrioritySoC                       float64            `mapstructure:"prioritySoC"`                       // prefer battery up to this SoC
+	BufferSoC                         float64            `mapstructure:"bufferSoC"`                         // ignore battery above this SoC
+	MaxGridSupplyWhileBatteryCharging float64            `mapstructure:"maxGridSupplyWhileBatteryCharging"` // ignore battery charging if AC consumption is above this value
+	Home                              *geofence.Geofence `mapstructure:"home"`                              // home location for vehicle geofencing
 
 	// meters
 	gridMeter     api.Meter   // Grid usage meter
@@ -53,6 +67,7 @@ type Site struct {
 
 	tariffs     tariff.Tariffs           // Tariff
 	loadpoints  []*LoadPoint             // Loadpoints
+	consumers   []ConsumerUpdater        // Consumers
 	coordinator *coordinator.Coordinator // Savings
 	savings     *Savings                 // Savings
 
@@ -78,6 +93,7 @@ func NewSiteFromConfig(
 	cp configProvider,
 	other map[string]interface{},
 	loadpoints []*LoadPoint,
+	consumers []ConsumerUpdater,
 	vehicles []api.Vehicle,
 	tariffs tariff.Tariffs,
 ) (*Site, error) {
@@ -88,21 +104,21 @@ func NewSiteFromConfig(
 
 	Voltage = site.Voltage
 	site.loadpoints = loadpoints
+	site.consumers = consumers
 	site.tariffs = tariffs
 	site.coordinator = coordinator.New(log, vehicles)
 	site.savings = NewSavings(tariffs)
 
+	if site.Home.Configured() {
+		if err := site.Home.Validate(); err != nil {
+			return nil, err
+		}
+		site.coordinator.SetHome(site.Home)
+	}
+
 	// migrate session log
 	if serverdb.Instance != nil {
-		var err error
-		// TODO deprecate
-		if table := "transactions"; serverdb.Instance.Migrator().HasTable(table) {
-			err = serverdb.Instance.Migrator().RenameTable(table, new(db.Session))
-		}
-		if err == nil {
-			err = serverdb.Instance.AutoMigrate(new(db.Session))
-		}
-		if err != nil {
+		if err := db.Migrate(); err != nil {
 			return nil, err
 		}
 	}
@@ -118,6 +134,12 @@ func NewSiteFromConfig(
 	for _, lp := range loadpoints {
 		lp.coordinator = coordinator.NewAdapter(lp, site.coordinator)
 
+		if site.Home.Configured() {
+			lp.home = site.Home
+		} else if lp.SoC.Poll.Mode == pollHome {
+			lp.log.WARN.Printf("poll mode '%s' requires site home location", pollHome)
+		}
+
 		if serverdb.Instance != nil {
 			var err error
 			if lp.db, err = db.New(lp.Title); err != nil {
@@ -126,13 +148,28 @@ func NewSiteFromConfig(
 
 			// NOTE: this requires stopSession to respect async access
 			shutdown.Register(lp.stopSession)
+
+			lp.signatureStore = settings.NewStore
 		}
 	}
 
+	if err := site.configureMeters(cp); err != nil {
+		return nil, err
+	}
+
+	return site, nil
+}
+
+// configureMeters resolves the site's meter references
+func (site *Site) configureMeters(cp configProvider) error {
+	site.gridMeter = nil
+	site.pvMeters = nil
+	site.batteryMeters = nil
+
 	if site.Meters.GridMeterRef != "" {
 		var err error
 		if site.gridMeter, err = cp.Meter(site.Meters.GridMeterRef); err != nil {
-			return nil, err
+			return err
 		}
 	}
 
@@ -140,7 +177,7 @@ func NewSiteFromConfig(
 	for _, ref := range site.Meters.PVMetersRef {
 		pv, err := cp.Meter(ref)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		site.pvMeters = append(site.pvMeters, pv)
 	}
@@ -148,11 +185,11 @@ func NewSiteFromConfig(
 	// single pv
 	if site.Meters.PVMeterRef != "" {
 		if len(site.pvMeters) > 0 {
-			return nil, errors.New("cannot have pv and pvs both")
+			return errors.New("cannot have pv and pvs both")
 		}
 		pv, err := cp.Meter(site.Meters.PVMeterRef)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		site.pvMeters = append(site.pvMeters, pv)
 	}
@@ -161,7 +198,7 @@ func NewSiteFromConfig(
 	for _, ref := range site.Meters.BatteryMetersRef {
 		battery, err := cp.Meter(ref)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		site.batteryMeters = append(site.batteryMeters, battery)
 	}
@@ -169,28 +206,30 @@ func NewSiteFromConfig(
 	// single battery
 	if site.Meters.BatteryMeterRef != "" {
 		if len(site.batteryMeters) > 0 {
-			return nil, errors.New("cannot have battery and batteries both")
+			return errors.New("cannot have battery and batteries both")
 		}
 		battery, err := cp.Meter(site.Meters.BatteryMeterRef)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		site.batteryMeters = append(site.batteryMeters, battery)
 	}
 
 	// configure meter from references
 	if site.gridMeter == nil && len(site.pvMeters) == 0 {
-		return nil, errors.New("missing either grid or pv meter")
+		return errors.New("missing either grid or pv meter")
 	}
 
-	return site, nil
+	return nil
 }
 
 // NewSite creates a Site with sane defaults
 func NewSite() *Site {
 	lp := &Site{
-		log:     util.NewLogger("site"),
-		Voltage: 230, // V
+		log:          util.NewLogger("site"),
+		Voltage:      230, // V
+		reconfigureC: make(chan func()),
+		doneC:        make(chan struct{}),
 	}
 
 	return lp
@@ -205,6 +244,15 @@ func (site *Site) LoadPoints() []loadpoint.API {
 	return res
 }
 
+// Consumers returns the array of associated consumers
+func (site *Site) Consumers() []consumer.API {
+	res := make([]consumer.API, len(site.consumers))
+	for id, c := range site.consumers {
+		res[id] = c
+	}
+	return res
+}
+
 func meterCapabilities(name string, meter interface{}) string {
 	_, power := meter.(api.Meter)
 	_, energy := meter.(api.MeterEnergy)
@@ -297,6 +345,11 @@ func (site *Site) DumpConfig() {
 			lp.log.INFO.Printf(meterCapabilities("charge", lp.chargeMeter))
 		}
 	}
+
+	for i, c := range site.consumers {
+		site.log.INFO.Printf("consumer %d: %s", i+1, c.Name())
+		c.dumpConfig()
+	}
 }
 
 // publish sends values to UI and databases
@@ -490,6 +543,11 @@ func (site *Site) update(lp Updater) {
 	}
 
 	if sitePower, err := site.sitePower(totalChargePower); err == nil {
+		// consumers are served before loadpoints, account for their expected power change
+		for _, c := range site.consumers {
+			sitePower += c.Update(sitePower, cheap)
+		}
+
 		lp.Update(sitePower, cheap, site.batteryBuffered)
 
 		// ignore negative pvPower values as that means it is not an energy source but consumption
@@ -552,6 +610,20 @@ func (site *Site) Prepare(uiChan chan<- util.Param, pushChan chan<- push.Event)
 
 		lp.Prepare(lpUIChan, lpPushChan, site.lpUpdateChan)
 	}
+
+	for id, c := range site.consumers {
+		cUIChan := make(chan util.Param)
+
+		// pipe messages through go func to add id
+		go func(id int) {
+			for param := range cUIChan {
+				param.Consumer = &id
+				uiChan <- param
+			}
+		}(id)
+
+		c.Prepare(cUIChan)
+	}
 }
 
 // loopLoadpoints keeps iterating across loadpoints sending the next to the given channel
@@ -563,9 +635,85 @@ func (site *Site) loopLoadpoints(next chan<- Updater) {
 	}
 }
 
+// Reconfigure re-resolves the site's and loadpoints' device references after runtime
+// configuration changes. Changes are applied on the control loop to avoid concurrent device access.
+func (site *Site) Reconfigure(cp configProvider, vehicles []api.Vehicle) error {
+	return site.onControlLoop(func() error {
+		return site.reconfigure(cp, vehicles)
+	})
+}
+
+// errSiteStopped is returned for configuration changes after the control loop has stopped
+var errSiteStopped = errors.New("site stopped")
+
+// onControlLoop executes fn on the control loop and returns its result
+func (site *Site) onControlLoop(fn func() error) error {
+	errC := make(chan error, 1)
+
+	select {
+	case site.reconfigureC <- func() { errC <- fn() }:
+		return <-errC
+	case <-site.doneC:
+		return errSiteStopped
+	}
+}
+
+func (site *Site) reconfigure(cp configProvider, vehicles []api.Vehicle) error {
+	grid, pv, battery := site.gridMeter, site.pvMeters, site.batteryMeters
+
+	if err := site.configureMeters(cp); err != nil {
+		site.gridMeter, site.pvMeters, site.batteryMeters = grid, pv, battery
+		return err
+	}
+
+	site.Lock()
+	site.coordinator.SetVehicles(vehicles)
+	site.Unlock()
+
+	for _, lp := range site.loadpoints {
+		if err := lp.reconfigure(cp, vehicles); err != nil {
+			return err
+		}
+	}
+
+	site.publish("vehicles", vehicleTitles(vehicles))
+
+	return nil
+}
+
+// ReconfigureLoadPoint applies changed loadpoint configuration to the running loadpoint.
+// Changes are applied on the control loop to avoid concurrent device access.
+func (site *Site) ReconfigureLoadPoint(lp *LoadPoint, cp configProvider, vehicles []api.Vehicle, other map[string]interface{}) error {
+	return site.onControlLoop(func() error {
+		return site.reconfigureLoadPoint(lp, cp, vehicles, other)
+	})
+}
+
+func (site *Site) reconfigureLoadPoint(lp *LoadPoint, cp configProvider, vehicles []api.Vehicle, other map[string]interface{}) error {
+	conf, err := NewLoadPointFromConfig(lp.log, cp, other)
+	if err != nil {
+		return err
+	}
+
+	lp.applyConfig(conf)
+
+	if err := lp.reconfigure(cp, vehicles); err != nil {
+		return err
+	}
+
+	lp.setConfiguredPhases(conf.ConfiguredPhases)
+	if _, ok := lp.charger.(api.PhaseSwitcher); !ok {
+		lp.setPhases(conf.phases)
+	}
+
+	return nil
+}
+
 // Run is the main control loop. It reacts to trigger events by
 // updating measurements and executing control logic.
 func (site *Site) Run(stopC chan struct{}, interval time.Duration) {
+	defer close(site.doneC)
+
 	site.Health = NewHealth(time.Minute + interval)
 
 	loadpointChan := make(chan Updater)
@@ -580,6 +728,8 @@ func (site *Site) Run(stopC chan struct{}, interval time.Duration) {
 			site.update(<-loadpointChan)
 		case lp := <-site.lpUpdateChan:
 			site.update(lp)
+		case fn := <-site.reconfigureC:
+			fn()
 		case <-stopC:
 			return
 		}
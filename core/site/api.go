@@ -2,6 +2,7 @@ package site
 
 import (
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/consumer"
 	"github.com/evcc-io/evcc/core/loadpoint"
 )
 
@@ -9,6 +10,7 @@ import (
 type API interface {
 	Healthy() bool
 	LoadPoints() []loadpoint.API
+	Consumers() []consumer.API
 
 	//
 	// battery
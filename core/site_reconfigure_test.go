@@ -0,0 +1,118 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/coordinator"
+	"github.com/evcc-io/evcc/mock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type testConfigProvider struct {
+	meters   map[string]api.Meter
+	chargers map[string]api.Charger
+}
+
+func (cp *testConfigProvider) Meter(name string) (api.Meter, error) {
+	if m, ok := cp.meters[name]; ok {
+		return m, nil
+	}
+	return nil, errors.New("meter does not exist: " + name)
+}
+
+func (cp *testConfigProvider) Charger(name string) (api.Charger, error) {
+	if c, ok := cp.chargers[name]; ok {
+		return c, nil
+	}
+	return nil, errors.New("charger does not exist: " + name)
+}
+
+func (cp *testConfigProvider) Vehicle(name string) (api.Vehicle, error) {
+	return nil, errors.New("vehicle does not exist: " + name)
+}
+
+func TestSiteReconfigure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	grid := mock.NewMockMeter(ctrl)
+	charger := mock.NewMockCharger(ctrl)
+
+	cp := &testConfigProvider{
+		meters:   map[string]api.Meter{"grid": grid},
+		chargers: map[string]api.Charger{"charger": charger},
+	}
+
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.ChargerRef = "charger"
+	lp.charger = charger
+	lp.configureChargerType(charger)
+	meter := lp.chargeMeter
+
+	site := NewSite()
+	site.Meters.GridMeterRef = "grid"
+	site.loadpoints = []*LoadPoint{lp}
+	site.coordinator = coordinator.New(site.log, nil)
+
+	// unchanged charger keeps wrappers
+	assert.NoError(t, site.reconfigure(cp, nil))
+	assert.Equal(t, grid, site.gridMeter)
+	assert.Same(t, meter, lp.chargeMeter)
+
+	// replaced charger
+	replaced := mock.NewMockCharger(ctrl)
+	cp.chargers["charger"] = replaced
+	assert.NoError(t, site.reconfigure(cp, nil))
+	assert.Equal(t, replaced, lp.charger)
+
+	// missing grid meter restores previous meters
+	delete(cp.meters, "grid")
+	assert.Error(t, site.reconfigure(cp, nil))
+	assert.Equal(t, grid, site.gridMeter)
+}
+
+func TestSiteReconfigureLoadPoint(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	charger := mock.NewMockCharger(ctrl)
+
+	cp := &testConfigProvider{
+		chargers: map[string]api.Charger{"charger": charger},
+	}
+
+	lp := NewLoadPoint(util.NewLogger("foo"))
+	lp.ChargerRef = "charger"
+	lp.charger = charger
+	lp.configureChargerType(charger)
+
+	site := NewSite()
+	site.loadpoints = []*LoadPoint{lp}
+
+	// invalid configuration leaves loadpoint unchanged
+	assert.Error(t, site.reconfigureLoadPoint(lp, cp, nil, map[string]interface{}{
+		"title":   "changed",
+		"charger": "missing",
+	}))
+	assert.Equal(t, "", lp.Title)
+
+	assert.NoError(t, site.reconfigureLoadPoint(lp, cp, nil, map[string]interface{}{
+		"title":      "changed",
+		"charger":    "charger",
+		"phases":     1,
+		"maxCurrent": 32,
+	}))
+	assert.Equal(t, "changed", lp.Title)
+	assert.Equal(t, 32.0, lp.GetMaxCurrent())
+	assert.Equal(t, 1, lp.GetPhases())
+	assert.Same(t, charger, lp.charger)
+}
+
+func TestSiteReconfigureStopped(t *testing.T) {
+	site := NewSite()
+	close(site.doneC)
+
+	assert.ErrorIs(t, site.Reconfigure(new(testConfigProvider), nil), errSiteStopped)
+}
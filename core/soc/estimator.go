@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/api/store"
 	"github.com/evcc-io/evcc/util"
 )
 
@@ -27,6 +28,10 @@ type Estimator struct {
 	prevSoc           float64 // previous vehicle SoC in %
 	prevChargedEnergy float64 // previous charged energy in Wh
 	energyPerSocStep  float64 // Energy per SoC percent in Wh
+
+	profile             Profile     // learned charging behaviour
+	learnedEnergyPerSoC float64     // learned energy per soc percent at session start
+	store               store.Store // profile persistence
 }
 
 // NewEstimator creates new estimator
@@ -51,9 +56,17 @@ func (s *Estimator) Reset() {
 	s.capacity = float64(s.vehicle.Capacity()) * 1e3  // cache to simplify debugging
 	s.virtualCapacity = s.capacity / chargeEfficiency // initial capacity taking efficiency into account
 	s.energyPerSocStep = s.virtualCapacity / 100
+
+	// use learned capacity instead of assumed efficiency
+	s.learnedEnergyPerSoC = s.profile.EnergyPerSoC
+	if s.profile.EnergyPerSoC > 0 {
+		s.energyPerSocStep = s.profile.EnergyPerSoC
+		s.virtualCapacity = s.energyPerSocStep * 100
+	}
 }
 
-// AssumedChargeDuration estimates charge duration up to targetSoC based on virtual capacity
+// AssumedChargeDuration estimates charge duration up to targetSoC based on virtual capacity.
+// The offered charge power is reduced according to the learned charging curve.
 func (s *Estimator) AssumedChargeDuration(targetSoC int, chargePower float64) time.Duration {
 	percentRemaining := float64(targetSoC) - s.vehicleSoc
 
@@ -61,8 +74,18 @@ func (s *Estimator) AssumedChargeDuration(targetSoC int, chargePower float64) ti
 		return 0
 	}
 
-	whRemaining := percentRemaining / 100 * s.virtualCapacity
-	return time.Duration(float64(time.Hour) * whRemaining / chargePower).Round(time.Second)
+	hours := s.chargeDuration(s.vehicleSoc, float64(targetSoC), chargePower)
+	return time.Duration(float64(time.Hour) * hours).Round(time.Second)
+}
+
+// PlannedChargeDuration estimates charge duration up to targetSoC for target charge planning.
+// Unless capacity and charging curve have been learned, charge efficiency is taken into account again as safety margin.
+func (s *Estimator) PlannedChargeDuration(targetSoC int, chargePower float64) time.Duration {
+	d := s.AssumedChargeDuration(targetSoC, chargePower)
+	if !s.profile.learned() {
+		d = time.Duration(float64(d) / chargeEfficiency)
+	}
+	return d
 }
 
 // RemainingChargeDuration returns the remaining duration estimate based on SoC, target and charge power
@@ -86,7 +109,10 @@ func (s *Estimator) RemainingChargeDuration(chargePower float64, targetSoC int)
 			}
 		}
 
-		return s.AssumedChargeDuration(targetSoC, chargePower)
+		// actual charge power is already reduced by the current soc's acceptance
+		offeredPower := chargePower / s.profile.acceptance(band(s.vehicleSoc))
+
+		return s.AssumedChargeDuration(targetSoC, offeredPower)
 	}
 
 	return -1
@@ -187,6 +213,10 @@ func (s *Estimator) SoC(chargedEnergy float64) (float64, error) {
 					s.energyPerSocStep = energyDiff / socDiff
 					s.virtualCapacity = s.energyPerSocStep * 100
 					s.log.DEBUG.Printf("soc gradient updated: soc: %.1f%%, socDiff: %.1f%%, energyDiff: %.0fWh, energyPerSocStep: %.1fWh, virtualCapacity: %.0fWh", s.vehicleSoc, socDiff, energyDiff, s.energyPerSocStep, s.virtualCapacity)
+
+					// keep learned capacity for next session, weighting each session once
+					s.profile.EnergyPerSoC = average(s.learnedEnergyPerSoC, s.energyPerSocStep)
+					s.saveProfile()
 				}
 			}
 
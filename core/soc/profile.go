@@ -0,0 +1,114 @@
+package soc
+
+import (
+	"math"
+
+	"github.com/evcc-io/evcc/api/store"
+)
+
+const (
+	profileBands    = 10  // soc bands of 10%
+	profileWeight   = 0.1 // weight of new samples
+	minSamplePower  = 100 // W, ignore samples below
+	minLearnedBands = 3   // bands required to use the learned curve
+)
+
+// Profile is the learned charging behaviour of a vehicle
+type Profile struct {
+	EnergyPerSoC float64   `json:"energyPerSoc"` // Wh per soc percent including charging losses
+	Acceptance   []float64 `json:"acceptance"`   // ratio of charge power to offered power per soc band
+}
+
+// band returns the profile band of the soc
+func band(soc float64) int {
+	return int(math.Max(0, math.Min(soc/100*profileBands, profileBands-1)))
+}
+
+// acceptance returns the learned acceptance of the soc band or 1 if not available
+func (p *Profile) acceptance(band int) float64 {
+	if band < len(p.Acceptance) && p.Acceptance[band] > 0 {
+		return p.Acceptance[band]
+	}
+	return 1
+}
+
+// curve returns true if sufficient soc bands have been learned
+func (p *Profile) curve() bool {
+	var n int
+	for _, a := range p.Acceptance {
+		if a > 0 {
+			n++
+		}
+	}
+	return n >= minLearnedBands
+}
+
+// learned returns true if energy per soc and the charging curve have been learned
+func (p *Profile) learned() bool {
+	return p.EnergyPerSoC > 0 && p.curve()
+}
+
+// average updates the value with the new sample
+func average(val, sample float64) float64 {
+	if val == 0 {
+		return sample
+	}
+	return (1-profileWeight)*val + profileWeight*sample
+}
+
+// SetProfileStore loads the vehicle's learned profile from the store and persists learned values to it
+func (s *Estimator) SetProfileStore(st store.Store) {
+	s.store = st
+
+	var p Profile
+	if err := st.Load(&p); err == nil {
+		s.profile = p
+		s.log.DEBUG.Printf("soc profile: energyPerSoc: %.1fWh, acceptance: %v", p.EnergyPerSoC, p.Acceptance)
+	}
+
+	s.Reset()
+}
+
+func (s *Estimator) saveProfile() {
+	if s.store == nil {
+		return
+	}
+
+	if err := s.store.Save(s.profile); err != nil {
+		s.log.ERROR.Printf("soc profile: %v", err)
+	}
+}
+
+// Learn samples the ratio of actual to offered charge power at the current soc
+func (s *Estimator) Learn(chargePower, offeredPower float64) {
+	if chargePower < minSamplePower || offeredPower <= 0 || s.vehicleSoc <= 0 {
+		return
+	}
+
+	if len(s.profile.Acceptance) != profileBands {
+		s.profile.Acceptance = make([]float64, profileBands)
+	}
+
+	b := band(s.vehicleSoc)
+	s.profile.Acceptance[b] = average(s.profile.Acceptance[b], math.Min(chargePower/offeredPower, 1))
+
+	s.saveProfile()
+}
+
+// chargeDuration integrates the charge duration from soc to target soc at the offered power
+// using the learned acceptance per soc band
+func (s *Estimator) chargeDuration(soc, targetSoC, offeredPower float64) float64 {
+	var hours float64
+
+	for soc < targetSoC {
+		b := band(soc)
+		next := math.Min(float64(b+1)*100/profileBands, targetSoC)
+
+		wh := (next - soc) / 100 * s.virtualCapacity
+		hours += wh / (offeredPower * s.profile.acceptance(b))
+
+		soc = next
+	}
+
+	return hours
+}
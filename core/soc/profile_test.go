@@ -0,0 +1,82 @@
+package soc
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/mock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memStore struct {
+	val []byte
+}
+
+func (s *memStore) Load(res any) error {
+	return json.Unmarshal(s.val, res)
+}
+
+func (s *memStore) Save(val any) error {
+	var err error
+	s.val, err = json.Marshal(val)
+	return err
+}
+
+func TestProfileLearn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	charger := mock.NewMockCharger(ctrl)
+	vehicle := mock.NewMockVehicle(ctrl)
+	vehicle.EXPECT().Capacity().Return(float64(9)).AnyTimes()
+
+	st := new(memStore)
+	st.val = []byte("{}")
+
+	ce := NewEstimator(util.NewLogger("foo"), charger, vehicle, false)
+	ce.SetProfileStore(st)
+
+	// flat curve below 80%, half power above
+	for soc, ratio := range map[float64]float64{50: 1, 60: 1, 70: 1, 85: 0.5, 95: 0.5} {
+		ce.vehicleSoc = soc
+		ce.Learn(11000*ratio, 11000)
+	}
+
+	// ignored samples
+	ce.Learn(50, 11000)
+	ce.Learn(11000, 0)
+
+	assert.Equal(t, 1.0, ce.profile.acceptance(band(50)))
+	assert.Equal(t, 0.5, ce.profile.acceptance(band(85)))
+	assert.Equal(t, 1.0, ce.profile.acceptance(band(10)), "unknown band")
+	assert.False(t, ce.profile.learned())
+
+	// 70% to 90%: 10% at full, 10% at half power
+	ce.vehicleSoc = 70
+	assert.Equal(t, 3*time.Hour, ce.AssumedChargeDuration(90, 1000))
+
+	// actual power at 85% is reduced already
+	ce.vehicleSoc = 85
+	assert.Equal(t, time.Hour, ce.RemainingChargeDuration(500, 90))
+
+	// learned capacity
+	ce.profile.EnergyPerSoC = 95
+	ce.saveProfile()
+	assert.True(t, ce.profile.learned())
+
+	ce = NewEstimator(util.NewLogger("foo"), charger, vehicle, false)
+	ce.SetProfileStore(st)
+	assert.Equal(t, 9500.0, ce.virtualCapacity)
+
+	// no efficiency margin for learned profile
+	ce.vehicleSoc = 50
+	require.Equal(t, ce.AssumedChargeDuration(80, 9500), ce.PlannedChargeDuration(80, 9500))
+	assert.Equal(t, 18*time.Minute, ce.PlannedChargeDuration(80, 9500))
+}
+
+func TestProfileAverage(t *testing.T) {
+	assert.Equal(t, 1.0, average(0, 1))
+	assert.InDelta(t, 0.95, average(1, 0.5), 1e-9)
+}
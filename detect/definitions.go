@@ -39,6 +39,11 @@ const (
 	taskFroniusWeb   = "fronius-web"
 	taskTasmota      = "tasmota"
 	taskShelly       = "shelly"
+	taskShellyMdns   = "shelly-mdns"
+	taskGoEMdns      = "go-e-mdns"
+	taskOpenwbMdns   = "openwb-mdns"
+	taskTasmotaMdns  = "tasmota-mdns"
+	taskFroniusSsdp  = "fronius-ssdp"
 	// taskTPLink       = "tplink"
 )
 
@@ -288,4 +293,49 @@ func init() {
 			"jq":   ".type",
 		},
 	})
+
+	taskList.Add(tasks.Task{
+		ID:      taskShellyMdns,
+		Type:    tasks.Mdns,
+		Depends: TaskPing,
+		Config: map[string]interface{}{
+			"instance": "shelly",
+		},
+	})
+
+	taskList.Add(tasks.Task{
+		ID:      taskGoEMdns,
+		Type:    tasks.Mdns,
+		Depends: TaskPing,
+		Config: map[string]interface{}{
+			"instance": "go-echarger",
+		},
+	})
+
+	taskList.Add(tasks.Task{
+		ID:      taskOpenwbMdns,
+		Type:    tasks.Mdns,
+		Depends: TaskPing,
+		Config: map[string]interface{}{
+			"instance": "openwb",
+		},
+	})
+
+	taskList.Add(tasks.Task{
+		ID:      taskTasmotaMdns,
+		Type:    tasks.Mdns,
+		Depends: TaskPing,
+		Config: map[string]interface{}{
+			"instance": "tasmota",
+		},
+	})
+
+	taskList.Add(tasks.Task{
+		ID:      taskFroniusSsdp,
+		Type:    tasks.Ssdp,
+		Depends: TaskPing,
+		Config: map[string]interface{}{
+			"server": "fronius",
+		},
+	})
 }
@@ -0,0 +1,124 @@
+package detect
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/korylprince/ipnetgen"
+)
+
+// Discovery periodically scans the local network for devices in the background
+type Discovery struct {
+	mu       sync.Mutex
+	log      *util.Logger
+	interval time.Duration
+	scanC    chan struct{}
+	running  bool
+	updated  time.Time
+	hits     []Hit
+}
+
+// Status is the current discovery state
+type Status struct {
+	Running bool      `json:"running"`
+	Updated time.Time `json:"updated"`
+	Hits    []Hit     `json:"hits"`
+}
+
+// NewDiscovery creates a discovery service scanning with the given interval
+func NewDiscovery(interval time.Duration) *Discovery {
+	return &Discovery{
+		log:      util.NewLogger("discovery"),
+		interval: interval,
+		scanC:    make(chan struct{}, 1),
+	}
+}
+
+// Run scans the local network on start, with given interval and when triggered
+func (d *Discovery) Run() {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		d.scan()
+
+		select {
+		case <-ticker.C:
+		case <-d.scanC:
+		}
+	}
+}
+
+// Scan triggers a new scan unless already running
+func (d *Discovery) Scan() {
+	select {
+	case d.scanC <- struct{}{}:
+	default:
+	}
+}
+
+// Status returns the latest discovery results
+func (d *Discovery) Status() Status {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return Status{
+		Running: d.running,
+		Updated: d.updated,
+		Hits:    d.hits,
+	}
+}
+
+func (d *Discovery) scan() {
+	d.mu.Lock()
+	d.running = true
+	d.mu.Unlock()
+
+	hosts := LocalHosts()
+	d.log.DEBUG.Printf("scanning %d hosts", len(hosts))
+
+	hits := Hits(Work(d.log, 50, hosts))
+	d.log.DEBUG.Printf("found %d devices", len(hits))
+
+	d.mu.Lock()
+	d.running = false
+	d.updated = time.Now()
+	d.hits = hits
+	d.mu.Unlock()
+}
+
+// LocalHosts returns the host addresses of the local subnets. Subnets larger than /24 are skipped.
+func LocalHosts() []string {
+	var res []string
+
+	for _, ipnet := range util.LocalIPs() {
+		if bits, _ := ipnet.Mask.Size(); bits < 24 {
+			continue
+		}
+
+		res = append(res, subnetHosts(ipnet)...)
+	}
+
+	return res
+}
+
+// subnetHosts returns the addresses of a subnet excluding network and broadcast address
+func subnetHosts(ipnet net.IPNet) []string {
+	gen, err := ipnetgen.New(ipnet.String())
+	if err != nil {
+		return nil
+	}
+
+	var res []string
+	for ip := gen.Next(); ip != nil; ip = gen.Next() {
+		res = append(res, ip.String())
+	}
+
+	if len(res) < 3 {
+		return res
+	}
+
+	return res[1 : len(res)-1]
+}
@@ -0,0 +1,126 @@
+package tasks
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/libp2p/zeroconf/v2"
+)
+
+const Mdns TaskType = "mdns"
+
+func init() {
+	registry.Add(Mdns, MdnsHandlerFactory)
+}
+
+type MdnsResult struct {
+	Instance string
+	Text     []string `json:",omitempty"`
+}
+
+func MdnsHandlerFactory(conf map[string]interface{}) (TaskHandler, error) {
+	handler := MdnsHandler{
+		Service: "_http._tcp",
+		Timeout: 3 * time.Second,
+	}
+
+	err := util.DecodeOther(conf, &handler)
+
+	return &handler, err
+}
+
+// MdnsHandler matches hosts against service instances announced via mDNS.
+// Instance is matched as case-insensitive prefix of the announced instance name.
+type MdnsHandler struct {
+	Service, Instance string
+	Timeout           time.Duration
+}
+
+// mdnsCache shares browse results between tasks and hosts of a single scan
+var mdnsCache = struct {
+	mu      sync.Mutex
+	updated map[string]time.Time
+	entries map[string][]*zeroconf.ServiceEntry
+}{
+	updated: make(map[string]time.Time),
+	entries: make(map[string][]*zeroconf.ServiceEntry),
+}
+
+const discoveryCache = time.Minute
+
+func (h *MdnsHandler) browse(log *util.Logger) []*zeroconf.ServiceEntry {
+	mdnsCache.mu.Lock()
+	defer mdnsCache.mu.Unlock()
+
+	if time.Since(mdnsCache.updated[h.Service]) < discoveryCache {
+		return mdnsCache.entries[h.Service]
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.Timeout)
+	defer cancel()
+
+	var res []*zeroconf.ServiceEntry
+
+	entries := make(chan *zeroconf.ServiceEntry)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case entry, ok := <-entries:
+				if !ok {
+					return
+				}
+				res = append(res, entry)
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	// Browse does not close entries if it fails to start, but never sends once it has returned
+	err := zeroconf.Browse(ctx, h.Service, "local.", entries)
+	close(stop)
+	<-done
+
+	if err != nil {
+		log.ERROR.Println("mdns:", err)
+		res = nil
+	}
+
+	// failures are cached as well to avoid repeated browsing
+	mdnsCache.updated[h.Service] = time.Now()
+	mdnsCache.entries[h.Service] = res
+
+	return res
+}
+
+func (h *MdnsHandler) Test(log *util.Logger, in ResultDetails) []ResultDetails {
+	for _, entry := range h.browse(log) {
+		if !strings.HasPrefix(strings.ToLower(entry.Instance), strings.ToLower(h.Instance)) {
+			continue
+		}
+
+		for _, ip := range entry.AddrIPv4 {
+			if ip.String() != in.IP {
+				continue
+			}
+
+			out := in.Clone()
+			out.Port = entry.Port
+			out.MdnsResult = &MdnsResult{
+				Instance: entry.Instance,
+				Text:     entry.Text,
+			}
+
+			return []ResultDetails{out}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package tasks
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/koron/go-ssdp"
+)
+
+const Ssdp TaskType = "ssdp"
+
+func init() {
+	registry.Add(Ssdp, SsdpHandlerFactory)
+}
+
+type SsdpResult struct {
+	Type, Server, Location string
+}
+
+func SsdpHandlerFactory(conf map[string]interface{}) (TaskHandler, error) {
+	handler := SsdpHandler{
+		SearchType: ssdp.RootDevice,
+		Timeout:    3 * time.Second,
+	}
+
+	err := util.DecodeOther(conf, &handler)
+
+	return &handler, err
+}
+
+// SsdpHandler matches hosts against devices responding to an SSDP search.
+// Server is matched as case-insensitive substring of the server header.
+type SsdpHandler struct {
+	SearchType, Server string
+	Timeout            time.Duration
+}
+
+// ssdpCache shares search results between tasks and hosts of a single scan
+var ssdpCache = struct {
+	mu       sync.Mutex
+	updated  map[string]time.Time
+	services map[string][]ssdp.Service
+}{
+	updated:  make(map[string]time.Time),
+	services: make(map[string][]ssdp.Service),
+}
+
+func (h *SsdpHandler) search(log *util.Logger) []ssdp.Service {
+	ssdpCache.mu.Lock()
+	defer ssdpCache.mu.Unlock()
+
+	if time.Since(ssdpCache.updated[h.SearchType]) < discoveryCache {
+		return ssdpCache.services[h.SearchType]
+	}
+
+	res, err := ssdp.Search(h.SearchType, int(h.Timeout.Seconds()), "")
+	if err != nil {
+		log.ERROR.Println("ssdp:", err)
+	}
+
+	ssdpCache.updated[h.SearchType] = time.Now()
+	ssdpCache.services[h.SearchType] = res
+
+	return res
+}
+
+func (h *SsdpHandler) Test(log *util.Logger, in ResultDetails) []ResultDetails {
+	for _, service := range h.search(log) {
+		if !strings.Contains(strings.ToLower(service.Server), strings.ToLower(h.Server)) {
+			continue
+		}
+
+		u, err := url.Parse(service.Location)
+		if err != nil || u.Hostname() != in.IP {
+			continue
+		}
+
+		out := in.Clone()
+		out.SsdpResult = &SsdpResult{
+			Type:     service.Type,
+			Server:   service.Server,
+			Location: service.Location,
+		}
+
+		return []ResultDetails{out}
+	}
+
+	return nil
+}
@@ -12,6 +12,8 @@ type ResultDetails struct {
 	ModbusResult *ModbusResult `json:",omitempty"`
 	KebaResult   *KebaResult   `json:",omitempty"`
 	SmaResult    *SmaResult    `json:",omitempty"`
+	MdnsResult   *MdnsResult   `json:",omitempty"`
+	SsdpResult   *SsdpResult   `json:",omitempty"`
 }
 
 func (d *ResultDetails) Clone() ResultDetails {
@@ -0,0 +1,96 @@
+package detect
+
+import (
+	"sort"
+
+	"github.com/evcc-io/evcc/detect/tasks"
+	"github.com/evcc-io/evcc/util/templates"
+)
+
+// Template references the device template matching a detection task
+type Template struct {
+	Class    templates.Class
+	Template string
+}
+
+// taskTemplates maps detection tasks to the templates of the detected devices
+var taskTemplates = map[string][]Template{
+	taskOpenwb:       {{templates.Charger, "openwb"}},
+	taskOpenwbMdns:   {{templates.Charger, "openwb"}},
+	taskKEBA:         {{templates.Charger, "keba"}},
+	taskWallbe:       {{templates.Charger, "wallbe"}},
+	taskPhoenixEMEth: {{templates.Charger, "phoenix-em-eth"}},
+	taskPhoenixEVEth: {{templates.Charger, "phoenix-ev-eth"}},
+	taskEVSEWifi:     {{templates.Charger, "evsewifi"}},
+	taskGoE:          {{templates.Charger, "go-e"}},
+	taskGoEMdns:      {{templates.Charger, "go-e"}},
+	taskShelly:       {{templates.Charger, "shelly"}, {templates.Meter, "shelly-1pm"}},
+	taskShellyMdns:   {{templates.Charger, "shelly"}, {templates.Meter, "shelly-1pm"}},
+	taskTasmota:      {{templates.Charger, "tasmota"}, {templates.Meter, "tasmota"}},
+	taskTasmotaMdns:  {{templates.Charger, "tasmota"}, {templates.Meter, "tasmota"}},
+	taskE3DC:         {{templates.Meter, "e3dc"}},
+	taskSonnen:       {{templates.Meter, "sonnenbatterie"}},
+	taskPowerwall:    {{templates.Meter, "tesla-powerwall"}},
+	taskFroniusWeb:   {{templates.Meter, "fronius-solarapi-v1"}},
+	taskFroniusSsdp:  {{templates.Meter, "fronius-solarapi-v1"}},
+	taskInverter:     {{templates.Meter, "sunspec-inverter"}},
+	taskBattery:      {{templates.Meter, "sunspec-hybrid"}},
+	taskSMA:          {{templates.Meter, "sma-home-manager"}, {templates.Meter, "sma-energy-meter"}},
+}
+
+// Hit is a detected device with its matching template
+type Hit struct {
+	IP       string              `json:"ip"`
+	Port     int                 `json:"port,omitempty"`
+	Task     string              `json:"task"`
+	Class    templates.Class     `json:"class"`
+	Template string              `json:"template"`
+	Product  string              `json:"product"`
+	Details  tasks.ResultDetails `json:"details"`
+}
+
+// Hits maps detection results to device templates. Results without matching template are
+// dropped, devices found by multiple tasks are only returned once.
+func Hits(res []tasks.Result) []Hit {
+	var hits []Hit
+	seen := make(map[Hit]bool)
+
+	for _, r := range res {
+		for _, t := range taskTemplates[r.Task.ID] {
+			tmpl, err := templates.ByName(t.Class, t.Template)
+			if err != nil {
+				continue
+			}
+
+			key := Hit{IP: r.ResultDetails.IP, Class: t.Class, Template: tmpl.Template}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			hit := Hit{
+				IP:       r.ResultDetails.IP,
+				Port:     r.ResultDetails.Port,
+				Task:     r.Task.ID,
+				Class:    t.Class,
+				Template: tmpl.Template,
+				Details:  r.ResultDetails,
+			}
+
+			if len(tmpl.Products) > 0 {
+				hit.Product = tmpl.Products[0].Title("en")
+			}
+
+			hits = append(hits, hit)
+		}
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].IP == hits[j].IP {
+			return hits[i].Class < hits[j].Class
+		}
+		return hits[i].IP < hits[j].IP
+	})
+
+	return hits
+}
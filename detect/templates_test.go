@@ -0,0 +1,38 @@
+package detect
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/detect/tasks"
+	"github.com/evcc-io/evcc/util/templates"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskTemplates(t *testing.T) {
+	for id, refs := range taskTemplates {
+		for _, ref := range refs {
+			_, err := templates.ByName(ref.Class, ref.Template)
+			assert.NoError(t, err, id)
+		}
+	}
+}
+
+func TestHits(t *testing.T) {
+	res := []tasks.Result{
+		{Task: tasks.Task{ID: TaskPing}, ResultDetails: tasks.ResultDetails{IP: "192.168.1.50"}},
+		{Task: tasks.Task{ID: taskKEBA}, ResultDetails: tasks.ResultDetails{IP: "192.168.1.50"}},
+		{Task: tasks.Task{ID: taskShelly}, ResultDetails: tasks.ResultDetails{IP: "192.168.1.10", Port: 80}},
+		{Task: tasks.Task{ID: taskShellyMdns}, ResultDetails: tasks.ResultDetails{IP: "192.168.1.10", Port: 80}},
+	}
+
+	hits := Hits(res)
+	require.Len(t, hits, 3)
+
+	assert.Equal(t, "192.168.1.10", hits[0].IP)
+	assert.Equal(t, templates.Charger, hits[0].Class)
+	assert.Equal(t, templates.Meter, hits[1].Class)
+
+	assert.Equal(t, "keba", hits[2].Template)
+	assert.Contains(t, hits[2].Product, "KEBA")
+}
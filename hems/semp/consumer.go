@@ -0,0 +1,121 @@
+package semp
+
+import (
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/consumer"
+)
+
+// consumerDevice is a consumer with its SEMP device number
+type consumerDevice struct {
+	id int
+	consumer.API
+}
+
+// consumers returns the site's consumers in device number order. Consumers are numbered after loadpoints.
+func (s *SEMP) consumers() []consumerDevice {
+	offset := len(s.site.LoadPoints())
+
+	consumers := s.site.Consumers()
+	res := make([]consumerDevice, 0, len(consumers))
+
+	for id, c := range consumers {
+		res = append(res, consumerDevice{id: offset + id, API: c})
+	}
+
+	return res
+}
+
+func (s *SEMP) consumerInfo(id int, c consumer.API) DeviceInfo {
+	method := MethodEstimation
+	if c.HasMeter() {
+		method = MethodMeasurement
+	}
+
+	deviceType := sempConsumer
+	if _, ok := c.(consumer.HeatPump); ok {
+		deviceType = sempHeatPump
+	}
+
+	res := DeviceInfo{
+		Identification: Identification{
+			DeviceID:     s.deviceID(id),
+			DeviceName:   c.Name(),
+			DeviceType:   deviceType,
+			DeviceSerial: s.serialNumber(id),
+			DeviceVendor: "github.com/evcc-io/evcc",
+		},
+		Capabilities: Capabilities{
+			CurrentPowerMethod:   method,
+			InterruptionsAllowed: true,
+			OptionalEnergy:       true,
+		},
+		Characteristics: Characteristics{
+			MinPowerConsumption: int(c.GetMinPower()),
+			MaxPowerConsumption: int(c.GetMaxPower()),
+		},
+	}
+
+	return res
+}
+
+func (s *SEMP) consumerStatus(id int, c consumer.API) DeviceStatus {
+	mode := c.GetMode()
+	isPV := mode == api.ModeMinPV || mode == api.ModePV
+
+	deviceStatus := StatusOff
+	if c.GetEnabled() {
+		deviceStatus = StatusOn
+	}
+
+	res := DeviceStatus{
+		DeviceID:          s.deviceID(id),
+		EMSignalsAccepted: s.controllable && isPV,
+		PowerInfo: PowerInfo{
+			AveragePower:      int(c.GetPower()),
+			AveragingInterval: 60,
+		},
+		Status: deviceStatus,
+	}
+
+	return res
+}
+
+func (s *SEMP) consumerPlanningRequest(id int, c consumer.API) (res PlanningRequest) {
+	mode := c.GetMode()
+	if mode == api.ModeOff || mode == api.ModeNow {
+		return res
+	}
+
+	// daily demand must be met until midnight
+	now := time.Now()
+	midnight := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+	latestEnd := int(midnight.Sub(now) / time.Second)
+
+	maxPowerConsumption := int(c.GetMaxPower())
+	minPowerConsumption := int(c.GetMinPower())
+
+	// remaining daily energy demand is mandatory, surplus is optional
+	minEnergy := int(c.GetRemainingEnergy())
+	maxEnergy := maxPowerConsumption * latestEnd / 3600
+	if maxEnergy < minEnergy {
+		maxEnergy = minEnergy
+	}
+
+	if maxEnergy > 0 {
+		res = PlanningRequest{
+			Timeframe: []Timeframe{{
+				DeviceID:            s.deviceID(id),
+				EarliestStart:       0,
+				LatestEnd:           latestEnd,
+				MinEnergy:           &minEnergy,
+				MaxEnergy:           &maxEnergy,
+				MaxPowerConsumption: &maxPowerConsumption,
+				MinPowerConsumption: &minPowerConsumption,
+			}},
+		}
+	}
+
+	return res
+}
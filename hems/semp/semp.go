@@ -30,6 +30,8 @@ const (
 	sempDeviceId     = "F-%s-%.12x-00" // 6 bytes
 	sempSerialNumber = "%s-%d"
 	sempCharger      = "EVCharger"
+	sempConsumer     = "Other"
+	sempHeatPump     = "HeatPump"
 	basePath         = "/semp"
 	maxAge           = 1800
 )
@@ -277,6 +279,15 @@ func (s *SEMP) deviceInfoQuery(w http.ResponseWriter, r *http.Request) {
 			msg.DeviceInfo = append(msg.DeviceInfo, s.deviceInfo(id, lp))
 		}
 
+		for _, cd := range s.consumers() {
+			id, c := cd.id, cd.API
+			if did != s.deviceID(id) {
+				continue
+			}
+
+			msg.DeviceInfo = append(msg.DeviceInfo, s.consumerInfo(id, c))
+		}
+
 		if len(msg.DeviceInfo) == 0 {
 			w.WriteHeader(http.StatusBadRequest)
 			return
@@ -302,6 +313,15 @@ func (s *SEMP) deviceStatusQuery(w http.ResponseWriter, r *http.Request) {
 			msg.DeviceStatus = append(msg.DeviceStatus, s.deviceStatus(id, lp))
 		}
 
+		for _, cd := range s.consumers() {
+			id, c := cd.id, cd.API
+			if did != s.deviceID(id) {
+				continue
+			}
+
+			msg.DeviceStatus = append(msg.DeviceStatus, s.consumerStatus(id, c))
+		}
+
 		if len(msg.DeviceStatus) == 0 {
 			w.WriteHeader(http.StatusBadRequest)
 			return
@@ -328,6 +348,17 @@ func (s *SEMP) devicePlanningQuery(w http.ResponseWriter, r *http.Request) {
 				msg.PlanningRequest = append(msg.PlanningRequest, pr)
 			}
 		}
+
+		for _, cd := range s.consumers() {
+			id, c := cd.id, cd.API
+			if did != s.deviceID(id) {
+				continue
+			}
+
+			if pr := s.consumerPlanningRequest(id, c); len(pr.Timeframe) > 0 {
+				msg.PlanningRequest = append(msg.PlanningRequest, pr)
+			}
+		}
 	}
 
 	s.writeXML(w, msg)
@@ -401,6 +432,11 @@ func (s *SEMP) allDeviceInfo() (res []DeviceInfo) {
 		res = append(res, s.deviceInfo(id, lp))
 	}
 
+	for _, cd := range s.consumers() {
+		id, c := cd.id, cd.API
+		res = append(res, s.consumerInfo(id, c))
+	}
+
 	return res
 }
 
@@ -436,6 +472,11 @@ func (s *SEMP) allDeviceStatus() (res []DeviceStatus) {
 		res = append(res, s.deviceStatus(id, lp))
 	}
 
+	for _, cd := range s.consumers() {
+		id, c := cd.id, cd.API
+		res = append(res, s.consumerStatus(id, c))
+	}
+
 	return res
 }
 
@@ -465,6 +506,14 @@ func (s *SEMP) planningRequest(id int, lp loadpoint.API) (res PlanningRequest) {
 		minEnergy = 0
 	}
 
+	// target charging: energy becomes mandatory until target time
+	if targetTime := lp.GetTargetTime(); !targetTime.IsZero() {
+		if remaining := int(time.Until(targetTime) / time.Second); remaining > 0 {
+			latestEnd = remaining
+			minEnergy = maxEnergy
+		}
+	}
+
 	maxPowerConsumption := int(lp.GetMaxPower())
 	minPowerConsumption := int(lp.GetMinPower())
 	if mode == api.ModeNow {
@@ -495,6 +544,13 @@ func (s *SEMP) allPlanningRequest() (res []PlanningRequest) {
 		}
 	}
 
+	for _, cd := range s.consumers() {
+		id, c := cd.id, cd.API
+		if pr := s.consumerPlanningRequest(id, c); len(pr.Timeframe) > 0 {
+			res = append(res, pr)
+		}
+	}
+
 	return res
 }
 
@@ -533,7 +589,44 @@ func (s *SEMP) deviceControlHandler(w http.ResponseWriter, r *http.Request) {
 				demand = loadpoint.RemoteEnable
 			}
 
-			lp.RemoteControl(sempController, demand)
+			if err := lp.RemoteControl(sempController, demand); err != nil {
+				s.log.ERROR.Printf("remote control: %v", err)
+			}
+
+			// recommended power is honored as upper limit
+			var limit float64
+			if dev.On {
+				limit = dev.RecommendedPowerConsumption
+			}
+
+			if err := lp.RemotePowerLimit(sempController, limit); err != nil {
+				s.log.ERROR.Printf("remote power limit: %v", err)
+			}
+		}
+
+		for _, cd := range s.consumers() {
+			id, c := cd.id, cd.API
+			if did != s.deviceID(id) {
+				continue
+			}
+
+			if mode := c.GetMode(); mode != api.ModeMinPV && mode != api.ModePV {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			// ignore requests if not controllable
+			if !s.controllable {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			demand := loadpoint.RemoteSoftDisable
+			if dev.On {
+				demand = loadpoint.RemoteEnable
+			}
+
+			c.RemoteControl(sempController, demand)
 		}
 	}
 
@@ -0,0 +1,59 @@
+package semp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type planningLoadPoint struct {
+	loadpoint.API
+	mode       api.ChargeMode
+	targetTime time.Time
+}
+
+func (lp *planningLoadPoint) GetMode() api.ChargeMode             { return lp.mode }
+func (lp *planningLoadPoint) GetStatus() api.ChargeStatus         { return api.StatusB }
+func (lp *planningLoadPoint) GetRemainingDuration() time.Duration { return time.Hour }
+func (lp *planningLoadPoint) GetRemainingEnergy() float64         { return 10e3 }
+func (lp *planningLoadPoint) GetTargetTime() time.Time            { return lp.targetTime }
+func (lp *planningLoadPoint) GetMaxPower() float64                { return 11e3 }
+func (lp *planningLoadPoint) GetMinPower() float64                { return 1.4e3 }
+
+func TestPlanningRequest(t *testing.T) {
+	s := &SEMP{vid: "28081973", did: []byte{0, 0, 0, 0, 0, 1}}
+
+	// pv mode: optional energy until end of day
+	lp := &planningLoadPoint{mode: api.ModePV}
+
+	res := s.planningRequest(0, lp)
+	require.Len(t, res.Timeframe, 1)
+
+	tf := res.Timeframe[0]
+	assert.Equal(t, 24*3600, tf.LatestEnd)
+	assert.Equal(t, 0, *tf.MinEnergy)
+	assert.Equal(t, 10000, *tf.MaxEnergy)
+
+	// target charging: mandatory energy until target time
+	lp.targetTime = time.Now().Add(2 * time.Hour)
+
+	res = s.planningRequest(0, lp)
+	require.Len(t, res.Timeframe, 1)
+
+	tf = res.Timeframe[0]
+	assert.InDelta(t, 2*3600, tf.LatestEnd, 5)
+	assert.Equal(t, 10000, *tf.MinEnergy)
+	assert.Equal(t, 10000, *tf.MaxEnergy)
+
+	// target time passed
+	lp.targetTime = time.Now().Add(-time.Minute)
+
+	res = s.planningRequest(0, lp)
+	require.Len(t, res.Timeframe, 1)
+	assert.Equal(t, 24*3600, res.Timeframe[0].LatestEnd)
+	assert.Equal(t, 0, *res.Timeframe[0].MinEnergy)
+}
@@ -144,6 +144,8 @@ func (m *Modbus) bytesGetter() ([]byte, error) {
 			return m.conn.ReadHoldingRegisters(op.OpCode, op.ReadLen)
 		case rs485.ReadInputReg:
 			return m.conn.ReadInputRegisters(op.OpCode, op.ReadLen)
+		case gridx.FuncCodeReadCoils:
+			return m.conn.ReadCoil(op.OpCode)
 		default:
 			return nil, fmt.Errorf("unknown function code %d", op.FuncCode)
 		}
@@ -272,6 +274,8 @@ func (m *Modbus) IntSetter(param string) func(int64) error {
 			switch op.FuncCode {
 			case gridx.FuncCodeWriteSingleRegister:
 				_, err = m.conn.WriteSingleRegister(op.OpCode, uval)
+			case gridx.FuncCodeWriteSingleCoil:
+				err = m.conn.WriteCoil(op.OpCode, uval != 0)
 			default:
 				err = fmt.Errorf("unknown function code %d", op.FuncCode)
 			}
@@ -142,9 +142,11 @@ func (m *Client) Listen(topic string, callback func(string)) {
 }
 
 // ListenSetter creates a /set listener that resets the payload after handling
-func (m *Client) ListenSetter(topic string, callback func(string)) {
+func (m *Client) ListenSetter(topic string, callback func(string) error) {
 	m.Listen(topic, func(payload string) {
-		callback(payload)
+		if err := callback(payload); err != nil {
+			m.log.ERROR.Printf("set %s: %v", topic, err)
+		}
 		if err := m.Publish(topic, true, ""); err != nil {
 			m.log.ERROR.Printf("clear: %v", err)
 		}
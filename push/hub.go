@@ -75,7 +75,7 @@ func (h *Hub) apply(ev Event, tmpl *template.Template) (string, error) {
 
 	// get all values from cache
 	for _, p := range h.cache.All() {
-		if p.LoadPoint == nil || ev.LoadPoint == p.LoadPoint {
+		if p.Consumer == nil && (p.LoadPoint == nil || ev.LoadPoint == p.LoadPoint) {
 			attr[p.Key] = p.Val
 		}
 	}
@@ -0,0 +1,252 @@
+package backup
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/server/db"
+	"golang.org/x/exp/slices"
+	"gorm.io/gorm"
+)
+
+// Version is the archive format version
+const Version = 1
+
+const (
+	manifestFile = "manifest.json"
+	configFile   = "evcc.yaml"
+	tablesDir    = "tables"
+)
+
+// Manifest describes the archive contents
+type Manifest struct {
+	Version int       `json:"version"` // archive format version
+	Evcc    string    `json:"evcc"`    // evcc version that created the archive
+	Created time.Time `json:"created"`
+	Config  bool      `json:"config"` // archive contains config file
+	Tables  []string  `json:"tables"`
+}
+
+// Archive is the content of a backup
+type Archive struct {
+	Manifest
+	ConfigFile []byte
+	Rows       map[string][]map[string]any
+}
+
+// Create writes a backup archive containing the given config file contents and all database tables of the instance.
+// Tables are archived without table prefix.
+func Create(w io.Writer, version string, config []byte) error {
+	if db.Instance == nil {
+		return errors.New("database not configured")
+	}
+
+	tables, err := db.Instance.Migrator().GetTables()
+	if err != nil {
+		return err
+	}
+
+	sort.Strings(tables)
+
+	manifest := Manifest{
+		Version: Version,
+		Evcc:    version,
+		Created: time.Now().UTC(),
+		Config:  len(config) > 0,
+	}
+
+	zw := zip.NewWriter(w)
+
+	for _, table := range tables {
+		// skip internal tables and tables of other instances
+		if strings.HasPrefix(table, "sqlite_") || !strings.HasPrefix(table, db.Prefix()) {
+			continue
+		}
+
+		var rows []map[string]any
+		if err := db.Instance.Table(table).Find(&rows).Error; err != nil {
+			return fmt.Errorf("reading %s: %w", table, err)
+		}
+
+		name := strings.TrimPrefix(table, db.Prefix())
+		if err := writeJSON(zw, path.Join(tablesDir, name+".json"), rows); err != nil {
+			return err
+		}
+
+		manifest.Tables = append(manifest.Tables, name)
+	}
+
+	if manifest.Config {
+		f, err := zw.Create(configFile)
+		if err == nil {
+			_, err = f.Write(config)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := writeJSON(zw, manifestFile, manifest); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+func writeJSON(zw *zip.Writer, name string, v any) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(v)
+}
+
+// Open reads and validates a backup archive
+func Open(r io.ReaderAt, size int64) (Archive, error) {
+	res := Archive{
+		Rows: make(map[string][]map[string]any),
+	}
+
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return res, err
+	}
+
+	if err := readJSON(zr, manifestFile, &res.Manifest); err != nil {
+		return res, fmt.Errorf("invalid archive: %w", err)
+	}
+
+	if res.Version > Version {
+		return res, fmt.Errorf("unsupported archive version %d created by evcc %s, update evcc", res.Version, res.Evcc)
+	}
+
+	if res.Config {
+		f, err := zr.Open(configFile)
+		if err != nil {
+			return res, err
+		}
+		defer f.Close()
+
+		if res.ConfigFile, err = io.ReadAll(f); err != nil {
+			return res, err
+		}
+	}
+
+	for _, table := range res.Tables {
+		var rows []map[string]any
+		if err := readJSON(zr, path.Join(tablesDir, table+".json"), &rows); err != nil {
+			return res, err
+		}
+
+		res.Rows[table] = rows
+	}
+
+	return res, nil
+}
+
+func readJSON(zr *zip.Reader, name string, v any) error {
+	f, err := zr.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return json.NewDecoder(f).Decode(v)
+}
+
+// Check verifies that the archive's tables and columns are compatible with the database.
+// Tables must have been migrated before.
+func (a Archive) Check() error {
+	if db.Instance == nil {
+		return errors.New("database not configured")
+	}
+
+	migrator := db.Instance.Migrator()
+
+	for _, name := range a.Tables {
+		table := db.Prefix() + name
+
+		if !migrator.HasTable(table) {
+			return fmt.Errorf("unknown table %s, archive was created by evcc %s", table, a.Evcc)
+		}
+
+		types, err := migrator.ColumnTypes(table)
+		if err != nil {
+			return err
+		}
+
+		columns := make([]string, 0, len(types))
+		for _, t := range types {
+			columns = append(columns, t.Name())
+		}
+
+		for _, row := range a.Rows[name] {
+			for col := range row {
+				if !slices.Contains(columns, col) {
+					return fmt.Errorf("unknown column %s.%s, archive was created by evcc %s", table, col, a.Evcc)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Restore replaces the database contents with the archive's tables in a single transaction
+func (a Archive) Restore() error {
+	if err := a.Check(); err != nil {
+		return err
+	}
+
+	tx := db.Instance.Begin()
+
+	for _, name := range a.Tables {
+		table := db.Prefix() + name
+
+		if err := tx.Exec(fmt.Sprintf("DELETE FROM %s", tx.Statement.Quote(table))).Error; err != nil {
+			tx.Rollback()
+			return fmt.Errorf("clearing %s: %w", table, err)
+		}
+
+		if rows := a.Rows[name]; len(rows) > 0 {
+			if err := tx.Table(table).Create(&rows).Error; err != nil {
+				tx.Rollback()
+				return fmt.Errorf("restoring %s: %w", table, err)
+			}
+
+			if _, ok := rows[0]["id"]; ok {
+				if err := resetSequence(tx, table); err != nil {
+					tx.Rollback()
+					return fmt.Errorf("restoring %s: %w", table, err)
+				}
+			}
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+// resetSequence continues the table's id sequence after the restored ids.
+// Postgres sequences are not advanced by inserting explicit ids, other databases adjust automatically.
+func resetSequence(tx *gorm.DB, table string) error {
+	if tx.Dialector.Name() != "postgres" {
+		return nil
+	}
+
+	quoted := tx.Statement.Quote(table)
+
+	return tx.Exec(fmt.Sprintf(
+		"SELECT setval(pg_get_serial_sequence('%s', 'id'), COALESCE(MAX(id), 1), MAX(id) IS NOT NULL) FROM %s", quoted, quoted,
+	)).Error
+}
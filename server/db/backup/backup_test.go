@@ -0,0 +1,124 @@
+package backup
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/server/db/config"
+	"github.com/evcc-io/evcc/server/db/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type session struct {
+	ID      uint `gorm:"primarykey"`
+	Created time.Time
+	Charged float64
+}
+
+func setup(t *testing.T) {
+	t.Helper()
+
+	require.NoError(t, db.NewInstance("sqlite", filepath.Join(t.TempDir(), "evcc.db"), ""))
+	require.NoError(t, settings.Init())
+	require.NoError(t, config.Init())
+	require.NoError(t, db.Instance.AutoMigrate(new(session)))
+}
+
+func TestBackupRestore(t *testing.T) {
+	setup(t)
+
+	created := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	settings.SetString("foo", "bar")
+	require.NoError(t, settings.Persist())
+	_, err := config.AddConfig(config.Meter, "grid", "custom", map[string]interface{}{"power": 1})
+	require.NoError(t, err)
+	require.NoError(t, db.Instance.Create(&session{Created: created, Charged: 4.2}).Error)
+
+	var buf bytes.Buffer
+	require.NoError(t, Create(&buf, "0.1.0", []byte("interval: 10s\n")))
+
+	// restore into new database
+	setup(t)
+
+	archive, err := Open(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	assert.Equal(t, Version, archive.Version)
+	assert.Equal(t, "0.1.0", archive.Evcc)
+	assert.Equal(t, "interval: 10s\n", string(archive.ConfigFile))
+
+	require.NoError(t, archive.Restore())
+
+	// settings are cached
+	require.NoError(t, settings.Init())
+	val, err := settings.String("foo")
+	require.NoError(t, err)
+	assert.Equal(t, "bar", val)
+
+	confs, err := config.Configs(config.Meter)
+	require.NoError(t, err)
+	require.Len(t, confs, 1)
+	assert.Equal(t, "grid", confs[0].Name)
+
+	var sessions []session
+	require.NoError(t, db.Instance.Find(&sessions).Error)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, 4.2, sessions[0].Charged)
+	assert.True(t, created.Equal(sessions[0].Created), sessions[0].Created)
+}
+
+func TestRestoreUnknownTable(t *testing.T) {
+	setup(t)
+
+	var buf bytes.Buffer
+	require.NoError(t, Create(&buf, "0.1.0", nil))
+
+	// database without sessions table
+	require.NoError(t, db.NewInstance("sqlite", filepath.Join(t.TempDir(), "evcc.db"), ""))
+	require.NoError(t, settings.Init())
+
+	archive, err := Open(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	assert.ErrorContains(t, archive.Restore(), "unknown table")
+}
+
+func TestBackupPrefix(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "evcc.db")
+
+	// other instance sharing the database
+	require.NoError(t, db.NewInstance("sqlite", file, ""))
+	require.NoError(t, db.Instance.AutoMigrate(new(session)))
+	require.NoError(t, db.Instance.Create(&session{Charged: 1}).Error)
+
+	require.NoError(t, db.NewInstance("sqlite", file, "site1_"))
+	require.NoError(t, db.Instance.AutoMigrate(new(session)))
+	require.NoError(t, db.Instance.Create(&session{Charged: 2}).Error)
+
+	var buf bytes.Buffer
+	require.NoError(t, Create(&buf, "0.1.0", nil))
+
+	archive, err := Open(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"sessions"}, archive.Tables)
+	require.Len(t, archive.Rows["sessions"], 1)
+	assert.Equal(t, 2.0, archive.Rows["sessions"][0]["charged"])
+
+	// restore into instance with different prefix
+	require.NoError(t, db.NewInstance("sqlite", filepath.Join(t.TempDir(), "evcc.db"), "site2_"))
+	require.NoError(t, db.Instance.AutoMigrate(new(session)))
+	require.NoError(t, archive.Restore())
+
+	assert.True(t, db.Instance.Migrator().HasTable("site2_sessions"))
+
+	var res []session
+	require.NoError(t, db.Instance.Find(&res).Error)
+	require.Len(t, res, 1)
+	assert.Equal(t, 2.0, res[0].Charged)
+}
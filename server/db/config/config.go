@@ -0,0 +1,120 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/evcc-io/evcc/server/db"
+)
+
+// configuration classes
+const (
+	Meter     = "meter"
+	Charger   = "charger"
+	Vehicle   = "vehicle"
+	LoadPoint = "loadpoint"
+)
+
+// Classes are the configuration classes available for runtime configuration
+var Classes = []string{Meter, Charger, Vehicle, LoadPoint}
+
+var ErrNotFound = errors.New("not found")
+
+// Config is a runtime device or loadpoint configuration
+type Config struct {
+	ID    int    `json:"id" gorm:"primarykey"`
+	Class string `json:"class" gorm:"index"`
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"-"` // json encoded configuration
+}
+
+// Other returns the decoded configuration
+func (c Config) Other() (map[string]interface{}, error) {
+	res := make(map[string]interface{})
+	if c.Value == "" {
+		return res, nil
+	}
+	err := json.Unmarshal([]byte(c.Value), &res)
+	return res, err
+}
+
+// SetOther encodes the configuration
+func (c *Config) SetOther(other map[string]interface{}) error {
+	b, err := json.Marshal(other)
+	if err == nil {
+		c.Value = string(b)
+	}
+	return err
+}
+
+func Init() error {
+	return db.Instance.AutoMigrate(new(Config))
+}
+
+// Configs returns the configurations of the given class
+func Configs(class string) ([]Config, error) {
+	var res []Config
+	err := db.Instance.Where(&Config{Class: class}).Order("id").Find(&res).Error
+	return res, err
+}
+
+// ConfigByID returns the configuration with the given id
+func ConfigByID(id int) (Config, error) {
+	var res Config
+	tx := db.Instance.Where(&Config{ID: id}).Find(&res)
+	if tx.Error == nil && tx.RowsAffected == 0 {
+		return res, ErrNotFound
+	}
+	return res, tx.Error
+}
+
+// AddConfig adds a new configuration
+func AddConfig(class, name, typ string, other map[string]interface{}) (Config, error) {
+	var existing []Config
+	if err := db.Instance.Where(&Config{Class: class, Name: name}).Find(&existing).Error; err != nil {
+		return Config{}, err
+	}
+	if len(existing) > 0 {
+		return Config{}, fmt.Errorf("duplicate %s name: %s already defined and must be unique", class, name)
+	}
+
+	conf := Config{Class: class, Name: name, Type: typ}
+	if err := conf.SetOther(other); err != nil {
+		return conf, err
+	}
+
+	err := db.Instance.Create(&conf).Error
+	return conf, err
+}
+
+// UpdateConfig updates the type and configuration of an existing configuration
+func UpdateConfig(id int, typ string, other map[string]interface{}) (Config, error) {
+	conf, err := ConfigByID(id)
+	if err != nil {
+		return conf, err
+	}
+
+	conf.Type = typ
+	if err := conf.SetOther(other); err != nil {
+		return conf, err
+	}
+
+	err = db.Instance.Save(&conf).Error
+	return conf, err
+}
+
+// SaveConfig stores the configuration including its id, e.g. to restore a previous state
+func SaveConfig(conf Config) error {
+	return db.Instance.Save(&conf).Error
+}
+
+// DeleteConfig deletes the configuration with the given id
+func DeleteConfig(id int) error {
+	tx := db.Instance.Delete(&Config{ID: id})
+	if tx.Error == nil && tx.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return tx.Error
+}
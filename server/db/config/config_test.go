@@ -0,0 +1,44 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig(t *testing.T) {
+	require.NoError(t, db.NewInstance("sqlite", filepath.Join(t.TempDir(), "evcc.db"), ""))
+	require.NoError(t, Init())
+
+	conf, err := AddConfig(Meter, "grid", "custom", map[string]interface{}{"power": "foo"})
+	require.NoError(t, err)
+	assert.NotZero(t, conf.ID)
+
+	_, err = AddConfig(Meter, "grid", "custom", nil)
+	assert.Error(t, err)
+
+	conf, err = UpdateConfig(conf.ID, "template", map[string]interface{}{"template": "bar"})
+	require.NoError(t, err)
+
+	res, err := Configs(Meter)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	assert.Equal(t, "template", res[0].Type)
+
+	other, err := res[0].Other()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"template": "bar"}, other)
+
+	res, err = Configs(Charger)
+	assert.NoError(t, err)
+	assert.Empty(t, res)
+
+	assert.NoError(t, DeleteConfig(conf.ID))
+	assert.ErrorIs(t, DeleteConfig(conf.ID), ErrNotFound)
+
+	_, err = ConfigByID(conf.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
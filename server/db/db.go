@@ -5,16 +5,24 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/evcc-io/evcc/util"
 	"github.com/glebarez/sqlite"
 	"github.com/mitchellh/go-homedir"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
 )
 
 var Instance *gorm.DB
 
-func New(driver, dsn string) (*gorm.DB, error) {
+// prefix is the table prefix of the database instance
+var prefix string
+
+// New opens the database. Table names are prefixed for sharing a database between evcc instances.
+func New(driver, dsn, prefix string) (*gorm.DB, error) {
 	var dialect gorm.Dialector
 
 	switch driver {
@@ -28,20 +36,63 @@ func New(driver, dsn string) (*gorm.DB, error) {
 		}
 		// avoid busy errors
 		dialect = sqlite.Open(file + "?_pragma=busy_timeout(5000)")
-	// case "postgres":
-	// 	dialect = postgres.Open(dsn)
-	// case "mysql":
-	// 	dialect = mysql.Open(dsn)
+	case "postgres":
+		dialect = postgres.Open(dsn)
+	case "mysql":
+		// parse timestamps into time.Time
+		if !strings.Contains(dsn, "parseTime=") {
+			if strings.Contains(dsn, "?") {
+				dsn += "&parseTime=true"
+			} else {
+				dsn += "?parseTime=true"
+			}
+		}
+		dialect = mysql.Open(dsn)
 	default:
-		return nil, fmt.Errorf("invalid database type: %s not in [sqlite]", driver)
+		return nil, fmt.Errorf("invalid database type: %s not in [sqlite postgres mysql]", driver)
 	}
 
 	return gorm.Open(dialect, &gorm.Config{
-		Logger: &Logger{util.NewLogger("db")},
+		Logger:         &Logger{util.NewLogger("db")},
+		NamingStrategy: schema.NamingStrategy{TablePrefix: prefix},
 	})
 }
 
-func NewInstance(driver, dsn string) (err error) {
-	Instance, err = New(strings.ToLower(driver), dsn)
+func NewInstance(driver, dsn, tablePrefix string) (err error) {
+	if Instance, err = New(strings.ToLower(driver), dsn, tablePrefix); err == nil {
+		prefix = tablePrefix
+	}
 	return
 }
+
+// Prefix returns the table prefix of the database instance
+func Prefix() string {
+	return prefix
+}
+
+// Pool contains the connection pool settings of database servers
+type Pool struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+}
+
+// SetPool applies the connection pool settings to the database instance. Zero values are ignored.
+func SetPool(pool Pool) error {
+	sqlDB, err := Instance.DB()
+	if err != nil {
+		return err
+	}
+
+	if pool.MaxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(pool.MaxOpenConns)
+	}
+	if pool.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(pool.MaxIdleConns)
+	}
+	if pool.ConnMaxLifetime > 0 {
+		sqlDB.SetConnMaxLifetime(pool.ConnMaxLifetime)
+	}
+
+	return nil
+}
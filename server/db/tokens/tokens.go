@@ -0,0 +1,187 @@
+package tokens
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/evcc-io/evcc/api/store"
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/util"
+)
+
+var (
+	ErrNotFound = errors.New("not found")
+	ErrRevoked  = errors.New("token revoked")
+)
+
+// Token is a stored token of a provider account
+type Token struct {
+	Provider string    `json:"provider" gorm:"primarykey"`
+	Account  string    `json:"account" gorm:"primarykey"`
+	Value    string    `json:"-"` // json encoded token, encrypted if secret key is available
+	Expiry   time.Time `json:"expiry"`
+	Updated  time.Time `json:"updated"`
+	Revoked  time.Time `json:"-"` // revocation timestamp, rejects saving tokens obtained before
+}
+
+func (t Token) revoked() bool {
+	return !t.Revoked.IsZero()
+}
+
+func Init() error {
+	return db.Instance.AutoMigrate(new(Token))
+}
+
+func available() error {
+	if db.Instance == nil {
+		return errors.New("database offline")
+	}
+	return nil
+}
+
+// List returns the stored tokens
+func List() ([]Token, error) {
+	if err := available(); err != nil {
+		return nil, err
+	}
+
+	var tokens []Token
+	if err := db.Instance.Order("provider, account").Find(&tokens).Error; err != nil {
+		return nil, err
+	}
+
+	res := make([]Token, 0, len(tokens))
+	for _, t := range tokens {
+		if !t.revoked() {
+			res = append(res, t)
+		}
+	}
+
+	return res, nil
+}
+
+// find returns the provider account's token including revoked tokens
+func find(provider, account string) (Token, error) {
+	var t Token
+	tx := db.Instance.Where(&Token{Provider: provider, Account: account}).Limit(1).Find(&t)
+	if tx.Error != nil {
+		return t, tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return t, ErrNotFound
+	}
+	return t, nil
+}
+
+// Load decodes the provider account's token into res
+func Load(provider, account string, res any) error {
+	if err := available(); err != nil {
+		return err
+	}
+
+	t, err := find(provider, account)
+	if err != nil {
+		return err
+	}
+	if t.revoked() {
+		return ErrNotFound
+	}
+
+	val := t.Value
+	if util.IsSecretRef(val) {
+		var err error
+		if val, err = util.ResolveSecrets(val); err != nil {
+			return err
+		}
+	}
+
+	return json.Unmarshal([]byte(val), res)
+}
+
+// Save stores the provider account's token
+func Save(provider, account string, val any) error {
+	if err := available(); err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+
+	// expiry for display only
+	var exp struct {
+		Expiry time.Time `json:"expiry"`
+	}
+	_ = json.Unmarshal(b, &exp)
+
+	t := Token{
+		Provider: provider,
+		Account:  account,
+		Value:    string(b),
+		Expiry:   exp.Expiry,
+		Updated:  time.Now(),
+	}
+
+	if os.Getenv(util.SecretKeyEnv) != "" {
+		if t.Value, err = util.EncryptSecret(t.Value); err != nil {
+			return err
+		}
+	}
+
+	return db.Instance.Save(&t).Error
+}
+
+// Delete revokes the provider account's token. The revocation is kept such that
+// token sources obtained before can't store their tokens again.
+func Delete(provider, account string) error {
+	if err := available(); err != nil {
+		return err
+	}
+
+	t, err := find(provider, account)
+	if err == nil && t.revoked() {
+		err = ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	return db.Instance.Save(&Token{
+		Provider: provider,
+		Account:  account,
+		Updated:  time.Now(),
+		Revoked:  time.Now(),
+	}).Error
+}
+
+type storer struct {
+	provider, account string
+	created           time.Time
+}
+
+var _ store.Store = (*storer)(nil)
+
+// NewStore creates a store for the provider account's token.
+// Saving is rejected once the token has been revoked after creating the store.
+func NewStore(provider, account string) store.Store {
+	return &storer{provider: provider, account: account, created: time.Now()}
+}
+
+func (s *storer) Load(res any) error {
+	return Load(s.provider, s.account, res)
+}
+
+func (s *storer) Save(val any) error {
+	if err := available(); err != nil {
+		return err
+	}
+
+	if t, err := find(s.provider, s.account); err == nil && t.revoked() && t.Revoked.After(s.created) {
+		return ErrRevoked
+	}
+
+	return Save(s.provider, s.account, val)
+}
@@ -0,0 +1,75 @@
+package tokens
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func setup(t *testing.T) {
+	require.NoError(t, db.NewInstance("sqlite", filepath.Join(t.TempDir(), "evcc.db"), ""))
+	require.NoError(t, Init())
+}
+
+func TestTokens(t *testing.T) {
+	setup(t)
+
+	store := NewStore("bmw", "user@example.com")
+
+	var res oauth2.Token
+	assert.ErrorIs(t, store.Load(&res), ErrNotFound)
+
+	expiry := time.Now().Add(time.Hour).Truncate(time.Second)
+	require.NoError(t, store.Save(&oauth2.Token{AccessToken: "access", RefreshToken: "refresh", Expiry: expiry}))
+	require.NoError(t, store.Save(&oauth2.Token{AccessToken: "access2", RefreshToken: "refresh2", Expiry: expiry}))
+
+	require.NoError(t, store.Load(&res))
+	assert.Equal(t, "access2", res.AccessToken)
+	assert.Equal(t, "refresh2", res.RefreshToken)
+
+	list, err := List()
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "bmw", list[0].Provider)
+	assert.Equal(t, "user@example.com", list[0].Account)
+	assert.True(t, expiry.Equal(list[0].Expiry))
+
+	require.NoError(t, Delete("bmw", "user@example.com"))
+	assert.ErrorIs(t, Delete("bmw", "user@example.com"), ErrNotFound)
+	assert.ErrorIs(t, store.Load(&res), ErrNotFound)
+
+	list, err = List()
+	require.NoError(t, err)
+	assert.Empty(t, list)
+
+	// running token sources can't restore the revoked token
+	assert.ErrorIs(t, store.Save(&oauth2.Token{AccessToken: "access3"}), ErrRevoked)
+	assert.ErrorIs(t, store.Load(&res), ErrNotFound)
+
+	// login after revocation
+	store = NewStore("bmw", "user@example.com")
+	require.NoError(t, store.Save(&oauth2.Token{AccessToken: "access4"}))
+	require.NoError(t, store.Load(&res))
+	assert.Equal(t, "access4", res.AccessToken)
+}
+
+func TestEncryptedTokens(t *testing.T) {
+	setup(t)
+	t.Setenv(util.SecretKeyEnv, "key")
+
+	require.NoError(t, Save("bmw", "user", &oauth2.Token{RefreshToken: "refresh"}))
+
+	var stored Token
+	require.NoError(t, db.Instance.First(&stored).Error)
+	assert.NotContains(t, stored.Value, "refresh")
+
+	var res oauth2.Token
+	require.NoError(t, Load("bmw", "user", &res))
+	assert.Equal(t, "refresh", res.RefreshToken)
+}
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/server/db/backup"
+	"github.com/evcc-io/evcc/server/db/settings"
+	"github.com/gorilla/handlers"
+	"github.com/gorilla/mux"
+)
+
+// maxArchiveSize limits the size of uploaded backup archives
+const maxArchiveSize = 64 << 20
+
+// RegisterBackupHandlers connects the http handlers for backup and restore.
+// Requests must be authorized by the token as bearer token.
+func (s *HTTPd) RegisterBackupHandlers(token, cfgFile string) {
+	router := s.Server.Handler.(*mux.Router)
+
+	// api
+	api := router.PathPrefix("/api/backup").Subrouter()
+	api.Use(handlers.CORS(
+		handlers.AllowedHeaders([]string{"Authorization", "Content-Type"}),
+	))
+	api.Use(tokenHandler(token))
+
+	routes := map[string]route{
+		"backup":  {[]string{"GET"}, "", backupHandler(cfgFile)},
+		"restore": {[]string{"POST", "OPTIONS"}, "/restore", restoreHandler},
+	}
+
+	for _, r := range routes {
+		api.Methods(r.Methods...).Path(r.Pattern).Handler(r.HandlerFunc)
+	}
+}
+
+// tokenHandler rejects requests without valid bearer token. CORS preflight requests don't carry credentials and are passed.
+func tokenHandler(token string) mux.MiddlewareFunc {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodOptions {
+				h.ServeHTTP(w, r)
+				return
+			}
+
+			auth := r.Header.Get("Authorization")
+			if !strings.HasPrefix(auth, "Bearer ") || subtle.ConstantTimeCompare([]byte(auth[len("Bearer "):]), []byte(token)) != 1 {
+				w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+				jsonError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+				return
+			}
+
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+// backupHandler returns the backup archive
+func backupHandler(cfgFile string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var config []byte
+		if cfgFile != "" {
+			var err error
+			if config, err = os.ReadFile(cfgFile); err != nil {
+				w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+				jsonError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		// persist cached settings before backup
+		if err := settings.Persist(); err != nil {
+			log.ERROR.Println("cannot save settings:", err)
+		}
+
+		var buf bytes.Buffer
+		if err := backup.Create(&buf, Version, config); err != nil {
+			w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+			jsonError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="evcc-backup-%s.zip"`, time.Now().Format("20060102")))
+		_, _ = w.Write(buf.Bytes())
+	}
+}
+
+// restoreHandler restores the database from the uploaded backup archive.
+// The configuration file is not changed, database changes require a restart to take full effect.
+func restoreHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	b, err := io.ReadAll(io.LimitReader(r.Body, maxArchiveSize))
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	archive, err := backup.Open(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := archive.Restore(); err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// reload cached settings
+	if err := settings.Init(); err != nil {
+		jsonError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	jsonResult(w, struct {
+		Version string `json:"version"`
+		Restart bool   `json:"restart"`
+	}{
+		Version: archive.Evcc,
+		Restart: true,
+	})
+}
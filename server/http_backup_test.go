@@ -0,0 +1,53 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTokenHandler(t *testing.T) {
+	handler := tokenHandler("secret")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tc := []struct {
+		auth       string
+		statusCode int
+	}{
+		{"", http.StatusUnauthorized},
+		{"Bearer foo", http.StatusUnauthorized},
+		{"secret", http.StatusUnauthorized},
+		{"Bearer secret", http.StatusOK},
+	}
+
+	for _, tc := range tc {
+		req := httptest.NewRequest(http.MethodGet, "/api/backup", nil)
+		if tc.auth != "" {
+			req.Header.Set("Authorization", tc.auth)
+		}
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != tc.statusCode {
+			t.Errorf("%q: expected status %d, got %d", tc.auth, tc.statusCode, rr.Code)
+		}
+	}
+}
+
+func TestTokenHandlerPreflight(t *testing.T) {
+	handler := tokenHandler("secret")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	req := httptest.NewRequest(http.MethodOptions, "/api/backup/restore", nil)
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("expected preflight to pass, got %d", rr.Code)
+	}
+}
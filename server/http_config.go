@@ -0,0 +1,222 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/evcc-io/evcc/server/db/config"
+	"github.com/evcc-io/evcc/util"
+	"github.com/gorilla/handlers"
+	"github.com/gorilla/mux"
+)
+
+// DeviceConfigurator persists runtime configuration changes and applies them to the running instance
+type DeviceConfigurator interface {
+	StaticDevices(class string) []string
+	AddDevice(conf config.Config) (config.Config, error)
+	UpdateDevice(conf config.Config) (config.Config, error)
+	DeleteDevice(conf config.Config) error
+}
+
+// deviceConfig is the api representation of a runtime configuration
+type deviceConfig struct {
+	ID       int                    `json:"id,omitempty"`
+	Name     string                 `json:"name"`
+	Type     string                 `json:"type,omitempty"`
+	Config   map[string]interface{} `json:"config,omitempty"`
+	ReadOnly bool                   `json:"readOnly,omitempty"`
+}
+
+// RegisterConfigHandlers connects the http handlers for runtime device configuration
+func (s *HTTPd) RegisterConfigHandlers(dc DeviceConfigurator) {
+	router := s.Server.Handler.(*mux.Router)
+
+	// api
+	api := router.PathPrefix("/api/config").Subrouter()
+	api.Use(jsonHandler)
+	api.Use(handlers.CompressHandler)
+	api.Use(handlers.CORS(
+		handlers.AllowedHeaders([]string{"Content-Type"}),
+	))
+
+	class := "{class:meter|charger|vehicle|loadpoint}"
+
+	routes := map[string]route{
+		"list":   {[]string{"GET"}, "/" + class, configListHandler(dc)},
+		"add":    {[]string{"POST", "OPTIONS"}, "/" + class, configAddHandler(dc)},
+		"update": {[]string{"PUT", "OPTIONS"}, "/" + class + "/{id:[0-9]+}", configUpdateHandler(dc)},
+		"delete": {[]string{"DELETE", "OPTIONS"}, "/" + class + "/{id:[0-9]+}", configDeleteHandler(dc)},
+	}
+
+	for _, r := range routes {
+		api.Methods(r.Methods...).Path(r.Pattern).Handler(r.HandlerFunc)
+	}
+}
+
+// configResult returns the changed configuration's id.
+// Added or deleted loadpoints only take effect after restart.
+func configResult(w http.ResponseWriter, conf config.Config, restart bool) {
+	jsonResult(w, struct {
+		ID      int  `json:"id"`
+		Restart bool `json:"restart,omitempty"`
+	}{
+		ID:      conf.ID,
+		Restart: restart && conf.Class == config.LoadPoint,
+	})
+}
+
+// errSecretRef is returned for secret references in api-supplied configuration.
+// References are only resolved for the configuration file.
+var errSecretRef = errors.New("secret references are only supported in the configuration file")
+
+// configByRequest returns the stored configuration referenced by the request
+func configByRequest(r *http.Request) (config.Config, error) {
+	vars := mux.Vars(r)
+
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		return config.Config{}, err
+	}
+
+	conf, err := config.ConfigByID(id)
+	if err == nil && conf.Class != vars["class"] {
+		err = config.ErrNotFound
+	}
+
+	return conf, err
+}
+
+func configStatus(err error) int {
+	if errors.Is(err, config.ErrNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
+
+// configListHandler returns the static and runtime configurations of a class.
+// Secret values are redacted.
+func configListHandler(dc DeviceConfigurator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		class := mux.Vars(r)["class"]
+
+		res := make([]deviceConfig, 0)
+		for _, name := range dc.StaticDevices(class) {
+			res = append(res, deviceConfig{Name: name, ReadOnly: true})
+		}
+
+		confs, err := config.Configs(class)
+		if err != nil {
+			jsonError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		for _, conf := range confs {
+			other, err := conf.Other()
+			if err != nil {
+				jsonError(w, http.StatusInternalServerError, err)
+				return
+			}
+
+			res = append(res, deviceConfig{ID: conf.ID, Name: conf.Name, Type: conf.Type, Config: util.RedactConfig(other)})
+		}
+
+		jsonResult(w, res)
+	}
+}
+
+// configAddHandler creates and persists a new runtime configuration
+func configAddHandler(dc DeviceConfigurator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req deviceConfig
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		if req.Name == "" {
+			jsonError(w, http.StatusBadRequest, errors.New("missing name"))
+			return
+		}
+
+		if util.ContainsSecretRef(req.Config) {
+			jsonError(w, http.StatusBadRequest, errSecretRef)
+			return
+		}
+
+		conf := config.Config{Class: mux.Vars(r)["class"], Name: req.Name, Type: req.Type}
+		if err := conf.SetOther(req.Config); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		conf, err := dc.AddDevice(conf)
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		configResult(w, conf, true)
+	}
+}
+
+// configUpdateHandler replaces and persists an existing runtime configuration
+func configUpdateHandler(dc DeviceConfigurator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		conf, err := configByRequest(r)
+		if err != nil {
+			jsonError(w, configStatus(err), err)
+			return
+		}
+
+		var req deviceConfig
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		if util.ContainsSecretRef(req.Config) {
+			jsonError(w, http.StatusBadRequest, errSecretRef)
+			return
+		}
+
+		// keep stored secrets for redacted values
+		previous, err := conf.Other()
+		if err != nil {
+			jsonError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		conf.Type = req.Type
+		if err := conf.SetOther(util.UnredactConfig(req.Config, previous)); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		if conf, err = dc.UpdateDevice(conf); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		configResult(w, conf, false)
+	}
+}
+
+// configDeleteHandler removes a runtime configuration
+func configDeleteHandler(dc DeviceConfigurator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		conf, err := configByRequest(r)
+		if err != nil {
+			jsonError(w, configStatus(err), err)
+			return
+		}
+
+		if err := dc.DeleteDevice(conf); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		configResult(w, conf, true)
+	}
+}
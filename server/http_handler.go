@@ -17,6 +17,7 @@ import (
 	"github.com/evcc-io/evcc/core/site"
 	"github.com/evcc-io/evcc/server/assets"
 	dbserver "github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/server/db/tokens"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/locale"
 	"github.com/gorilla/mux"
@@ -74,9 +75,9 @@ func jsonError(w http.ResponseWriter, status int, err error) {
 	jsonWrite(w, map[string]interface{}{"error": err.Error()})
 }
 
-func csvResult(ctx context.Context, w http.ResponseWriter, res any) {
+func csvResult(ctx context.Context, w http.ResponseWriter, filename string, res any) {
 	w.Header().Set("Content-Type", "text/csv")
-	w.Header().Set("Content-Disposition", `attachment; filename="sessions.csv"`)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.csv"`, filename))
 
 	if ww, ok := res.(api.CsvWriter); ok {
 		_ = ww.WriteCsv(ctx, w)
@@ -196,6 +197,60 @@ func sessionHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	tableResult(w, r, "sessions", &res)
+}
+
+// tripsHandler returns the list of vehicle trips, optionally filtered by vehicle
+func tripsHandler(w http.ResponseWriter, r *http.Request) {
+	res, err := db.VehicleTrips(r.URL.Query().Get("vehicle"))
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	tableResult(w, r, "trips", &res)
+}
+
+// statisticsHandler returns the driving and charging statistics per vehicle
+func statisticsHandler(w http.ResponseWriter, r *http.Request) {
+	res, err := db.VehicleStatistics()
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	tableResult(w, r, "statistics", &res)
+}
+
+// tokensHandler returns the stored vehicle tokens without token values
+func tokensHandler(w http.ResponseWriter, r *http.Request) {
+	res, err := tokens.List()
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	jsonResult(w, res)
+}
+
+// tokenRevokeHandler removes a stored vehicle token
+func tokenRevokeHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	if err := tokens.Delete(vars["provider"], vars["account"]); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, tokens.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+		jsonError(w, status, err)
+		return
+	}
+
+	jsonResult(w, true)
+}
+
+// tableResult writes rows as json or csv depending on requested format
+func tableResult(w http.ResponseWriter, r *http.Request, filename string, res api.CsvWriter) {
 	if r.URL.Query().Get("format") == "csv" {
 		// get request language
 		lang := r.Header.Get("Accept-Language")
@@ -204,7 +259,7 @@ func sessionHandler(w http.ResponseWriter, r *http.Request) {
 		}
 
 		ctx := context.WithValue(context.Background(), locale.Locale, lang)
-		csvResult(ctx, w, &res)
+		csvResult(ctx, w, filename, res)
 		return
 	}
 
@@ -212,7 +267,7 @@ func sessionHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 // chargeModeHandler updates charge mode
-func chargeModeHandler(lp loadpoint.API) http.HandlerFunc {
+func chargeModeHandler(set func(api.ChargeMode) error, get func() api.ChargeMode) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
 
@@ -222,9 +277,12 @@ func chargeModeHandler(lp loadpoint.API) http.HandlerFunc {
 			return
 		}
 
-		lp.SetMode(mode)
+		if err := set(mode); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
 
-		jsonResult(w, lp.GetMode())
+		jsonResult(w, get())
 	}
 }
 
@@ -259,7 +317,10 @@ func remoteDemandHandler(lp loadpoint.API) http.HandlerFunc {
 			return
 		}
 
-		lp.RemoteControl(source, demand)
+		if err := lp.RemoteControl(source, demand); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
 
 		res := struct {
 			Demand loadpoint.RemoteDemand `json:"demand"`
@@ -294,7 +355,10 @@ func targetChargeHandler(loadpoint targetCharger) http.HandlerFunc {
 			return
 		}
 
-		loadpoint.SetTargetCharge(timeV, socV)
+		if err := loadpoint.SetTargetCharge(timeV, socV); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
 
 		res := struct {
 			SoC  int       `json:"soc"`
@@ -311,7 +375,11 @@ func targetChargeHandler(loadpoint targetCharger) http.HandlerFunc {
 // targetChargeRemoveHandler removes target soc
 func targetChargeRemoveHandler(loadpoint loadpoint.API) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		loadpoint.SetTargetCharge(time.Time{}, 0)
+		if err := loadpoint.SetTargetCharge(time.Time{}, 0); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
 		res := struct{}{}
 		jsonResult(w, res)
 	}
@@ -331,7 +399,10 @@ func vehicleHandler(site site.API, loadpoint loadpoint.API) http.HandlerFunc {
 			return
 		}
 
-		loadpoint.SetVehicle(vehicles[val])
+		if err := loadpoint.SetVehicle(vehicles[val]); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
 
 		res := struct {
 			Vehicle string `json:"vehicle"`
@@ -346,7 +417,11 @@ func vehicleHandler(site site.API, loadpoint loadpoint.API) http.HandlerFunc {
 // vehicleRemoveHandler removes vehicle
 func vehicleRemoveHandler(loadpoint loadpoint.API) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		loadpoint.SetVehicle(nil)
+		if err := loadpoint.SetVehicle(nil); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
 		res := struct{}{}
 		jsonResult(w, res)
 	}
@@ -355,7 +430,11 @@ func vehicleRemoveHandler(loadpoint loadpoint.API) http.HandlerFunc {
 // vehicleDetectHandler starts vehicle detection
 func vehicleDetectHandler(loadpoint loadpoint.API) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		loadpoint.StartVehicleDetection()
+		if err := loadpoint.StartVehicleDetection(); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
 		res := struct{}{}
 		jsonResult(w, res)
 	}
@@ -371,5 +450,5 @@ func socketHandler(hub *SocketHub) http.HandlerFunc {
 // TargetCharger defines target charge related loadpoint operations
 type targetCharger interface {
 	// SetTargetCharge sets the charge targetSoC
-	SetTargetCharge(time.Time, int)
+	SetTargetCharge(time.Time, int) error
 }
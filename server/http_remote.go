@@ -0,0 +1,163 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/evcc-io/evcc/core/db"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/remote"
+	dbserver "github.com/evcc-io/evcc/server/db"
+	"github.com/gorilla/handlers"
+	"github.com/gorilla/mux"
+)
+
+// RegisterRemoteHandlers connects the http handlers to the aggregated remote sites
+func (s *HTTPd) RegisterRemoteHandlers(remotes []*remote.Site) {
+	router := s.Server.Handler.(*mux.Router)
+
+	// api
+	api := router.PathPrefix("/api/remotes").Subrouter()
+	api.Use(jsonHandler)
+	api.Use(handlers.CompressHandler)
+	api.Use(handlers.CORS(
+		handlers.AllowedHeaders([]string{"Content-Type"}),
+	))
+
+	routes := map[string]route{
+		"remotes":  {[]string{"GET"}, "", remotesHandler(remotes)},
+		"sessions": {[]string{"GET"}, "/sessions", remoteSessionHandler(remotes)},
+		"state":    {[]string{"GET"}, "/{id:[0-9]+}/state", remoteStateHandler(remotes)},
+	}
+
+	for _, r := range routes {
+		api.Methods(r.Methods...).Path(r.Pattern).Handler(r.HandlerFunc)
+	}
+
+	// remote loadpoint api
+	lpAPI := api.PathPrefix("/{id:[0-9]+}/loadpoints/{lp:[0-9]+}").Subrouter()
+
+	routes = map[string]route{
+		"mode": {[]string{"POST", "OPTIONS"}, "/mode/{value:[a-z]+}", remoteLoadpointHandler(remotes, func(lp loadpoint.API) http.HandlerFunc {
+			return chargeModeHandler(lp.SetMode, lp.GetMode)
+		})},
+		"targetenergy": {[]string{"POST", "OPTIONS"}, "/targetenergy/{value:[0-9.]+}", remoteLoadpointHandler(remotes, func(lp loadpoint.API) http.HandlerFunc {
+			return floatHandler(lp.SetTargetEnergy, lp.GetTargetEnergy)
+		})},
+		"targetsoc": {[]string{"POST", "OPTIONS"}, "/targetsoc/{value:[0-9]+}", remoteLoadpointHandler(remotes, func(lp loadpoint.API) http.HandlerFunc {
+			return intHandler(lp.SetTargetSoC, lp.GetTargetSoC)
+		})},
+		"minsoc": {[]string{"POST", "OPTIONS"}, "/minsoc/{value:[0-9]+}", remoteLoadpointHandler(remotes, func(lp loadpoint.API) http.HandlerFunc {
+			return intHandler(lp.SetMinSoC, lp.GetMinSoC)
+		})},
+	}
+
+	for _, r := range routes {
+		lpAPI.Methods(r.Methods...).Path(r.Pattern).Handler(r.HandlerFunc)
+	}
+}
+
+// remoteSite returns the remote site referenced by the request
+func remoteSite(remotes []*remote.Site, r *http.Request) (*remote.Site, error) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id >= len(remotes) {
+		return nil, errors.New("invalid remote")
+	}
+
+	return remotes[id], nil
+}
+
+// remotesHandler returns the list of remote sites
+func remotesHandler(remotes []*remote.Site) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		type remoteStatus struct {
+			Title        string `json:"title"`
+			URI          string `json:"uri"`
+			Controllable bool   `json:"controllable"`
+			Healthy      bool   `json:"healthy"`
+		}
+
+		res := make([]remoteStatus, 0, len(remotes))
+		for _, s := range remotes {
+			res = append(res, remoteStatus{
+				Title:        s.Title(),
+				URI:          s.URI(),
+				Controllable: s.Controllable(),
+				Healthy:      s.Healthy(),
+			})
+		}
+
+		jsonResult(w, res)
+	}
+}
+
+// remoteStateHandler returns the remote site's state
+func remoteStateHandler(remotes []*remote.Site) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		site, err := remoteSite(remotes, r)
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		jsonResult(w, site.State())
+	}
+}
+
+// remoteLoadpointHandler resolves the remote loadpoint and delegates to the loadpoint handler
+func remoteLoadpointHandler(remotes []*remote.Site, handler func(loadpoint.API) http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		site, err := remoteSite(remotes, r)
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		if !site.Controllable() {
+			jsonError(w, http.StatusForbidden, remote.ErrReadOnly)
+			return
+		}
+
+		lps := site.LoadPoints()
+
+		id, err := strconv.Atoi(mux.Vars(r)["lp"])
+		if err != nil || id >= len(lps) {
+			jsonError(w, http.StatusBadRequest, errors.New("invalid loadpoint"))
+			return
+		}
+
+		handler(lps[id])(w, r)
+	}
+}
+
+// remoteSessionHandler returns the combined sessions of local and remote sites
+func remoteSessionHandler(remotes []*remote.Site) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var res db.Sessions
+
+		if dbserver.Instance != nil {
+			if txn := dbserver.Instance.Where("charged_kwh>=0.05").Order("created desc").Find(&res); txn.Error != nil {
+				jsonError(w, http.StatusInternalServerError, txn.Error)
+				return
+			}
+		}
+
+		for _, site := range remotes {
+			sessions, err := site.Sessions()
+			if err != nil {
+				log.ERROR.Printf("remote %s sessions: %v", site.Title(), err)
+				continue
+			}
+
+			res = append(res, sessions...)
+		}
+
+		sort.SliceStable(res, func(i, j int) bool {
+			return res[i].Created.After(res[j].Created)
+		})
+
+		tableResult(w, r, "sessions", &res)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/evcc-io/evcc/core/consumer"
 	"github.com/evcc-io/evcc/core/loadpoint"
 	"github.com/evcc-io/evcc/util"
 	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
@@ -73,7 +74,7 @@ func (m *Influx) supportedType(p util.Param) bool {
 }
 
 // Run Influx publisher
-func (m *Influx) Run(loadPoints []loadpoint.API, in <-chan util.Param) {
+func (m *Influx) Run(loadPoints []loadpoint.API, consumers []consumer.API, in <-chan util.Param) {
 	writer := m.client.WriteAPI(m.org, m.database)
 
 	// log errors
@@ -106,6 +107,9 @@ func (m *Influx) Run(loadPoints []loadpoint.API, in <-chan util.Param) {
 			tags["loadpoint"] = loadPoints[*param.LoadPoint].Name()
 			tags["vehicle"] = vehicles[*param.LoadPoint]
 		}
+		if param.Consumer != nil {
+			tags["consumer"] = consumers[*param.Consumer].Name()
+		}
 
 		fields := map[string]interface{}{}
 
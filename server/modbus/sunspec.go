@@ -0,0 +1,241 @@
+package modbus
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/andig/mbserver"
+	"github.com/evcc-io/evcc/server"
+	"github.com/evcc-io/evcc/util"
+)
+
+// SunSpec register map
+const (
+	sunspecBase        = 40000 // SunSpec well-known base address
+	sunspecCommonModel = 1
+	sunspecCommonLen   = 66
+	sunspecMeterModel  = 203 // wye-connect three phase (abcn) meter
+	sunspecMeterLen    = 105
+	sunspecEndModel    = 0xFFFF
+
+	sunspecNotImplementedInt16 = 0x8000
+	sunspecNotImplementedSF    = 0x8000
+
+	// model 203 point offsets relative to model data start
+	m203A        = 0
+	m203AphA     = 1
+	m203A_SF     = 4
+	m203V_SF     = 13
+	m203Hz_SF    = 15
+	m203W        = 16
+	m203W_SF     = 20
+	m203VA_SF    = 25
+	m203VAR_SF   = 30
+	m203PF_SF    = 35
+	m203TotWhExp = 36
+	m203TotWhImp = 44
+	m203TotWh_SF = 52
+	m203VAh_SF   = 69
+	m203VArh_SF  = 102
+
+	sunspecTimeout = time.Minute // stop serving stale values after timeout
+)
+
+// SunSpec is a read-only modbus server emulating a SunSpec model 203 meter.
+// It exposes the site's grid meter measurements to inverters capable of zero-export regulation.
+type SunSpec struct {
+	log *util.Logger
+	mbserver.RequestHandler
+	id uint8
+
+	mu      sync.Mutex
+	regs    []uint16
+	meter   int // meter model data offset
+	updated time.Time
+}
+
+// NewSunSpec creates a SunSpec meter emulation for the given slave id, defaults to 1
+func NewSunSpec(id uint8) *SunSpec {
+	if id == 0 {
+		id = 1
+	}
+
+	s := &SunSpec{
+		log:            util.NewLogger("sunspec"),
+		RequestHandler: new(mbserver.DummyHandler), // supplies HandleCoils etc
+		id:             id,
+	}
+
+	s.regs = s.registers()
+
+	return s
+}
+
+// registers creates the initial SunSpec register map
+func (s *SunSpec) registers() []uint16 {
+	regs := []uint16{0x5375, 0x6e53} // SunS
+
+	// common model
+	common := make([]uint16, sunspecCommonLen)
+	copy(common[0:], sunspecString("evcc", 16))
+	copy(common[16:], sunspecString("Grid Meter", 16))
+	copy(common[40:], sunspecString(server.Version, 8))
+	copy(common[48:], sunspecString("evcc-grid", 16))
+	common[64] = uint16(s.id)
+
+	regs = append(regs, sunspecCommonModel, sunspecCommonLen)
+	regs = append(regs, common...)
+
+	// meter model
+	meter := make([]uint16, sunspecMeterLen)
+	for i := m203A; i < m203TotWhExp; i++ {
+		meter[i] = sunspecNotImplementedInt16
+	}
+	for _, sf := range []int{m203A_SF, m203V_SF, m203Hz_SF, m203W_SF, m203VA_SF, m203VAR_SF, m203PF_SF, m203VAh_SF, m203VArh_SF} {
+		meter[sf] = sunspecNotImplementedSF
+	}
+
+	// TotWhExp remains 0 (acc32 not implemented) since the grid meter only provides import energy
+
+	regs = append(regs, sunspecMeterModel, sunspecMeterLen)
+	s.meter = len(regs)
+	regs = append(regs, meter...)
+
+	// end marker
+	regs = append(regs, sunspecEndModel, 0)
+
+	return regs
+}
+
+// sunspecString encodes a string into the given number of registers
+func sunspecString(s string, regs int) []uint16 {
+	b := make([]byte, 2*regs)
+	copy(b, s)
+	return bytesAsUint16(b)
+}
+
+// scaled returns the value and scale factor such that the value fits into an int16 register
+func scaled(val float64) (uint16, uint16) {
+	var sf int16
+	for math.Abs(val) > math.MaxInt16 && sf < 10 {
+		val /= 10
+		sf++
+	}
+	return uint16(int16(math.Round(val))), uint16(sf)
+}
+
+// Start starts the modbus server at the given port
+func (s *SunSpec) Start(port int) error {
+	l, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return err
+	}
+
+	srv, err := mbserver.New(s)
+	if err == nil {
+		err = srv.Start(l)
+	}
+
+	if err == nil {
+		s.log.DEBUG.Printf("sunspec meter listening at :%d", port)
+	}
+
+	return err
+}
+
+// Run updates the register map from the site's published grid values
+func (s *SunSpec) Run(in <-chan util.Param) {
+	for p := range in {
+		if p.LoadPoint != nil || p.Consumer != nil {
+			continue
+		}
+
+		switch p.Key {
+		case "gridPower":
+			if val, ok := p.Val.(float64); ok {
+				s.setPower(val)
+			}
+		case "gridCurrents":
+			if val, ok := p.Val.([]float64); ok && len(val) == 3 {
+				s.setCurrents(val)
+			}
+		case "gridEnergy":
+			if val, ok := p.Val.(float64); ok {
+				s.setEnergy(val)
+			}
+		}
+	}
+}
+
+func (s *SunSpec) setPower(power float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.regs[s.meter+m203W], s.regs[s.meter+m203W_SF] = scaled(power)
+	s.updated = time.Now()
+}
+
+func (s *SunSpec) setCurrents(currents []float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// use centiampere resolution
+	var total float64
+	for i, c := range currents {
+		s.regs[s.meter+m203AphA+i] = uint16(int16(math.Round(100 * c)))
+		total += c
+	}
+	s.regs[s.meter+m203A] = uint16(int16(math.Round(100 * total)))
+	s.regs[s.meter+m203A_SF] = 0xFFFE // -2
+}
+
+func (s *SunSpec) setEnergy(energy float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// kWh to Wh, acc32
+	wh := uint32(math.Max(0, 1e3*energy))
+	s.regs[s.meter+m203TotWhImp] = uint16(wh >> 16)
+	s.regs[s.meter+m203TotWhImp+1] = uint16(wh)
+	s.regs[s.meter+m203TotWh_SF] = 0
+}
+
+// HandleHoldingRegisters implements mbserver.RequestHandler
+func (s *SunSpec) HandleHoldingRegisters(req *mbserver.HoldingRegistersRequest) ([]uint16, error) {
+	if req.IsWrite {
+		return nil, mbserver.ErrIllegalFunction
+	}
+
+	s.log.TRACE.Printf("read holding: id %d addr %d qty %d", req.UnitId, req.Addr, req.Quantity)
+
+	// only respond as the configured device
+	if req.UnitId != s.id {
+		return nil, mbserver.ErrGWTargetFailedToRespond
+	}
+
+	if req.Addr < sunspecBase {
+		return nil, mbserver.ErrIllegalDataAddress
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	start := int(req.Addr - sunspecBase)
+	end := start + int(req.Quantity)
+	if end > len(s.regs) {
+		return nil, mbserver.ErrIllegalDataAddress
+	}
+
+	// don't allow inverters to regulate on outdated values
+	if time.Since(s.updated) > sunspecTimeout && end > s.meter && start < s.meter+sunspecMeterLen {
+		return nil, mbserver.ErrServerDeviceFailure
+	}
+
+	res := make([]uint16, req.Quantity)
+	copy(res, s.regs[start:end])
+
+	return res, nil
+}
@@ -0,0 +1,105 @@
+package modbus
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	sunspec "github.com/andig/gosunspec"
+	bus "github.com/andig/gosunspec/modbus"
+	"github.com/andig/gosunspec/models/model203"
+	"github.com/andig/mbserver"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/modbus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSunSpecScaled(t *testing.T) {
+	tc := []struct {
+		in      float64
+		val, sf uint16
+	}{
+		{0, 0, 0},
+		{-1234, uint16(0xFFFF - 1234 + 1), 0},
+		{32767, 32767, 0},
+		{40000, 4000, 1},
+		{-400000, uint16(0xFFFF - 4000 + 1), 2},
+	}
+
+	for _, tc := range tc {
+		val, sf := scaled(tc.in)
+		assert.Equal(t, tc.val, val, "%v", tc.in)
+		assert.Equal(t, tc.sf, sf, "%v", tc.in)
+	}
+}
+
+func TestSunSpecMeter(t *testing.T) {
+	l, err := net.Listen("tcp", "localhost:0")
+	assert.NoError(t, err)
+	defer l.Close()
+
+	h := NewSunSpec(1)
+
+	srv, _ := mbserver.New(h)
+	assert.NoError(t, srv.Start(l))
+	defer func() { _ = srv.Stop() }()
+
+	conn, err := modbus.NewConnection(l.Addr().String(), "", "", 0, modbus.Tcp, 1)
+	assert.NoError(t, err)
+
+	// stale values must not be served
+	_, err = conn.ReadHoldingRegisters(sunspecBase, uint16(len(h.regs)))
+	assert.Error(t, err)
+
+	in := make(chan util.Param)
+	go h.Run(in)
+
+	in <- util.Param{Key: "gridCurrents", Val: []float64{1, 2, 3}}
+	in <- util.Param{Key: "gridEnergy", Val: 12.345}
+	in <- util.Param{Key: "gridPower", Val: -1234.0}
+	in <- util.Param{LoadPoint: new(int), Key: "gridPower", Val: 999.0} // ignored
+	close(in)
+
+	// wait for the last value to be applied
+	power, _ := scaled(-1234)
+	assert.Eventually(t, func() bool {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		return h.regs[h.meter+m203W] == power
+	}, time.Second, 10*time.Millisecond)
+
+	dev, err := bus.Open(conn)
+	assert.NoError(t, err)
+
+	var found bool
+	dev.Do(func(d sunspec.Device) {
+		d.Do(func(m sunspec.Model) {
+			if m.Id() != model203.ModelID {
+				return
+			}
+			found = true
+
+			b := m.MustBlock(0)
+			assert.NoError(t, b.Read())
+
+			assert.Equal(t, -1234.0, b.MustPoint(model203.W).ScaledValue())
+			assert.Equal(t, 12345.0, b.MustPoint(model203.TotWhImp).ScaledValue())
+			assert.InDelta(t, 2.0, b.MustPoint(model203.AphB).ScaledValue(), 1e-6)
+			assert.InDelta(t, 6.0, b.MustPoint(model203.A).ScaledValue(), 1e-6)
+		})
+	})
+
+	assert.True(t, found, "model 203 not found")
+
+	// other unit ids are not served
+	other, err := modbus.NewConnection(l.Addr().String(), "", "", 0, modbus.Tcp, 2)
+	assert.NoError(t, err)
+
+	_, err = other.ReadHoldingRegisters(sunspecBase, 2)
+	assert.Error(t, err)
+
+	// export energy is not implemented
+	b, err := conn.ReadHoldingRegisters(sunspecBase+uint16(h.meter+m203TotWhExp), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0, 0, 0, 0}, b)
+}
@@ -86,44 +86,60 @@ func (m *MQTT) publish(topic string, retained bool, payload interface{}) {
 }
 
 func (m *MQTT) listenSetters(topic string, site site.API, lp loadpoint.API) {
-	m.Handler.ListenSetter(topic+"/mode/set", func(payload string) {
-		lp.SetMode(api.ChargeMode(payload))
+	m.Handler.ListenSetter(topic+"/mode/set", func(payload string) error {
+		return lp.SetMode(api.ChargeMode(payload))
 	})
-	m.Handler.ListenSetter(topic+"/minSoC/set", func(payload string) {
-		if soc, err := strconv.Atoi(payload); err == nil {
-			lp.SetMinSoC(soc)
+	m.Handler.ListenSetter(topic+"/minSoC/set", func(payload string) error {
+		soc, err := strconv.Atoi(payload)
+		if err == nil {
+			err = lp.SetMinSoC(soc)
 		}
+		return err
 	})
-	m.Handler.ListenSetter(topic+"/targetSoC/set", func(payload string) {
-		if soc, err := strconv.Atoi(payload); err == nil {
-			lp.SetTargetSoC(soc)
+	m.Handler.ListenSetter(topic+"/targetSoC/set", func(payload string) error {
+		soc, err := strconv.Atoi(payload)
+		if err == nil {
+			err = lp.SetTargetSoC(soc)
 		}
+		return err
 	})
-	m.Handler.ListenSetter(topic+"/minCurrent/set", func(payload string) {
-		if current, err := strconv.ParseFloat(payload, 64); err == nil {
-			lp.SetMinCurrent(current)
+	m.Handler.ListenSetter(topic+"/minCurrent/set", func(payload string) error {
+		current, err := strconv.ParseFloat(payload, 64)
+		if err == nil {
+			err = lp.SetMinCurrent(current)
 		}
+		return err
 	})
-	m.Handler.ListenSetter(topic+"/maxCurrent/set", func(payload string) {
-		if current, err := strconv.ParseFloat(payload, 64); err == nil {
-			lp.SetMaxCurrent(current)
+	m.Handler.ListenSetter(topic+"/maxCurrent/set", func(payload string) error {
+		current, err := strconv.ParseFloat(payload, 64)
+		if err == nil {
+			err = lp.SetMaxCurrent(current)
 		}
+		return err
 	})
-	m.Handler.ListenSetter(topic+"/phases/set", func(payload string) {
-		if phases, err := strconv.Atoi(payload); err == nil {
-			_ = lp.SetPhases(phases)
+	m.Handler.ListenSetter(topic+"/phases/set", func(payload string) error {
+		phases, err := strconv.Atoi(payload)
+		if err == nil {
+			err = lp.SetPhases(phases)
 		}
+		return err
 	})
-	m.Handler.ListenSetter(topic+"/vehicle/set", func(payload string) {
-		if vehicle, err := strconv.Atoi(payload); err == nil {
-			if vehicle >= 0 {
-				if vehicles := site.GetVehicles(); vehicle < len(vehicles) {
-					lp.SetVehicle(vehicles[vehicle])
-				}
-			} else {
-				lp.SetVehicle(nil)
-			}
+	m.Handler.ListenSetter(topic+"/vehicle/set", func(payload string) error {
+		vehicle, err := strconv.Atoi(payload)
+		if err != nil {
+			return err
 		}
+
+		if vehicle < 0 {
+			return lp.SetVehicle(nil)
+		}
+
+		vehicles := site.GetVehicles()
+		if vehicle >= len(vehicles) {
+			return fmt.Errorf("invalid vehicle: %d", vehicle)
+		}
+
+		return lp.SetVehicle(vehicles[vehicle])
 	})
 }
 
@@ -134,22 +150,28 @@ func (m *MQTT) Run(site site.API, in <-chan util.Param) {
 	m.publish(topic, true, "online")
 
 	// site setters
-	m.Handler.ListenSetter(fmt.Sprintf("%s/site/prioritySoC/set", m.root), func(payload string) {
-		if soc, err := strconv.Atoi(payload); err == nil {
-			_ = site.SetPrioritySoC(float64(soc))
+	m.Handler.ListenSetter(fmt.Sprintf("%s/site/prioritySoC/set", m.root), func(payload string) error {
+		soc, err := strconv.Atoi(payload)
+		if err == nil {
+			err = site.SetPrioritySoC(float64(soc))
 		}
+		return err
 	})
 
-	m.Handler.ListenSetter(fmt.Sprintf("%s/site/bufferSoC/set", m.root), func(payload string) {
-		if soc, err := strconv.Atoi(payload); err == nil {
-			_ = site.SetBufferSoC(float64(soc))
+	m.Handler.ListenSetter(fmt.Sprintf("%s/site/bufferSoC/set", m.root), func(payload string) error {
+		soc, err := strconv.Atoi(payload)
+		if err == nil {
+			err = site.SetBufferSoC(float64(soc))
 		}
+		return err
 	})
 
-	m.Handler.ListenSetter(fmt.Sprintf("%s/site/residualPower/set", m.root), func(payload string) {
-		if soc, err := strconv.Atoi(payload); err == nil {
-			_ = site.SetResidualPower(float64(soc))
+	m.Handler.ListenSetter(fmt.Sprintf("%s/site/residualPower/set", m.root), func(payload string) error {
+		soc, err := strconv.Atoi(payload)
+		if err == nil {
+			err = site.SetResidualPower(float64(soc))
 		}
+		return err
 	})
 
 	// number of loadpoints
@@ -162,6 +184,19 @@ func (m *MQTT) Run(site site.API, in <-chan util.Param) {
 		m.listenSetters(topic, site, lp)
 	}
 
+	// number of consumers
+	topic = fmt.Sprintf("%s/consumers", m.root)
+	m.publish(topic, true, len(site.Consumers()))
+
+	// consumer setters
+	for id, c := range site.Consumers() {
+		c := c
+		topic := fmt.Sprintf("%s/consumers/%d", m.root, id+1)
+		m.Handler.ListenSetter(topic+"/mode/set", func(payload string) error {
+			return c.SetMode(api.ChargeMode(payload))
+		})
+	}
+
 	// TODO remove deprecated topics
 	for id := range site.LoadPoints() {
 		topic := fmt.Sprintf("%s/loadpoints/%d", m.root, id+1)
@@ -180,6 +215,10 @@ func (m *MQTT) Run(site site.API, in <-chan util.Param) {
 			id := *p.LoadPoint + 1
 			topic = fmt.Sprintf("%s/loadpoints/%d", m.root, id)
 		}
+		if p.Consumer != nil {
+			id := *p.Consumer + 1
+			topic = fmt.Sprintf("%s/consumers/%d", m.root, id)
+		}
 
 		// alive indicator
 		if time.Since(updated) > time.Second {
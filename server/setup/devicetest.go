@@ -0,0 +1,171 @@
+package setup
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/charger"
+	"github.com/evcc-io/evcc/meter"
+	"github.com/evcc-io/evcc/util/templates"
+	"github.com/evcc-io/evcc/vehicle"
+	"gopkg.in/yaml.v3"
+)
+
+type Result string
+
+const (
+	ResultValid             Result = "Valid"
+	ResultValidMissingMeter Result = "Valid_MissingMeter"
+	ResultInvalid           Result = "Invalid"
+)
+
+// DeviceTest tests a device created from template values
+type DeviceTest struct {
+	Class        templates.Class
+	Usage        string // meter usage, e.g. grid, pv or battery
+	Template     templates.Template
+	ConfigValues map[string]interface{}
+}
+
+// Test returns:
+// - Result: Valid, Valid_MissingMeter, Invalid
+// - error: != nil if the device is invalid and can not be configured with the provided settings
+// Retries are aborted when the context is done.
+func (d *DeviceTest) Test(ctx context.Context) (Result, error) {
+	v, err := d.configure()
+	if err != nil {
+		return ResultInvalid, err
+	}
+
+	// test instances are discarded
+	if c, ok := v.(io.Closer); ok {
+		defer c.Close()
+	}
+
+	switch d.Class {
+	case templates.Charger:
+		return d.testCharger(v)
+
+	case templates.Meter:
+		return d.testMeter(ctx, v)
+
+	case templates.Vehicle:
+		return d.testVehicle(v)
+
+	default:
+		return ResultInvalid, errors.New("invalid class: " + string(d.Class))
+	}
+}
+
+// configure creates a configured device from a template so we can test it
+func (d *DeviceTest) configure() (interface{}, error) {
+	b, _, err := d.Template.RenderResult(templates.TemplateRenderModeInstance, d.ConfigValues)
+	if err != nil {
+		return nil, err
+	}
+
+	var instance struct {
+		Type  string
+		Other map[string]interface{} `yaml:",inline"`
+	}
+
+	if err := yaml.Unmarshal(b, &instance); err != nil {
+		return nil, err
+	}
+
+	var v interface{}
+
+	switch d.Class {
+	case templates.Meter:
+		v, err = meter.NewFromConfig(instance.Type, instance.Other)
+	case templates.Charger:
+		v, err = charger.NewFromConfig(instance.Type, instance.Other)
+	case templates.Vehicle:
+		v, err = vehicle.NewFromConfig(instance.Type, instance.Other)
+	}
+
+	return v, err
+}
+
+// testCharger tests a charger device
+func (d *DeviceTest) testCharger(v interface{}) (Result, error) {
+	c, ok := v.(api.Charger)
+	if !ok {
+		return ResultInvalid, errors.New("selected device is not a wallbox")
+	}
+	if _, err := c.Status(); err != nil {
+		return ResultInvalid, err
+	}
+
+	m, ok := v.(api.Meter)
+	if !ok {
+		return ResultValidMissingMeter, nil
+	}
+	if _, err := m.CurrentPower(); err != nil {
+		return ResultInvalid, err
+	}
+
+	return ResultValid, nil
+}
+
+// testMeter tests a meter device
+func (d *DeviceTest) testMeter(ctx context.Context, v interface{}) (Result, error) {
+	m, ok := v.(api.Meter)
+	if !ok {
+		return ResultInvalid, errors.New("selected device is not a meter")
+	}
+
+	power, err := m.CurrentPower()
+	if err != nil {
+		return ResultInvalid, err
+	}
+
+	// check if the grid meter reports power 0, which should be impossible
+	// happens with Kostal Piko charger that do not have a grid meter attached
+	// but we can't determine this
+	if power == 0 && d.Usage == templates.UsageChoiceGrid {
+		return ResultInvalid, errors.New("grid meter reports power 0")
+	}
+
+	if d.Usage == templates.UsageChoiceBattery {
+		b, ok := v.(api.Battery)
+		if !ok {
+			return ResultInvalid, errors.New("selected device is not a battery meter")
+		}
+
+		_, err := b.SoC()
+
+		for err != nil && errors.Is(err, api.ErrMustRetry) {
+			select {
+			case <-ctx.Done():
+				return ResultInvalid, api.ErrTimeout
+			case <-time.After(3 * time.Second):
+			}
+
+			_, err = b.SoC()
+		}
+
+		if err != nil {
+			return ResultInvalid, err
+		}
+	}
+
+	return ResultValid, nil
+}
+
+// testVehicle tests a vehicle device
+func (d *DeviceTest) testVehicle(v interface{}) (Result, error) {
+	vv, ok := v.(api.Vehicle)
+	if !ok {
+		return ResultInvalid, errors.New("selected device is not a vehicle")
+	}
+
+	if _, err := vv.SoC(); err != nil {
+		return ResultInvalid, err
+	}
+
+	return ResultValid, nil
+}
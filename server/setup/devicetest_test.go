@@ -0,0 +1,31 @@
+package setup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util/templates"
+	"github.com/stretchr/testify/assert"
+)
+
+type retryBattery struct{}
+
+func (b *retryBattery) CurrentPower() (float64, error) {
+	return 100, nil
+}
+
+func (b *retryBattery) SoC() (float64, error) {
+	return 0, api.ErrMustRetry
+}
+
+func TestMeterRetryTimeout(t *testing.T) {
+	d := DeviceTest{Class: templates.Meter, Usage: templates.UsageChoiceBattery}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	res, err := d.testMeter(ctx, new(retryBattery))
+	assert.Equal(t, ResultInvalid, res)
+	assert.ErrorIs(t, err, api.ErrTimeout)
+}
@@ -0,0 +1,49 @@
+package setup
+
+import (
+	"net/http"
+
+	"github.com/evcc-io/evcc/detect"
+	"github.com/gorilla/mux"
+)
+
+// RegisterDiscoveryHandlers connects the http handlers for the background network discovery
+func RegisterDiscoveryHandlers(router *mux.Router, d *detect.Discovery) {
+	api := apiRouter(router, "/api/discovery")
+
+	routes := map[string]route{
+		"status": {[]string{"GET"}, "", discoveryHandler(d)},
+		"scan":   {[]string{"POST", "OPTIONS"}, "", scanHandler(d)},
+	}
+
+	for _, r := range routes {
+		api.Methods(r.Methods...).Path(r.Pattern).Handler(r.HandlerFunc)
+	}
+}
+
+// discoveryHandler returns the discovered devices, optionally filtered by class
+func discoveryHandler(d *detect.Discovery) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res := d.Status()
+
+		if class := r.URL.Query().Get("class"); class != "" {
+			hits := make([]detect.Hit, 0, len(res.Hits))
+			for _, hit := range res.Hits {
+				if string(hit.Class) == class {
+					hits = append(hits, hit)
+				}
+			}
+			res.Hits = hits
+		}
+
+		jsonResult(w, res)
+	}
+}
+
+// scanHandler triggers a new network scan
+func scanHandler(d *detect.Discovery) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		d.Scan()
+		jsonResult(w, d.Status())
+	}
+}
@@ -0,0 +1,235 @@
+package setup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/evcc-io/evcc/server"
+	"github.com/evcc-io/evcc/server/db/config"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/templates"
+	"github.com/gorilla/handlers"
+	"github.com/gorilla/mux"
+	"gopkg.in/yaml.v3"
+)
+
+// request is the device configuration entered in the setup wizard
+type request struct {
+	Name     string                 `json:"name"`
+	Template string                 `json:"template"`
+	Usage    string                 `json:"usage"`
+	Values   map[string]interface{} `json:"values"`
+}
+
+// other returns the template configuration
+func (req request) other() map[string]interface{} {
+	res := make(map[string]interface{}, len(req.Values)+1)
+	for k, v := range req.Values {
+		res[k] = v
+	}
+	res["template"] = req.Template
+	if req.Usage != "" {
+		res[templates.ParamUsage] = req.Usage
+	}
+	return res
+}
+
+var log = util.NewLogger("setup")
+
+type route struct {
+	Methods     []string
+	Pattern     string
+	HandlerFunc http.HandlerFunc
+}
+
+// apiRouter creates a json api subrouter for the given path prefix
+func apiRouter(router *mux.Router, prefix string) *mux.Router {
+	api := router.PathPrefix(prefix).Subrouter()
+	api.Use(func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+			h.ServeHTTP(w, r)
+		})
+	})
+	api.Use(handlers.CompressHandler)
+	api.Use(handlers.CORS(
+		handlers.AllowedHeaders([]string{"Content-Type"}),
+	))
+
+	return api
+}
+
+// RegisterHandlers connects the http handlers for the setup wizard.
+// Devices are only added to the running instance if runtime configuration is available.
+func RegisterHandlers(router *mux.Router, dc server.DeviceConfigurator) {
+	api := apiRouter(router, "/api/setup")
+
+	class := "{class:meter|charger|vehicle}"
+
+	routes := map[string]route{
+		"templates": {[]string{"GET"}, "/templates/" + class, templatesHandler},
+		"form":      {[]string{"GET"}, "/templates/" + class + "/{template}", formHandler},
+		"test":      {[]string{"POST", "OPTIONS"}, "/test/" + class, testHandler},
+		"save":      {[]string{"POST", "OPTIONS"}, "/devices/" + class, saveHandler(dc)},
+	}
+
+	for _, r := range routes {
+		api.Methods(r.Methods...).Path(r.Pattern).Handler(r.HandlerFunc)
+	}
+}
+
+func jsonWrite(w http.ResponseWriter, content interface{}) {
+	if err := json.NewEncoder(w).Encode(content); err != nil {
+		log.ERROR.Printf("failed to encode JSON: %v", err)
+	}
+}
+
+func jsonResult(w http.ResponseWriter, res interface{}) {
+	jsonWrite(w, map[string]interface{}{"result": res})
+}
+
+func jsonError(w http.ResponseWriter, status int, err error) {
+	w.WriteHeader(status)
+	jsonWrite(w, map[string]interface{}{"error": err.Error()})
+}
+
+// requestLang returns the requested language
+func requestLang(r *http.Request) string {
+	if lang := r.URL.Query().Get("lang"); lang != "" {
+		return lang
+	}
+	return "en"
+}
+
+// templatesHandler returns the available templates of a class
+func templatesHandler(w http.ResponseWriter, r *http.Request) {
+	class := templates.Class(mux.Vars(r)["class"])
+	jsonResult(w, Templates(class, r.URL.Query().Get("usage"), requestLang(r)))
+}
+
+// formHandler returns the template's parameters
+func formHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	res, err := Form(templates.Class(vars["class"]), vars["template"], requestLang(r))
+	if err != nil {
+		jsonError(w, http.StatusNotFound, err)
+		return
+	}
+
+	jsonResult(w, res)
+}
+
+// decodeRequest decodes and validates the setup request
+func decodeRequest(r *http.Request) (templates.Template, request, error) {
+	var req request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return templates.Template{}, req, err
+	}
+
+	// references are only resolved for the configuration file
+	if util.ContainsSecretRef(req.Values) {
+		return templates.Template{}, req, errors.New("secret references are only supported in the configuration file")
+	}
+
+	tmpl, err := templates.ByName(templates.Class(mux.Vars(r)["class"]), req.Template)
+	if err != nil {
+		return tmpl, req, err
+	}
+
+	return tmpl, req, tmpl.Check(req.other())
+}
+
+// testTimeout limits retries of device tests
+const testTimeout = time.Minute
+
+// testHandler creates the device and tests its connection
+func testHandler(w http.ResponseWriter, r *http.Request) {
+	tmpl, req, err := decodeRequest(r)
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	test := DeviceTest{
+		Class:        templates.Class(mux.Vars(r)["class"]),
+		Usage:        req.Usage,
+		Template:     tmpl,
+		ConfigValues: req.other(),
+	}
+
+	res := struct {
+		Result Result `json:"result"`
+		Error  string `json:"error,omitempty"`
+	}{}
+
+	ctx, cancel := context.WithTimeout(r.Context(), testTimeout)
+	defer cancel()
+
+	res.Result, err = test.Test(ctx)
+	if err != nil {
+		res.Error = err.Error()
+	}
+
+	jsonResult(w, res)
+}
+
+// saveHandler adds the device to the running instance and returns its yaml configuration
+func saveHandler(dc server.DeviceConfigurator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_, req, err := decodeRequest(r)
+		if err == nil && req.Name == "" {
+			err = errors.New("missing name")
+		}
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		other := req.other()
+
+		b, err := yaml.Marshal(struct {
+			Name  string
+			Type  string
+			Other map[string]interface{} `yaml:",inline"`
+		}{
+			Name:  req.Name,
+			Type:  "template",
+			Other: other,
+		})
+		if err != nil {
+			jsonError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		res := struct {
+			ID   int    `json:"id,omitempty"`
+			Yaml string `json:"yaml"`
+		}{
+			Yaml: string(b),
+		}
+
+		// without runtime configuration the yaml must be added manually
+		if dc == nil {
+			jsonResult(w, res)
+			return
+		}
+
+		conf := config.Config{Class: mux.Vars(r)["class"], Name: req.Name, Type: "template"}
+		if err := conf.SetOther(other); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		if conf, err = dc.AddDevice(conf); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		res.ID = conf.ID
+		jsonResult(w, res)
+	}
+}
@@ -0,0 +1,152 @@
+package setup
+
+import (
+	"sort"
+
+	"github.com/evcc-io/evcc/util/templates"
+	"golang.org/x/exp/slices"
+)
+
+// Product is a product supported by a template
+type Product struct {
+	Brand       string `json:"brand"`
+	Description string `json:"description,omitempty"`
+}
+
+// TemplateInfo is a template summary for selection
+type TemplateInfo struct {
+	Template string    `json:"template"`
+	Group    string    `json:"group,omitempty"`
+	Products []Product `json:"products"`
+	Usages   []string  `json:"usages,omitempty"`
+}
+
+// ParamInfo is the form representation of a template parameter
+type ParamInfo struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Help        string   `json:"help,omitempty"`
+	Default     string   `json:"default,omitempty"`
+	Example     string   `json:"example,omitempty"`
+	Type        string   `json:"type"`
+	Required    bool     `json:"required,omitempty"`
+	Mask        bool     `json:"mask,omitempty"`
+	Advanced    bool     `json:"advanced,omitempty"`
+	ValidValues []string `json:"validValues,omitempty"`
+}
+
+// TemplateForm describes the parameters required for configuring a template.
+// Modbus contains the additional parameters per modbus connection type.
+type TemplateForm struct {
+	Template     string                 `json:"template"`
+	Requirements string                 `json:"requirements,omitempty"`
+	Params       []ParamInfo            `json:"params"`
+	Modbus       map[string][]ParamInfo `json:"modbus,omitempty"`
+}
+
+// Templates returns the templates of a class sorted by title, optionally filtered by usage
+func Templates(class templates.Class, usage, lang string) []TemplateInfo {
+	var res []TemplateInfo
+
+	for _, tmpl := range templates.ByClass(class) {
+		usages := tmpl.Usages()
+		if usage != "" && len(usages) > 0 && !slices.Contains(usages, usage) {
+			continue
+		}
+
+		tmpl.Lang = lang
+
+		info := TemplateInfo{
+			Template: tmpl.Template,
+			Group:    tmpl.GroupTitle(),
+			Usages:   usages,
+		}
+
+		for _, p := range tmpl.Products {
+			info.Products = append(info.Products, Product{
+				Brand:       p.Brand,
+				Description: p.Description.String(lang),
+			})
+		}
+
+		res = append(res, info)
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return title(res[i]) < title(res[j])
+	})
+
+	return res
+}
+
+func title(t TemplateInfo) string {
+	if len(t.Products) == 0 {
+		return t.Template
+	}
+	return t.Products[0].Brand + " " + t.Products[0].Description
+}
+
+func paramInfo(p templates.Param, lang string) ParamInfo {
+	res := ParamInfo{
+		Name:        p.Name,
+		Description: p.Description.String(lang),
+		Help:        p.Help.String(lang),
+		Default:     p.Default,
+		Example:     p.Example,
+		Type:        p.ValueType,
+		Required:    p.Required,
+		Mask:        p.Mask,
+		Advanced:    p.Advanced,
+		ValidValues: p.ValidValues,
+	}
+
+	if len(p.Choice) > 0 {
+		res.ValidValues = p.Choice
+	}
+
+	return res
+}
+
+// Form returns the form description of a template
+func Form(class templates.Class, name, lang string) (TemplateForm, error) {
+	tmpl, err := templates.ByName(class, name)
+	if err != nil {
+		return TemplateForm{}, err
+	}
+
+	res := TemplateForm{
+		Template:     tmpl.Template,
+		Requirements: tmpl.Requirements.Description.String(lang),
+	}
+
+	for _, p := range tmpl.Params {
+		if p.Deprecated || p.Hidden {
+			continue
+		}
+
+		info := paramInfo(p, lang)
+
+		// expand modbus interfaces into connection types
+		if p.Name == templates.ParamModbus {
+			info.ValidValues = nil
+			for _, choice := range p.Choice {
+				for _, iface := range tmpl.ConfigDefaults.Modbus.Interfaces[choice] {
+					info.ValidValues = append(info.ValidValues, iface)
+
+					if res.Modbus == nil {
+						res.Modbus = make(map[string][]ParamInfo)
+					}
+
+					for _, mp := range tmpl.ConfigDefaults.Modbus.Types[iface].Params {
+						res.Modbus[iface] = append(res.Modbus[iface], paramInfo(mp, lang))
+					}
+				}
+			}
+			info.Required = true
+		}
+
+		res.Params = append(res.Params, info)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,39 @@
+package setup
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/util/templates"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplates(t *testing.T) {
+	all := Templates(templates.Meter, "", "en")
+	pv := Templates(templates.Meter, templates.UsageChoicePV, "en")
+
+	assert.NotEmpty(t, pv)
+	assert.Less(t, len(pv), len(all))
+}
+
+func TestForm(t *testing.T) {
+	form, err := Form(templates.Charger, "abl", "en")
+	require.NoError(t, err)
+
+	var modbus *ParamInfo
+	for i, p := range form.Params {
+		if p.Name == templates.ParamModbus {
+			modbus = &form.Params[i]
+		}
+	}
+
+	require.NotNil(t, modbus)
+	assert.NotEmpty(t, modbus.ValidValues)
+
+	for _, iface := range modbus.ValidValues {
+		assert.Contains(t, form.Modbus, iface)
+	}
+
+	_, err = Form(templates.Charger, "foo", "en")
+	assert.Error(t, err)
+}
@@ -125,6 +125,9 @@ func kv(p util.Param) string {
 	if p.LoadPoint != nil {
 		msg.WriteString(fmt.Sprintf("loadpoints.%d.", *p.LoadPoint))
 	}
+	if p.Consumer != nil {
+		msg.WriteString(fmt.Sprintf("consumers.%d.", *p.Consumer))
+	}
 	msg.WriteString(p.Key)
 	msg.WriteString("\":")
 	msg.WriteString(val)
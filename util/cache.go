@@ -27,30 +27,42 @@ func (c *Cache) Run(in <-chan Param) {
 		if p.LoadPoint != nil {
 			key = fmt.Sprintf("lp-%d/%s", *p.LoadPoint+1, key)
 		}
+		if p.Consumer != nil {
+			key = fmt.Sprintf("consumer-%d/%s", *p.Consumer+1, key)
+		}
 		log.TRACE.Printf("%s: %v", key, p.Val)
 		c.Add(p.UniqueID(), p)
 	}
 }
 
 // State provides a structured copy of the cached values
-// Loadpoints are aggregated as loadpoints array
+// Loadpoints and consumers are aggregated as loadpoints and consumers arrays
 func (c *Cache) State() map[string]interface{} {
 	c.Lock()
 	defer c.Unlock()
 
 	res := map[string]interface{}{}
 	lps := make(map[int]map[string]interface{})
+	cs := make(map[int]map[string]interface{})
 
 	for _, param := range c.val {
-		if param.LoadPoint == nil {
-			res[param.Key] = param.Val
-		} else {
+		switch {
+		case param.LoadPoint != nil:
 			lp, ok := lps[*param.LoadPoint]
 			if !ok {
 				lp = make(map[string]interface{})
 				lps[*param.LoadPoint] = lp
 			}
 			lp[param.Key] = param.Val
+		case param.Consumer != nil:
+			cons, ok := cs[*param.Consumer]
+			if !ok {
+				cons = make(map[string]interface{})
+				cs[*param.Consumer] = cons
+			}
+			cons[param.Key] = param.Val
+		default:
+			res[param.Key] = param.Val
 		}
 	}
 
@@ -61,6 +73,14 @@ func (c *Cache) State() map[string]interface{} {
 	}
 	res["loadpoints"] = loadpoints
 
+	if len(cs) > 0 {
+		consumers := make([]map[string]interface{}, len(cs))
+		for id, cons := range cs {
+			consumers[id] = cons
+		}
+		res["consumers"] = consumers
+	}
+
 	return res
 }
 
@@ -191,6 +191,28 @@ func (mb *Connection) WriteSingleCoil(address, quantity uint16) ([]byte, error)
 	return mb.WriteSingleCoilWithSlave(mb.slaveID, address, quantity)
 }
 
+// ReadCoil reads a single coil and returns it as 16 bit register value 0 or 1
+func (mb *Connection) ReadCoil(address uint16) ([]byte, error) {
+	b, err := mb.ReadCoils(address, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != 1 {
+		return nil, fmt.Errorf("unexpected length: %d", len(b))
+	}
+	return []byte{0, b[0] & 1}, nil
+}
+
+// WriteCoil switches a single coil on or off
+func (mb *Connection) WriteCoil(address uint16, on bool) error {
+	var val uint16
+	if on {
+		val = 0xFF00
+	}
+	_, err := mb.WriteSingleCoil(address, val)
+	return err
+}
+
 func (mb *Connection) ReadInputRegisters(address, quantity uint16) ([]byte, error) {
 	return mb.ReadInputRegistersWithSlave(mb.slaveID, address, quantity)
 }
@@ -377,6 +399,10 @@ func RegisterOperation(r Register) (rs485.Operation, error) {
 		op.FuncCode = modbus.FuncCodeReadInputRegisters
 	case "writesingle":
 		op.FuncCode = modbus.FuncCodeWriteSingleRegister
+	case "coil":
+		op.FuncCode = modbus.FuncCodeReadCoils
+	case "writecoil":
+		op.FuncCode = modbus.FuncCodeWriteSingleCoil
 	default:
 		return rs485.Operation{}, fmt.Errorf("invalid register type: %s", r.Type)
 	}
@@ -437,6 +463,11 @@ func RegisterOperation(r Register) (rs485.Operation, error) {
 		return rs485.Operation{}, fmt.Errorf("invalid register decoding: %s", r.Decode)
 	}
 
+	// coils are read one at a time as 16 bit value
+	if op.FuncCode == modbus.FuncCodeReadCoils && op.ReadLen != 1 {
+		return rs485.Operation{}, fmt.Errorf("invalid coil decoding: %s", r.Decode)
+	}
+
 	return op, nil
 }
 
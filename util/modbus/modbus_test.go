@@ -1,6 +1,13 @@
 package modbus
 
-import "testing"
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/andig/mbserver"
+	"github.com/grid-x/modbus"
+)
 
 func TestParsePoint(t *testing.T) {
 	tc := []struct {
@@ -28,3 +35,87 @@ func TestParsePoint(t *testing.T) {
 		}
 	}
 }
+
+func TestRegisterOperation(t *testing.T) {
+	tc := []struct {
+		typ, decode string
+		funcCode    uint8
+		readLen     uint16
+		err         bool
+	}{
+		{"holding", "uint32", modbus.FuncCodeReadHoldingRegisters, 2, false},
+		{"input", "int16", modbus.FuncCodeReadInputRegisters, 1, false},
+		{"writesingle", "uint16", modbus.FuncCodeWriteSingleRegister, 1, false},
+		{"coil", "uint16", modbus.FuncCodeReadCoils, 1, false},
+		{"coil", "uint32", 0, 0, true},
+		{"writecoil", "uint16", modbus.FuncCodeWriteSingleCoil, 1, false},
+		{"foo", "uint16", 0, 0, true},
+	}
+
+	for _, tc := range tc {
+		t.Log(tc)
+
+		op, err := RegisterOperation(Register{Type: tc.typ, Decode: tc.decode})
+
+		if (err != nil) != tc.err {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		if !tc.err && (op.FuncCode != tc.funcCode || op.ReadLen != tc.readLen) {
+			t.Errorf("unexpected result: %d %d", op.FuncCode, op.ReadLen)
+		}
+	}
+}
+
+type coilHandler struct {
+	mbserver.DummyHandler
+	coils []bool
+}
+
+func (h *coilHandler) HandleCoils(req *mbserver.CoilsRequest) ([]bool, error) {
+	if req.IsWrite {
+		h.coils[req.Addr] = req.Args[0]
+		return nil, nil
+	}
+	return h.coils[req.Addr : req.Addr+req.Quantity], nil
+}
+
+func TestCoil(t *testing.T) {
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	h := &coilHandler{coils: make([]bool, 8)}
+
+	srv, _ := mbserver.New(h)
+	if err := srv.Start(l); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = srv.Stop() }()
+
+	conn, err := NewConnection(l.Addr().String(), "", "", 0, Tcp, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, on := range []bool{true, false} {
+		if err := conn.WriteCoil(3, on); err != nil {
+			t.Fatal(err)
+		}
+
+		if h.coils[3] != on {
+			t.Errorf("unexpected coil: %v", h.coils[3])
+		}
+
+		b, err := conn.ReadCoil(3)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if expected := []byte{0, map[bool]byte{true: 1}[on]}; !bytes.Equal(b, expected) {
+			t.Errorf("unexpected read: %v", b)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/evcc-io/evcc/api/store"
 	"github.com/imdario/mergo"
 	"golang.org/x/oauth2"
 )
@@ -17,30 +18,62 @@ type TokenSource struct {
 	mu        sync.Mutex
 	token     *oauth2.Token
 	refresher TokenRefresher
+	store     store.Store
 }
 
 func RefreshTokenSource(token *oauth2.Token, refresher TokenRefresher) oauth2.TokenSource {
 	return &TokenSource{token: token, refresher: refresher}
 }
 
+// PersistentRefreshTokenSource creates a refreshing token source that saves obtained tokens to the store.
+// Without token, the stored token is used if available. If refreshing a token fails, the refresher
+// is called with nil token for obtaining a new token by login.
+func PersistentRefreshTokenSource(token *oauth2.Token, refresher TokenRefresher, store store.Store) *TokenSource {
+	if token == nil {
+		var stored oauth2.Token
+		if err := store.Load(&stored); err == nil && (stored.RefreshToken != "" || stored.Valid()) {
+			token = &stored
+		}
+	}
+
+	return &TokenSource{token: token, refresher: refresher, store: store}
+}
+
 func (ts *TokenSource) Token() (*oauth2.Token, error) {
 	ts.mu.Lock()
 	defer ts.mu.Unlock()
 	var err error
 	if ts.token == nil || time.Until(ts.token.Expiry) < time.Minute {
 		var token *oauth2.Token
-		if token, err = ts.refresher.RefreshToken(ts.token); err == nil {
+		token, err = ts.refresher.RefreshToken(ts.token)
+
+		// login if the stored token can't be refreshed
+		if err != nil && ts.store != nil && ts.token != nil {
+			ts.token = nil
+			token, err = ts.refresher.RefreshToken(nil)
+		}
+
+		if err == nil {
 			if token.AccessToken == "" {
 				err = errors.New("token refresh failed to obtain access token")
 			} else {
 				err = ts.mergeToken(token)
 			}
 		}
+
+		if err == nil && ts.store != nil {
+			// persisting is optional
+			_ = ts.store.Save(ts.token)
+		}
 	}
 	return ts.token, err
 }
 
 // mergeToken updates a token while preventing wiping the refresh token
 func (ts *TokenSource) mergeToken(t *oauth2.Token) error {
+	if ts.token == nil {
+		ts.token = t
+		return nil
+	}
 	return mergo.Merge(ts.token, t, mergo.WithOverride)
 }
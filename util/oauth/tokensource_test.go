@@ -1,7 +1,9 @@
 package oauth
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	"golang.org/x/oauth2"
 )
@@ -29,3 +31,85 @@ func TestMerge(t *testing.T) {
 		t.Error("unexpected refresh token", ts.token)
 	}
 }
+
+type memoryStore struct {
+	token *oauth2.Token
+}
+
+func (s *memoryStore) Load(res any) error {
+	if s.token == nil {
+		return errors.New("not found")
+	}
+	*res.(*oauth2.Token) = *s.token
+	return nil
+}
+
+func (s *memoryStore) Save(val any) error {
+	t := *val.(*oauth2.Token)
+	s.token = &t
+	return nil
+}
+
+type refresher struct {
+	logins, refreshes int
+	fail              bool
+}
+
+func (r *refresher) RefreshToken(token *oauth2.Token) (*oauth2.Token, error) {
+	if token == nil {
+		r.logins++
+		return &oauth2.Token{AccessToken: "login", RefreshToken: "refresh", Expiry: time.Now().Add(time.Hour)}, nil
+	}
+
+	r.refreshes++
+	if r.fail {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	return &oauth2.Token{AccessToken: "refreshed", Expiry: time.Now().Add(time.Hour)}, nil
+}
+
+func TestPersistentTokenSource(t *testing.T) {
+	store := new(memoryStore)
+
+	// login without stored token
+	r := new(refresher)
+	ts := PersistentRefreshTokenSource(nil, r, store)
+
+	token, err := ts.Token()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.logins != 1 || token.AccessToken != "login" || store.token.AccessToken != "login" {
+		t.Error("unexpected login", r, token, store.token)
+	}
+
+	// refresh stored token
+	store.token.Expiry = time.Now()
+
+	r = new(refresher)
+	ts = PersistentRefreshTokenSource(nil, r, store)
+
+	if token, err = ts.Token(); err != nil {
+		t.Fatal(err)
+	}
+	if r.logins != 0 || r.refreshes != 1 || token.AccessToken != "refreshed" || token.RefreshToken != "refresh" {
+		t.Error("unexpected refresh", r, token)
+	}
+	if store.token.AccessToken != "refreshed" || store.token.RefreshToken != "refresh" {
+		t.Error("refreshed token not saved", store.token)
+	}
+
+	// login if refresh fails
+	store.token.Expiry = time.Now()
+
+	r = &refresher{fail: true}
+	ts = PersistentRefreshTokenSource(nil, r, store)
+
+	if token, err = ts.Token(); err != nil {
+		t.Fatal(err)
+	}
+	if r.logins != 1 || r.refreshes != 1 || token.AccessToken != "login" {
+		t.Error("unexpected login", r, token)
+	}
+}
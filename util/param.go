@@ -5,15 +5,19 @@ import "strconv"
 // Param is the broadcast channel data type
 type Param struct {
 	LoadPoint *int
+	Consumer  *int
 	Key       string
 	Val       interface{}
 }
 
-// UniqueID returns unique identifier for parameter LoadPoint/Key combination
+// UniqueID returns unique identifier for parameter LoadPoint/Consumer/Key combination
 func (p Param) UniqueID() string {
 	key := p.Key
 	if p.LoadPoint != nil {
 		key = strconv.Itoa(*p.LoadPoint) + "." + key
 	}
+	if p.Consumer != nil {
+		key = "c" + strconv.Itoa(*p.Consumer) + "." + key
+	}
 	return key
 }
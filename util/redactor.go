@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"net/url"
 	"os"
+	"strings"
 	"sync"
 )
 
@@ -47,3 +48,62 @@ func (l *Redactor) Write(p []byte) (n int, err error) {
 func RedactDefaultHook(s string) []string {
 	return []string{s, url.QueryEscape(s)}
 }
+
+// SecretKeys are configuration keys whose values must not be revealed
+var SecretKeys = []string{
+	"mac",                   // infrastructure
+	"sponsortoken", "plant", // global settings
+	"user", "password", "pin", // users
+	"token", "access", "refresh", // tokens
+	"ain", "secret", "serial", "deviceid", "machineid", // devices
+	"vin", // vehicles
+}
+
+// isSecretKey checks if the configuration key is a secret key
+func isSecretKey(key string) bool {
+	for _, k := range SecretKeys {
+		if strings.EqualFold(key, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactConfig returns a copy of the configuration with secret values replaced by RedactReplacement
+func RedactConfig(conf map[string]interface{}) map[string]interface{} {
+	res := make(map[string]interface{}, len(conf))
+
+	for k, v := range conf {
+		switch val := v.(type) {
+		case map[string]interface{}:
+			res[k] = RedactConfig(val)
+		default:
+			if isSecretKey(k) && v != nil && v != "" {
+				v = RedactReplacement
+			}
+			res[k] = v
+		}
+	}
+
+	return res
+}
+
+// UnredactConfig returns a copy of the configuration with RedactReplacement values restored from the previous configuration
+func UnredactConfig(conf, previous map[string]interface{}) map[string]interface{} {
+	res := make(map[string]interface{}, len(conf))
+
+	for k, v := range conf {
+		switch val := v.(type) {
+		case map[string]interface{}:
+			prev, _ := previous[k].(map[string]interface{})
+			res[k] = UnredactConfig(val, prev)
+		default:
+			if prev, ok := previous[k]; ok && isSecretKey(k) && v == RedactReplacement {
+				v = prev
+			}
+			res[k] = v
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,43 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactConfig(t *testing.T) {
+	conf := map[string]interface{}{
+		"uri":      "http://foo",
+		"Password": "geheim",
+		"user":     "",
+		"auth": map[string]interface{}{
+			"token": "secret",
+		},
+	}
+
+	res := RedactConfig(conf)
+	assert.Equal(t, map[string]interface{}{
+		"uri":      "http://foo",
+		"Password": RedactReplacement,
+		"user":     "",
+		"auth": map[string]interface{}{
+			"token": RedactReplacement,
+		},
+	}, res)
+
+	// original unchanged
+	assert.Equal(t, "geheim", conf["Password"])
+
+	// redacted values are restored, changed values kept
+	res["uri"] = "http://bar"
+	res["user"] = "admin"
+	assert.Equal(t, map[string]interface{}{
+		"uri":      "http://bar",
+		"Password": "geheim",
+		"user":     "admin",
+		"auth": map[string]interface{}{
+			"token": "secret",
+		},
+	}, UnredactConfig(res, conf))
+}
@@ -0,0 +1,177 @@
+package request
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/util/transport"
+)
+
+// Budget tracks the daily requests of a provider api against its quota.
+// Requests are counted by the round tripper for the budget's hosts or by the budget's transport.
+type Budget struct {
+	mu    sync.Mutex
+	clock clock.Clock
+	name  string
+	limit int
+	day   time.Time
+	used  int
+}
+
+// Budgeter provides the request budget of a vehicle api
+type Budgeter interface {
+	Budget() *Budget
+}
+
+var (
+	budgetMu    sync.Mutex
+	budgets     = make(map[string]*Budget) // by host
+	budgetNames = make(map[string]*Budget) // by name
+)
+
+// NewBudget creates the named request budget for the given hosts or returns the existing one.
+// A limit of 0 tracks requests without quota, otherwise the limit replaces the existing budget's limit.
+// Budgets of apis with per-account quota must be named by account and count requests using Transport.
+func NewBudget(name string, limit int, hosts ...string) *Budget {
+	budgetMu.Lock()
+	defer budgetMu.Unlock()
+
+	b, ok := budgetNames[name]
+	if !ok {
+		b = &Budget{
+			clock: clock.New(),
+			name:  name,
+		}
+		budgetNames[name] = b
+	}
+
+	if limit > 0 || !ok {
+		b.mu.Lock()
+		b.limit = limit
+		b.mu.Unlock()
+	}
+
+	b.register(hosts)
+
+	return b
+}
+
+// Transport returns a round tripper counting the requests made using base
+func (b *Budget) Transport(base http.RoundTripper) http.RoundTripper {
+	return &transport.Decorator{
+		Decorator: func(*http.Request) error {
+			b.Add()
+			return nil
+		},
+		Base: base,
+	}
+}
+
+func (b *Budget) register(hosts []string) {
+	for _, host := range hosts {
+		budgets[host] = b
+	}
+}
+
+// budgetForHost returns the budget registered for host
+func budgetForHost(host string) *Budget {
+	budgetMu.Lock()
+	defer budgetMu.Unlock()
+	return budgets[host]
+}
+
+// Budgets returns the registered budgets sorted by name
+func Budgets() []*Budget {
+	budgetMu.Lock()
+	defer budgetMu.Unlock()
+
+	res := make([]*Budget, 0, len(budgetNames))
+	for _, b := range budgetNames {
+		res = append(res, b)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].name < res[j].name
+	})
+
+	return res
+}
+
+// Name returns the budget's name
+func (b *Budget) Name() string {
+	return b.name
+}
+
+// rollover resets the used requests at the start of the day, requires lock
+func (b *Budget) rollover() time.Time {
+	now := b.clock.Now()
+
+	if day := startOfDay(now); !day.Equal(b.day) {
+		b.day = day
+		b.used = 0
+	}
+
+	return now
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// Add counts a request
+func (b *Budget) Add() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.rollover()
+	b.used++
+}
+
+// Used returns the requests used today and the daily limit
+func (b *Budget) Used() (int, int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.rollover()
+	return b.used, b.limit
+}
+
+// Exhausted returns true if the daily limit has been reached
+func (b *Budget) Exhausted() bool {
+	used, limit := b.Used()
+	return limit > 0 && used >= limit
+}
+
+// Pressure returns the ratio of requests used to requests available by the elapsed time of day.
+// Values above 1 indicate the budget is used faster than it lasts for the day.
+func (b *Budget) Pressure() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.limit == 0 {
+		return 0
+	}
+
+	now := b.rollover()
+
+	// allow an hour's share at the start of the day
+	elapsed := now.Sub(b.day)
+	if elapsed < time.Hour {
+		elapsed = time.Hour
+	}
+
+	available := float64(b.limit) * elapsed.Hours() / 24
+	return float64(b.used) / available
+}
+
+// Reset returns the time the budget is reset
+func (b *Budget) Reset() time.Time {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return startOfDay(b.rollover()).AddDate(0, 0, 1)
+}
@@ -0,0 +1,99 @@
+package request
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBudget(t *testing.T) {
+	clck := clock.NewMock()
+	clck.Set(time.Date(2022, 10, 1, 0, 0, 0, 0, time.Local))
+
+	b := NewBudget("test", 24, "test.example.com")
+	b.clock = clck
+
+	assert.Same(t, b, NewBudget("test", 24))
+	assert.Same(t, b, budgetForHost("test.example.com"))
+
+	// an hour's share
+	b.Add()
+	assert.Equal(t, 1.0, b.Pressure())
+
+	clck.Add(12 * time.Hour)
+	for i := 0; i < 11; i++ {
+		b.Add()
+	}
+	assert.Equal(t, 1.0, b.Pressure())
+	assert.False(t, b.Exhausted())
+
+	for i := 0; i < 12; i++ {
+		b.Add()
+	}
+	assert.Equal(t, 2.0, b.Pressure())
+	assert.True(t, b.Exhausted())
+	assert.Equal(t, time.Date(2022, 10, 2, 0, 0, 0, 0, time.Local), b.Reset())
+
+	// next day
+	clck.Add(12 * time.Hour)
+	used, limit := b.Used()
+	assert.Equal(t, 0, used)
+	assert.Equal(t, 24, limit)
+}
+
+func TestBudgetRoundTrip(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	b := NewBudget("roundtrip", 0, u.Hostname())
+
+	client := NewHelper(util.NewLogger("foo"))
+	_, err := client.GetBody(srv.URL)
+	assert.NoError(t, err)
+
+	used, _ := b.Used()
+	assert.Equal(t, 1, used)
+	assert.Equal(t, 0.0, b.Pressure())
+}
+
+func TestBudgetLimit(t *testing.T) {
+	b := NewBudget("limit", 10)
+
+	// configured limit applies to existing budget
+	assert.Same(t, b, NewBudget("limit", 20))
+	_, limit := b.Used()
+	assert.Equal(t, 20, limit)
+
+	// unlimited budget keeps the limit
+	NewBudget("limit", 0)
+	_, limit = b.Used()
+	assert.Equal(t, 20, limit)
+}
+
+func TestBudgetTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	// accounts sharing the api host
+	a := NewBudget("transport:a", 10)
+	b := NewBudget("transport:b", 10)
+
+	client := NewHelper(util.NewLogger("foo"))
+	client.Client.Transport = a.Transport(client.Client.Transport)
+
+	_, err := client.GetBody(srv.URL)
+	assert.NoError(t, err)
+
+	used, _ := a.Used()
+	assert.Equal(t, 1, used)
+
+	used, _ = b.Used()
+	assert.Equal(t, 0, used)
+}
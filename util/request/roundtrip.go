@@ -118,6 +118,10 @@ func (r *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
 		}
 	}
 
+	if b := budgetForHost(req.URL.Hostname()); b != nil {
+		b.Add()
+	}
+
 	startTime := time.Now()
 	resp, err := r.base.RoundTrip(req)
 
@@ -0,0 +1,196 @@
+package util
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// SecretKeyEnv is the environment variable containing the key for encrypted secrets
+const SecretKeyEnv = "EVCC_SECRET_KEY"
+
+// secretRef matches ${env:NAME}, ${file:/path} and ${enc:ciphertext} references
+var secretRef = regexp.MustCompile(`\$\{(env|file|enc):([^}]+)\}`)
+
+// IsSecretRef returns true if the string only consists of secret references
+func IsSecretRef(s string) bool {
+	s = strings.TrimSpace(s)
+	return s != "" && secretRef.ReplaceAllString(s, "") == ""
+}
+
+// ResolveSecrets replaces all secret references with the referenced values
+func ResolveSecrets(s string) (string, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+
+	var err error
+	res := secretRef.ReplaceAllStringFunc(s, func(ref string) string {
+		match := secretRef.FindStringSubmatch(ref)
+
+		val, e := resolveSecret(match[1], match[2])
+		if e != nil && err == nil {
+			err = fmt.Errorf("cannot resolve %s: %w", ref, e)
+		}
+
+		return val
+	})
+
+	return res, err
+}
+
+func resolveSecret(kind, name string) (string, error) {
+	switch kind {
+	case "env":
+		val, ok := os.LookupEnv(name)
+		if !ok {
+			return "", errors.New("not set")
+		}
+		return val, nil
+
+	case "file":
+		b, err := os.ReadFile(name)
+		return strings.TrimRight(string(b), "\r\n"), err
+
+	case "enc":
+		return DecryptSecret(name)
+
+	default:
+		return "", fmt.Errorf("invalid type: %s", kind)
+	}
+}
+
+// ContainsSecretRef returns true if any string value of the possibly nested configuration contains a secret reference
+func ContainsSecretRef(v interface{}) bool {
+	switch v := v.(type) {
+	case string:
+		return secretRef.MatchString(v)
+	case map[string]interface{}:
+		for _, val := range v {
+			if ContainsSecretRef(val) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, val := range v {
+			if ContainsSecretRef(val) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// resolveSecretsDeep replaces the secret references of all string values of the possibly nested configuration
+func resolveSecretsDeep(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case string:
+		return ResolveSecrets(v)
+
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			var err error
+			if res[k], err = resolveSecretsDeep(val); err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for i, val := range v {
+			var err error
+			if res[i], err = resolveSecretsDeep(val); err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+
+	default:
+		return v, nil
+	}
+}
+
+// SecretHookFunc returns a decode hook resolving secret references in string values.
+// Maps and slices are only resolved as a whole when decoded into interfaces since their values are not visited
+// by hooks. Otherwise the decoder visits each value, ensuring that resolved values are never resolved again.
+// The hook must only be used for the configuration file as its references are trusted.
+func SecretHookFunc() mapstructure.DecodeHookFuncKind {
+	return func(f reflect.Kind, t reflect.Kind, data interface{}) (interface{}, error) {
+		switch {
+		case f == reflect.String:
+			return resolveSecretsDeep(data)
+		case (f == reflect.Map || f == reflect.Slice) && t == reflect.Interface:
+			return resolveSecretsDeep(data)
+		default:
+			return data, nil
+		}
+	}
+}
+
+func secretCipher() (cipher.AEAD, error) {
+	key := os.Getenv(SecretKeyEnv)
+	if key == "" {
+		return nil, fmt.Errorf("missing %s", SecretKeyEnv)
+	}
+
+	hash := sha256.Sum256([]byte(key))
+
+	block, err := aes.NewCipher(hash[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// EncryptSecret encrypts a value using the secret key and returns the ${enc:} reference
+func EncryptSecret(val string) (string, error) {
+	gcm, err := secretCipher()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	b := gcm.Seal(nonce, nonce, []byte(val), nil)
+
+	return fmt.Sprintf("${enc:%s}", base64.RawURLEncoding.EncodeToString(b)), nil
+}
+
+// DecryptSecret decrypts an encrypted value using the secret key
+func DecryptSecret(enc string) (string, error) {
+	gcm, err := secretCipher()
+	if err != nil {
+		return "", err
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(enc)
+	if err != nil {
+		return "", err
+	}
+
+	if len(b) < gcm.NonceSize() {
+		return "", errors.New("invalid ciphertext")
+	}
+
+	nonce, ciphertext := b[:gcm.NonceSize()], b[gcm.NonceSize():]
+	res, err := gcm.Open(nil, nonce, ciphertext, nil)
+
+	return string(res), err
+}
@@ -0,0 +1,56 @@
+package util
+
+import "strings"
+
+// levenshtein returns the edit distance between a and b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i
+
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev = cur
+	}
+
+	return prev[len(rb)]
+}
+
+func min(a int, b ...int) int {
+	for _, v := range b {
+		if v < a {
+			a = v
+		}
+	}
+	return a
+}
+
+// Suggest returns the candidate closest to s (case-insensitive) or empty string if none is similar enough
+func Suggest(s string, candidates []string) string {
+	var (
+		res  string
+		best = len(s)/3 + 1 // allow roughly one typo per three characters
+	)
+
+	for _, c := range candidates {
+		if d := levenshtein(strings.ToLower(s), strings.ToLower(c)); d <= best && (res == "" || d < best) {
+			res, best = c, d
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,16 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuggest(t *testing.T) {
+	candidates := []string{"loadpoints", "meters", "chargers", "vehicles"}
+
+	assert.Equal(t, "loadpoints", Suggest("loadpoint", candidates))
+	assert.Equal(t, "chargers", Suggest("Chargres", candidates))
+	assert.Equal(t, "meters", Suggest("meter", candidates))
+	assert.Equal(t, "", Suggest("tariffs", candidates))
+}
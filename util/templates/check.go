@@ -0,0 +1,228 @@
+package templates
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"golang.org/x/exp/slices"
+)
+
+// ParamError is a configuration error of a single template parameter
+type ParamError struct {
+	Template string
+	Param    string
+	Err      error
+}
+
+func (e *ParamError) Error() string {
+	return fmt.Sprintf("%s: %s: %v", e.Template, e.Param, e.Err)
+}
+
+func (e *ParamError) Unwrap() error {
+	return e.Err
+}
+
+// Check validates configuration values against the template's parameters and combines all errors
+func (t *Template) Check(other map[string]interface{}) error {
+	errs := t.CheckValues(other)
+	if len(errs) == 0 {
+		return nil
+	}
+
+	msgs := make([]string, 0, len(errs))
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+
+	return errors.New(strings.Join(msgs, "; "))
+}
+
+// CheckValues validates configuration values against the template's parameters without rendering.
+// It returns an error for each unknown, invalid or missing parameter.
+func (t *Template) CheckValues(other map[string]interface{}) []error {
+	var res []error
+
+	fail := func(param string, format string, a ...any) {
+		res = append(res, &ParamError{Template: t.Template, Param: param, Err: fmt.Errorf(format, a...)})
+	}
+
+	names := make([]string, 0, len(t.Params))
+	for _, p := range t.Params {
+		if !p.Deprecated {
+			names = append(names, p.Name)
+		}
+	}
+
+	provided := make(map[string]interface{})
+
+	for key, val := range other {
+		provided[strings.ToLower(key)] = val
+
+		i, p := t.ParamByName(key)
+		if i == -1 {
+			if !slices.Contains(predefinedTemplateProperties, strings.ToLower(key)) {
+				if s := util.Suggest(key, names); s != "" {
+					fail(key, "unknown parameter, did you mean '%s'?", s)
+				} else {
+					fail(key, "unknown parameter")
+				}
+			}
+			continue
+		}
+
+		if isEmpty(val) {
+			continue
+		}
+
+		if err := p.checkValue(val); err != nil {
+			fail(p.Name, "%v", err)
+		}
+	}
+
+	for _, p := range t.Params {
+		if !p.Required || p.Deprecated || p.Default != "" || p.Name == ParamModbus {
+			continue
+		}
+
+		if _, ok := provided[strings.ToLower(p.Name)]; ok {
+			continue
+		}
+
+		deps, ok := t.dependenciesSatisfied(p, provided)
+		switch {
+		case !ok:
+			// parameter not applicable
+		case deps != "":
+			fail(p.Name, "missing required parameter (depends on %s)", deps)
+		default:
+			fail(p.Name, "missing required parameter")
+		}
+	}
+
+	return res
+}
+
+// isEmpty returns true for empty values which are treated as not configured
+func isEmpty(val interface{}) bool {
+	return val == nil || val == ""
+}
+
+// paramValue returns the provided or default value of a parameter
+func (t *Template) paramValue(name string, provided map[string]interface{}) string {
+	if val, ok := provided[strings.ToLower(name)]; ok && !isEmpty(val) {
+		return fmt.Sprintf("%v", val)
+	}
+
+	if i, p := t.ParamByName(name); i >= 0 {
+		return p.Default
+	}
+
+	return ""
+}
+
+// dependenciesSatisfied checks if all dependencies of a parameter are satisfied and
+// returns the names of the parameters it depends on
+func (t *Template) dependenciesSatisfied(p Param, provided map[string]interface{}) (string, bool) {
+	var names []string
+
+	for _, dep := range p.Dependencies {
+		val := t.paramValue(dep.Name, provided)
+
+		switch dep.Check {
+		case DependencyCheckEmpty:
+			if val != "" {
+				return "", false
+			}
+		case DependencyCheckNotEmpty:
+			if val == "" {
+				return "", false
+			}
+		case DependencyCheckEqual:
+			if val != dep.Value {
+				return "", false
+			}
+		}
+
+		names = append(names, dep.Name)
+	}
+
+	return strings.Join(names, ", "), true
+}
+
+// checkValue validates a single parameter value against the parameter's value type and valid values
+func (p *Param) checkValue(val interface{}) error {
+	s := fmt.Sprintf("%v", val)
+
+	switch p.ValueType {
+	case ParamValueTypeNumber:
+		i, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("invalid number: %v", val)
+		}
+		if err := p.checkRange(float64(i)); err != nil {
+			return err
+		}
+
+	case ParamValueTypeFloat:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("invalid float: %v", val)
+		}
+		if err := p.checkRange(f); err != nil {
+			return err
+		}
+
+	case ParamValueTypeBool:
+		if _, err := strconv.ParseBool(s); err != nil {
+			return fmt.Errorf("invalid bool: %v", val)
+		}
+
+	case ParamValueTypeDuration:
+		if _, err := time.ParseDuration(s); err != nil {
+			return fmt.Errorf("invalid duration: %v", val)
+		}
+
+	case ParamValueTypeChargeModes:
+		modes := []string{string(api.ModeOff), string(api.ModeNow), string(api.ModeMinPV), string(api.ModePV)}
+		if !slices.Contains(modes, s) {
+			return fmt.Errorf("invalid charge mode: %v, must be one of %s", val, strings.Join(modes, ", "))
+		}
+
+	case ParamValueTypeStringList:
+		if _, ok := val.([]interface{}); !ok {
+			if _, ok := val.([]string); !ok {
+				return fmt.Errorf("invalid list: %v", val)
+			}
+		}
+		return nil
+	}
+
+	valid := p.ValidValues
+	if p.Name == ParamUsage {
+		valid = p.Choice
+	}
+
+	if len(valid) > 0 && !slices.Contains(valid, s) {
+		return fmt.Errorf("invalid value: %v, must be one of %s", val, strings.Join(valid, ", "))
+	}
+
+	return nil
+}
+
+// checkRange validates a numeric value against the parameter's minimum and maximum
+func (p *Param) checkRange(f float64) error {
+	if p.Min != nil && f < *p.Min {
+		return fmt.Errorf("value %v below minimum %v", f, *p.Min)
+	}
+
+	if p.Max != nil && f > *p.Max {
+		return fmt.Errorf("value %v above maximum %v", f, *p.Max)
+	}
+
+	return nil
+}
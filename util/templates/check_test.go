@@ -0,0 +1,62 @@
+package templates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTemplate() Template {
+	min, max := 6.0, 32.0
+
+	return Template{
+		TemplateDefinition: TemplateDefinition{
+			Template: "test",
+			Params: []Param{
+				{Name: ParamUsage, Choice: []string{UsageChoiceGrid, UsageChoicePV}},
+				{Name: "host", Required: true},
+				{Name: "current", ValueType: ParamValueTypeNumber, Min: &min, Max: &max},
+				{Name: "user"},
+				{Name: "password", Required: true, Dependencies: []ParamDependency{
+					{Name: "user", Check: DependencyCheckNotEmpty},
+				}},
+			},
+			Render: "type: custom\nhost: {{ .host }}",
+		},
+	}
+}
+
+func TestCheckValues(t *testing.T) {
+	tmpl := testTemplate()
+
+	assert.Empty(t, tmpl.CheckValues(map[string]interface{}{"usage": "grid", "host": "foo", "current": 16}))
+
+	errs := tmpl.CheckValues(map[string]interface{}{"usage": "gird", "host": "foo", "current": 40})
+	require.Len(t, errs, 2)
+	assert.Contains(t, errs[0].Error()+errs[1].Error(), "usage: invalid value: gird")
+	assert.Contains(t, errs[0].Error()+errs[1].Error(), "current: value 40 above maximum 32")
+}
+
+func TestCheckDependencies(t *testing.T) {
+	tmpl := testTemplate()
+
+	// password only required with user
+	assert.Empty(t, tmpl.CheckValues(map[string]interface{}{"host": "foo", "user": ""}))
+
+	errs := tmpl.CheckValues(map[string]interface{}{"host": "foo", "user": "admin"})
+	require.Len(t, errs, 1)
+	assert.Equal(t, "test: password: missing required parameter (depends on user)", errs[0].Error())
+}
+
+func TestRenderResultValidation(t *testing.T) {
+	tmpl := testTemplate()
+
+	_, _, err := tmpl.RenderResult(TemplateRenderModeInstance, map[string]interface{}{"usage": "gird", "host": "foo"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "test: usage: invalid value: gird")
+
+	b, _, err := tmpl.RenderResult(TemplateRenderModeInstance, map[string]interface{}{"usage": "grid", "host": "foo"})
+	require.NoError(t, err)
+	assert.Equal(t, "type: custom\nhost: foo", string(b))
+}
@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"fmt"
 	"io/fs"
+	"os"
 	"path"
+	"path/filepath"
 
 	"github.com/evcc-io/evcc/templates/definition"
 	"golang.org/x/exp/slices"
@@ -66,33 +68,103 @@ func loadTemplates(class Class) {
 		return
 	}
 
-	err := fs.WalkDir(definition.YamlTemplates, ".", func(filepath string, d fs.DirEntry, err error) error {
+	err := walkTemplates(definition.YamlTemplates, func(class Class, _ string, tmpl Template) error {
+		templates[class] = append(templates[class], tmpl)
+		return nil
+	})
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+// walkTemplates parses all template definitions of the class subdirectories of fsys
+func walkTemplates(fsys fs.FS, fn func(class Class, file string, tmpl Template) error) error {
+	return fs.WalkDir(fsys, ".", func(file string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
-		if d.IsDir() {
+		if d.IsDir() || path.Ext(file) != ".yaml" {
+			return nil
+		}
+
+		class := Class(path.Dir(file))
+		if !slices.Contains([]Class{Charger, Meter, Vehicle}, class) {
 			return nil
 		}
 
-		b, err := fs.ReadFile(definition.YamlTemplates, filepath)
+		b, err := fs.ReadFile(fsys, file)
 		if err != nil {
 			return err
 		}
 
 		tmpl, err := FromBytes(b)
 		if err != nil {
-			return fmt.Errorf("processing template '%s' failed: %w", filepath, err)
+			return fmt.Errorf("processing template '%s' failed: %w", file, err)
 		}
 
-		path := Class(path.Dir(filepath))
-		templates[path] = append(templates[path], tmpl)
+		return fn(class, file, tmpl)
+	})
+}
+
+// Conflict is a user template replacing an embedded template of the same name
+type Conflict struct {
+	Class    Class
+	Template string
+	File     string
+}
+
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s template '%s' (%s) overrides built-in template", c.Class, c.Template, c.File)
+}
+
+// LoadDirectory adds the user templates from the charger, meter and vehicle subdirectories of dir.
+// User templates replace embedded templates of the same or covered name, these conflicts are returned.
+func LoadDirectory(dir string) ([]Conflict, error) {
+	var res []Conflict
+	loaded := make(map[Class][]string)
+
+	err := walkTemplates(os.DirFS(dir), func(class Class, file string, tmpl Template) error {
+		file = filepath.Join(dir, file)
+
+		names := append([]string{tmpl.Template}, tmpl.Covers...)
+		for _, name := range names {
+			if slices.Contains(loaded[class], name) {
+				return fmt.Errorf("duplicate %s template '%s' (%s)", class, name, file)
+			}
+		}
+		loaded[class] = append(loaded[class], names...)
+
+		// replace all embedded templates sharing a template or covered name
+		idx := -1
+		for i := 0; i < len(templates[class]); i++ {
+			t := templates[class][i]
+
+			if !slices.ContainsFunc(append([]string{t.Template}, t.Covers...), func(name string) bool {
+				return slices.Contains(names, name)
+			}) {
+				continue
+			}
+
+			res = append(res, Conflict{Class: class, Template: t.Template, File: file})
+
+			if idx < 0 {
+				idx = i
+				templates[class][i] = tmpl
+			} else {
+				templates[class] = slices.Delete(templates[class], i, i+1)
+				i--
+			}
+		}
+
+		if idx < 0 {
+			templates[class] = append(templates[class], tmpl)
+		}
 
 		return nil
 	})
 
-	if err != nil {
-		panic(err)
-	}
+	return res, err
 }
 
 func ByClass(class Class) []Template {
@@ -0,0 +1,113 @@
+package templates
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+const userTemplate = `template: %s
+products:
+  - description:
+      generic: Custom Meter
+params:
+  - name: usage
+    choice: ["grid"]
+  - name: host
+render: |
+  type: custom
+  power:
+    source: http
+    uri: http://{{ .host }}/power
+`
+
+func writeTemplate(t *testing.T, dir, class, name string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, class), 0o755))
+	b := []byte(fmt.Sprintf(userTemplate, name))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, class, name+".yaml"), b, 0o644))
+}
+
+// restoreTemplates restores the template registry after the test
+func restoreTemplates(t *testing.T) {
+	saved := maps.Clone(templates)
+	for k, v := range saved {
+		saved[k] = slices.Clone(v)
+	}
+	t.Cleanup(func() { templates = saved })
+}
+
+func TestLoadDirectory(t *testing.T) {
+	restoreTemplates(t)
+
+	count := len(ByClass(Meter))
+
+	dir := t.TempDir()
+	writeTemplate(t, dir, "meter", "custom-meter")
+	writeTemplate(t, dir, "meter", "tasmota")
+
+	conflicts, err := LoadDirectory(dir)
+	require.NoError(t, err)
+
+	require.Len(t, conflicts, 1)
+	assert.Equal(t, Meter, conflicts[0].Class)
+	assert.Equal(t, "tasmota", conflicts[0].Template)
+
+	assert.Len(t, ByClass(Meter), count+1)
+
+	tmpl, err := ByName(Meter, "tasmota")
+	require.NoError(t, err)
+	assert.Equal(t, "Custom Meter", tmpl.Products[0].Description.Generic)
+
+	_, err = ByName(Meter, "custom-meter")
+	assert.NoError(t, err)
+}
+
+func TestLoadDirectoryDuplicate(t *testing.T) {
+	restoreTemplates(t)
+
+	dir := t.TempDir()
+	writeTemplate(t, dir, "meter", "custom-meter")
+	b := []byte(fmt.Sprintf(userTemplate, "custom-meter"))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "meter", "copy.yaml"), b, 0o644))
+
+	_, err := LoadDirectory(dir)
+	assert.ErrorContains(t, err, "duplicate meter template 'custom-meter'")
+}
+
+func TestLoadDirectoryCovers(t *testing.T) {
+	restoreTemplates(t)
+
+	count := len(ByClass(Meter))
+
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "meter"), 0o755))
+
+	// user template covering embedded templates by name and by covered name
+	b := []byte(strings.Replace(fmt.Sprintf(userTemplate, "custom-meter"), "\n", "\ncovers:\n  - tasmota\n  - huawei-sun2000-8ktl\n", 1))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "meter", "custom-meter.yaml"), b, 0o644))
+
+	conflicts, err := LoadDirectory(dir)
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{"tasmota", "huawei-sun2000-rs485"}, lo.Map(conflicts, func(c Conflict, _ int) string {
+		return c.Template
+	}))
+
+	assert.Len(t, ByClass(Meter), count-1)
+
+	for _, name := range []string{"custom-meter", "tasmota", "huawei-sun2000-8ktl"} {
+		tmpl, err := ByName(Meter, name)
+		require.NoError(t, err)
+		assert.Equal(t, "custom-meter", tmpl.Template)
+	}
+}
@@ -78,6 +78,20 @@ func (t *Template) Validate() error {
 		if p.ValueType != "" && !slices.Contains(ValidParamValueTypes, p.ValueType) {
 			return fmt.Errorf("invalid value type '%s' in template %s", p.ValueType, t.Template)
 		}
+
+		if p.Min != nil && p.Max != nil && *p.Min > *p.Max {
+			return fmt.Errorf("invalid range for param '%s' in template %s", p.Name, t.Template)
+		}
+
+		for _, d := range p.Dependencies {
+			if !slices.Contains(ValidDependencies, d.Check) {
+				return fmt.Errorf("invalid dependency check '%s' for param '%s' in template %s", d.Check, p.Name, t.Template)
+			}
+
+			if i, _ := t.ParamByName(d.Name); i == -1 {
+				return fmt.Errorf("invalid dependency '%s' for param '%s' in template %s", d.Name, p.Name, t.Template)
+			}
+		}
 	}
 
 	return nil
@@ -280,6 +294,13 @@ func (t *Template) RenderProxyWithValues(values map[string]interface{}, lang str
 
 // RenderResult renders the result template to instantiate the proxy
 func (t *Template) RenderResult(renderMode string, other map[string]interface{}) ([]byte, map[string]interface{}, error) {
+	// validate user values before rendering
+	if renderMode == TemplateRenderModeInstance {
+		if err := t.Check(other); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	values := t.Defaults(renderMode)
 	if err := util.DecodeOther(other, &values); err != nil {
 		return nil, values, err
@@ -192,6 +192,10 @@ type Param struct {
 	AllInOne      bool         // defines if the defined usages can all be present in a single device
 	Requirements  Requirements // requirements for this param to be usable, only supported via ValueType "bool"
 
+	Dependencies []ParamDependency // checks on other params that must be satisfied for this param to be applicable
+	Min          *float64          // minimum value for ValueType "number" and "float"
+	Max          *float64          // maximum value for ValueType "number" and "float"
+
 	Baudrate int    // device specific default for modbus RS485 baudrate
 	Comset   string // device specific default for modbus RS485 comset
 	Port     int    // device specific default for modbus TCPIP port
@@ -251,6 +255,25 @@ func (p *Param) OverwriteProperties(withParam Param) {
 	if reflect.DeepEqual(p.Requirements, Requirements{}) {
 		p.Requirements = withParam.Requirements
 	}
+
+	if p.Dependencies == nil && withParam.Dependencies != nil {
+		p.Dependencies = withParam.Dependencies
+	}
+
+	if p.Min == nil && withParam.Min != nil {
+		p.Min = withParam.Min
+	}
+
+	if p.Max == nil && withParam.Max != nil {
+		p.Max = withParam.Max
+	}
+}
+
+// ParamDependency is a check on another param's value
+type ParamDependency struct {
+	Name  string // name of the referenced param
+	Check string // one of ValidDependencies
+	Value string // value for DependencyCheckEqual
 }
 
 // Product contains naming information about a product a template supports
@@ -0,0 +1,225 @@
+package vehicle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/provider"
+	"github.com/evcc-io/evcc/provider/mqtt"
+	"github.com/evcc-io/evcc/util"
+)
+
+// Abrp is an api.Vehicle implementation consuming A Better Routeplanner (ABRP) telemetry
+// as published by OBD dongles like WiCAN or evDash via mqtt or http
+// https://documenter.getpostman.com/view/7396339/SWTK5a8w
+type Abrp struct {
+	*embed
+	dataG func() (abrpTelemetry, error)
+}
+
+// abrpBool accepts numeric and boolean json values
+type abrpBool bool
+
+func (b *abrpBool) UnmarshalJSON(data []byte) error {
+	switch strings.Trim(string(data), `"`) {
+	case "1", "true":
+		*b = true
+	case "0", "false", "null", "":
+		*b = false
+	default:
+		return errors.New("invalid bool: " + string(data))
+	}
+	return nil
+}
+
+type abrpTelemetry struct {
+	Utc             int64
+	SoC             *float64 `json:"soc"`
+	EstBatteryRange *float64 `json:"est_battery_range"`
+	Odometer        *float64
+	Lat, Lon        *float64
+	IsCharging      abrpBool  `json:"is_charging"`
+	IsDcfc          abrpBool  `json:"is_dcfc"`
+	IsParked        abrpBool  `json:"is_parked"`
+	IsPlugged       *abrpBool `json:"is_plugged"`
+}
+
+// status derives the charge status from charging and plug state.
+// DC charging happens away from the loadpoint.
+func (t abrpTelemetry) status() (api.ChargeStatus, error) {
+	switch {
+	case bool(t.IsCharging) && bool(t.IsDcfc):
+		return api.StatusA, nil
+	case bool(t.IsCharging):
+		return api.StatusC, nil
+	case t.IsPlugged == nil:
+		return api.StatusNone, api.ErrNotAvailable
+	case bool(*t.IsPlugged):
+		return api.StatusB, nil
+	default:
+		return api.StatusA, nil
+	}
+}
+
+// outdated returns an error if the telemetry timestamp is older than timeout
+func (t abrpTelemetry) outdated(now time.Time, timeout time.Duration) error {
+	if timeout == 0 || t.Utc == 0 {
+		return nil
+	}
+
+	if age := now.Sub(time.Unix(t.Utc, 0)); age > timeout {
+		return fmt.Errorf("outdated: %v", age.Truncate(time.Second))
+	}
+
+	return nil
+}
+
+// parseAbrp decodes plain or tlm-wrapped telemetry
+func parseAbrp(s string) (abrpTelemetry, error) {
+	var res struct {
+		abrpTelemetry
+		Tlm *abrpTelemetry
+	}
+
+	if err := json.Unmarshal([]byte(s), &res); err != nil {
+		return abrpTelemetry{}, err
+	}
+
+	if res.Tlm != nil {
+		return *res.Tlm, nil
+	}
+
+	return res.abrpTelemetry, nil
+}
+
+func init() {
+	registry.Add("abrp", NewAbrpFromConfig)
+}
+
+// NewAbrpFromConfig creates a new vehicle
+func NewAbrpFromConfig(other map[string]interface{}) (api.Vehicle, error) {
+	cc := struct {
+		embed       `mapstructure:",squash"`
+		mqtt.Config `mapstructure:",squash"`
+		Topic       string
+		URI         string
+		Timeout     time.Duration
+		Cache       time.Duration
+	}{
+		Cache: interval,
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	log := util.NewLogger("abrp")
+
+	var g func() (string, error)
+
+	if cc.URI != "" {
+		g = provider.Cached(provider.NewHTTP(log, "GET", cc.URI, false, 1, 0).StringGetter(), cc.Cache)
+	} else {
+		if cc.Topic == "" {
+			return nil, errors.New("missing topic or uri")
+		}
+
+		client, err := mqtt.RegisteredClientOrDefault(log, cc.Config)
+		if err != nil {
+			return nil, err
+		}
+
+		// staleness is checked using the telemetry timestamp
+		g = provider.NewMqtt(log, client, cc.Topic, 0).StringGetter()
+	}
+
+	v := &Abrp{
+		embed: &cc.embed,
+		dataG: func() (abrpTelemetry, error) {
+			s, err := g()
+			if err != nil {
+				return abrpTelemetry{}, err
+			}
+
+			res, err := parseAbrp(s)
+			if err == nil {
+				err = res.outdated(time.Now(), cc.Timeout)
+			}
+
+			return res, err
+		},
+	}
+
+	return v, nil
+}
+
+// SoC implements the api.Vehicle interface
+func (v *Abrp) SoC() (float64, error) {
+	res, err := v.dataG()
+	if err == nil && res.SoC == nil {
+		err = api.ErrNotAvailable
+	}
+	if err != nil {
+		return 0, err
+	}
+	return *res.SoC, nil
+}
+
+var _ api.ChargeState = (*Abrp)(nil)
+
+// Status implements the api.ChargeState interface
+func (v *Abrp) Status() (api.ChargeStatus, error) {
+	res, err := v.dataG()
+	if err != nil {
+		return api.StatusNone, err
+	}
+
+	return res.status()
+}
+
+var _ api.VehicleRange = (*Abrp)(nil)
+
+// Range implements the api.VehicleRange interface
+func (v *Abrp) Range() (int64, error) {
+	res, err := v.dataG()
+	if err == nil && res.EstBatteryRange == nil {
+		err = api.ErrNotAvailable
+	}
+	if err != nil {
+		return 0, err
+	}
+	return int64(math.Round(*res.EstBatteryRange)), nil
+}
+
+var _ api.VehicleOdometer = (*Abrp)(nil)
+
+// Odometer implements the api.VehicleOdometer interface
+func (v *Abrp) Odometer() (float64, error) {
+	res, err := v.dataG()
+	if err == nil && res.Odometer == nil {
+		err = api.ErrNotAvailable
+	}
+	if err != nil {
+		return 0, err
+	}
+	return *res.Odometer, nil
+}
+
+var _ api.VehiclePosition = (*Abrp)(nil)
+
+// Position implements the api.VehiclePosition interface
+func (v *Abrp) Position() (float64, float64, error) {
+	res, err := v.dataG()
+	if err == nil && (res.Lat == nil || res.Lon == nil) {
+		err = api.ErrNotAvailable
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	return *res.Lat, *res.Lon, nil
+}
@@ -0,0 +1,67 @@
+package vehicle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAbrpParse(t *testing.T) {
+	for _, s := range []string{
+		`{"utc":1666000000,"soc":55.5,"est_battery_range":210,"is_charging":1,"lat":52.5,"lon":13.4}`,
+		`{"tlm":{"utc":1666000000,"soc":55.5,"est_battery_range":210,"is_charging":true,"lat":52.5,"lon":13.4}}`,
+	} {
+		res, err := parseAbrp(s)
+		require.NoError(t, err)
+
+		require.NotNil(t, res.SoC)
+		assert.Equal(t, 55.5, *res.SoC)
+		require.NotNil(t, res.EstBatteryRange)
+		assert.Equal(t, 210.0, *res.EstBatteryRange)
+		assert.True(t, bool(res.IsCharging))
+		assert.Nil(t, res.Odometer)
+	}
+
+	_, err := parseAbrp(`{"is_charging":"maybe"}`)
+	assert.Error(t, err)
+}
+
+func TestAbrpStatus(t *testing.T) {
+	for _, tc := range []struct {
+		json   string
+		status api.ChargeStatus
+		err    error
+	}{
+		{`{"is_charging":1}`, api.StatusC, nil},
+		{`{"is_charging":1,"is_dcfc":1}`, api.StatusA, nil},
+		{`{"is_charging":0,"is_plugged":1}`, api.StatusB, nil},
+		{`{"is_charging":0,"is_plugged":0}`, api.StatusA, nil},
+		{`{"is_charging":0}`, api.StatusNone, api.ErrNotAvailable},
+	} {
+		res, err := parseAbrp(tc.json)
+		require.NoError(t, err)
+
+		status, err := res.status()
+		assert.Equal(t, tc.status, status, tc.json)
+		assert.Equal(t, tc.err, err, tc.json)
+	}
+}
+
+func TestAbrpOutdated(t *testing.T) {
+	res, err := parseAbrp(`{"utc":1666000000,"soc":55.5}`)
+	require.NoError(t, err)
+
+	now := time.Unix(1666000000, 0).Add(10 * time.Minute)
+
+	assert.NoError(t, res.outdated(now, 0))
+	assert.NoError(t, res.outdated(now, 30*time.Minute))
+	assert.Error(t, res.outdated(now, 5*time.Minute))
+
+	// no timestamp
+	res, err = parseAbrp(`{"soc":55.5}`)
+	require.NoError(t, err)
+	assert.NoError(t, res.outdated(now, 5*time.Minute))
+}
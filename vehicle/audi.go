@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/server/db/tokens"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/request"
 	"github.com/evcc-io/evcc/vehicle/audi"
@@ -48,7 +49,7 @@ func NewAudiFromConfig(other map[string]interface{}) (api.Vehicle, error) {
 	log := util.NewLogger("audi").Redact(cc.User, cc.Password, cc.VIN)
 
 	idk := idkproxy.New(log, audi.IDKParams)
-	ts, err := service.MbbTokenSource(log, idk, audi.AuthClientID, audi.AuthParams, cc.User, cc.Password)
+	ts, err := service.MbbTokenSource(log, idk, audi.AuthClientID, audi.AuthParams, cc.User, cc.Password, tokens.NewStore("audi", cc.User))
 	if err != nil {
 		return nil, err
 	}
@@ -4,14 +4,20 @@ import (
 	"time"
 
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/server/db/tokens"
 	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
 	"github.com/evcc-io/evcc/vehicle/bluelink"
 )
 
+// bluelinkBudget is the daily api request limit of the EU bluelink api
+const bluelinkBudget = 200
+
 // Bluelink is an api.Vehicle implementation
 type Bluelink struct {
 	*embed
 	*bluelink.Provider
+	budget *request.Budget
 }
 
 func init() {
@@ -56,10 +62,12 @@ func newBluelinkFromConfig(brand string, other map[string]interface{}, settings
 		Language       string
 		Expiry         time.Duration
 		Cache          time.Duration
+		Budget         int
 	}{
 		Language: "en",
 		Expiry:   expiry,
 		Cache:    interval,
+		Budget:   bluelinkBudget,
 	}
 
 	if err := util.DecodeOther(other, &cc); err != nil {
@@ -69,11 +77,16 @@ func newBluelinkFromConfig(brand string, other map[string]interface{}, settings
 	log := util.NewLogger(brand).Redact(cc.User, cc.Password, cc.VIN)
 	identity := bluelink.NewIdentity(log, settings)
 
-	if err := identity.Login(cc.User, cc.Password, cc.Language); err != nil {
+	// quota applies per account
+	budget := request.NewBudget(brand+":"+cc.User, cc.Budget)
+	identity.Client.Transport = budget.Transport(identity.Client.Transport)
+
+	if err := identity.Login(cc.User, cc.Password, cc.Language, tokens.NewStore(brand, cc.User)); err != nil {
 		return nil, err
 	}
 
 	api := bluelink.NewAPI(log, settings.URI, identity)
+	api.Client.Transport = budget.Transport(api.Client.Transport)
 
 	vehicle, err := ensureVehicleEx(
 		cc.VIN, api.Vehicles,
@@ -89,7 +102,15 @@ func newBluelinkFromConfig(brand string, other map[string]interface{}, settings
 	v := &Bluelink{
 		embed:    &cc.embed,
 		Provider: bluelink.NewProvider(api, vehicle.VehicleID, cc.Expiry, cc.Cache),
+		budget:   budget,
 	}
 
 	return v, nil
 }
+
+var _ request.Budgeter = (*Bluelink)(nil)
+
+// Budget implements the request.Budgeter interface
+func (v *Bluelink) Budget() *request.Budget {
+	return v.budget
+}
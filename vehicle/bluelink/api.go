@@ -16,6 +16,8 @@ const (
 	VehiclesURL     = "vehicles"
 	StatusURL       = "vehicles/%s/status"
 	StatusLatestURL = "vehicles/%s/status/latest"
+	ChargeTargetURL = "vehicles/%s/charge/target"
+	TemperatureURL  = "vehicles/%s/control/temperature" // v2
 )
 
 const (
@@ -92,3 +94,60 @@ func (v *API) StatusPartial(vid string) (StatusResponse, error) {
 
 	return res, err
 }
+
+// ChargeTarget sets the AC and DC target soc
+func (v *API) ChargeTarget(vid string, soc int) error {
+	data := ChargeTargetRequest{
+		TargetSocList: []ChargeTarget{
+			{PlugType: plugTypeDC, TargetSocLevel: soc},
+			{PlugType: plugTypeAC, TargetSocLevel: soc},
+		},
+	}
+
+	uri := fmt.Sprintf("%s/%s", v.baseURI, fmt.Sprintf(ChargeTargetURL, vid))
+	req, err := request.New(http.MethodPost, uri, request.MarshalJSON(data), request.JSONEncoding)
+
+	var res struct {
+		RetCode string
+	}
+	if err == nil {
+		err = v.DoJSON(req, &res)
+	}
+	if err == nil && res.RetCode != resOK {
+		err = fmt.Errorf("unexpected response: %s", res.RetCode)
+	}
+
+	return err
+}
+
+// Temperature starts or stops climatisation at the given temperature
+func (v *API) Temperature(vid string, start bool, temp float64) error {
+	action := "stop"
+	if start {
+		action = "start"
+	}
+
+	data := TemperatureRequest{
+		Action:   action,
+		TempCode: tempCode(temp),
+		Unit:     "C",
+	}
+	data.Options.Defrost = true
+	data.Options.Heating1 = 1
+
+	// climatisation requires the v2 api
+	uri := fmt.Sprintf("%s/%s", strings.Replace(v.baseURI, "/api/v1/", "/api/v2/", 1), fmt.Sprintf(TemperatureURL, vid))
+	req, err := request.New(http.MethodPost, uri, request.MarshalJSON(data), request.JSONEncoding)
+
+	var res struct {
+		RetCode string
+	}
+	if err == nil {
+		err = v.DoJSON(req, &res)
+	}
+	if err == nil && res.RetCode != resOK {
+		err = fmt.Errorf("unexpected response: %s", res.RetCode)
+	}
+
+	return err
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/api/store"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/oauth"
 	"github.com/evcc-io/evcc/util/request"
@@ -44,6 +45,8 @@ type Identity struct {
 	log      *util.Logger
 	config   Config
 	deviceID string
+
+	user, password, language string
 	oauth2.TokenSource
 }
 
@@ -297,6 +300,10 @@ func (v *Identity) exchangeCode(accCode string) (oauth.Token, error) {
 
 // RefreshToken implements oauth.TokenRefresher
 func (v *Identity) RefreshToken(token *oauth2.Token) (*oauth2.Token, error) {
+	if token == nil || token.RefreshToken == "" {
+		return v.login()
+	}
+
 	headers := map[string]string{
 		"Authorization": "Basic " + v.config.BasicToken,
 		"Content-type":  "application/x-www-form-urlencoded",
@@ -319,42 +326,53 @@ func (v *Identity) RefreshToken(token *oauth2.Token) (*oauth2.Token, error) {
 	return (*oauth2.Token)(&res), err
 }
 
-func (v *Identity) Login(user, password, language string) (err error) {
+// Login creates the token source, using the stored token if available
+func (v *Identity) Login(user, password, language string, store store.Store) (err error) {
 	if user == "" || password == "" {
 		return api.ErrMissingCredentials
 	}
 
+	v.user = user
+	v.password = password
+	v.language = language
+
 	v.deviceID, err = v.getDeviceID()
 
-	var cookieClient *request.Helper
 	if err == nil {
-		cookieClient, err = v.getCookies()
+		ts := oauth.PersistentRefreshTokenSource(nil, v, store)
+		if _, err = ts.Token(); err == nil {
+			v.TokenSource = ts
+		}
 	}
 
+	return err
+}
+
+func (v *Identity) login() (*oauth2.Token, error) {
+	cookieClient, err := v.getCookies()
+
 	if err == nil {
-		err = v.setLanguage(cookieClient, language)
+		err = v.setLanguage(cookieClient, v.language)
 	}
 
 	var code string
 	if err == nil {
 		// try new login first, then fallback
-		if code, err = v.brandLogin(cookieClient, user, password); err != nil {
-			code, err = v.bluelinkLogin(cookieClient, user, password)
+		if code, err = v.brandLogin(cookieClient, v.user, v.password); err != nil {
+			code, err = v.bluelinkLogin(cookieClient, v.user, v.password)
 		}
 	}
 
+	var token oauth.Token
 	if err == nil {
-		var token oauth.Token
-		if token, err = v.exchangeCode(code); err == nil {
-			v.TokenSource = oauth.RefreshTokenSource((*oauth2.Token)(&token), v)
-		}
+		token, err = v.exchangeCode(code)
 	}
 
 	if err != nil {
 		err = fmt.Errorf("login failed: %w", err)
 	}
 
-	return err
+	return (*oauth2.Token)(&token), err
 }
 
 // Request decorates requests with authorization headers
@@ -7,7 +7,10 @@ import (
 	"github.com/evcc-io/evcc/provider"
 )
 
-const refreshTimeout = 2 * time.Minute
+const (
+	refreshTimeout = 2 * time.Minute
+	climateTemp    = 21 // °C
+)
 
 // Provider implements the Kia/Hyundai bluelink api.
 // Based on https://github.com/Hacksore/bluelinky.
@@ -15,6 +18,8 @@ type Provider struct {
 	statusG     func() (VehicleStatus, error)
 	statusLG    func() (StatusLatestResponse, error)
 	refreshG    func() (StatusResponse, error)
+	targetS     func(soc int) error
+	climateS    func(start bool) error
 	expiry      time.Duration
 	refreshTime time.Time
 }
@@ -25,6 +30,12 @@ func NewProvider(api *API, vid string, expiry, cache time.Duration) *Provider {
 		refreshG: func() (StatusResponse, error) {
 			return api.StatusPartial(vid)
 		},
+		targetS: func(soc int) error {
+			return api.ChargeTarget(vid, soc)
+		},
+		climateS: func(start bool) error {
+			return api.Temperature(vid, start, climateTemp)
+		},
 		expiry: expiry,
 	}
 
@@ -186,6 +197,25 @@ func (v *Provider) TargetSoC() (float64, error) {
 	return 0, err
 }
 
+var _ api.SocLimitSetter = (*Provider)(nil)
+
+// SetTargetSoC implements the api.SocLimitSetter interface
+func (v *Provider) SetTargetSoC(soc int) error {
+	return v.targetS(soc)
+}
+
+var _ api.VehicleClimateController = (*Provider)(nil)
+
+// StartClimate implements the api.VehicleClimateController interface
+func (v *Provider) StartClimate() error {
+	return v.climateS(true)
+}
+
+// StopClimate implements the api.VehicleClimateController interface
+func (v *Provider) StopClimate() error {
+	return v.climateS(false)
+}
+
 var _ api.VehiclePosition = (*Provider)(nil)
 
 // Position implements the api.VehiclePosition interface
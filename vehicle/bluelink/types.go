@@ -1,6 +1,10 @@
 package bluelink
 
-import "time"
+import (
+	"fmt"
+	"math"
+	"time"
+)
 
 type VehiclesResponse struct {
 	RetCode string
@@ -60,6 +64,7 @@ const (
 	timeFormat = "20060102150405 -0700" // Note: must add timeOffset
 	timeOffset = " +0100"
 
+	plugTypeDC = 0
 	plugTypeAC = 1
 )
 
@@ -83,3 +88,29 @@ type TargetSoc struct {
 	TargetSocLevel int
 	PlugType       int
 }
+
+type ChargeTargetRequest struct {
+	TargetSocList []ChargeTarget `json:"targetSOClist"`
+}
+
+type ChargeTarget struct {
+	PlugType       int `json:"plugType"`
+	TargetSocLevel int `json:"targetSOClevel"`
+}
+
+type TemperatureRequest struct {
+	Action   string `json:"action"`
+	HvacType int    `json:"hvacType"`
+	Options  struct {
+		Defrost  bool `json:"defrost"`
+		Heating1 int  `json:"heating1"`
+	} `json:"options"`
+	TempCode string `json:"tempCode"`
+	Unit     string `json:"unit"`
+}
+
+// tempCode converts the temperature into the api's hex code of 0.5°C steps starting at 14°C
+func tempCode(temp float64) string {
+	idx := int(math.Round((math.Min(math.Max(temp, 14), 29.5) - 14) * 2))
+	return fmt.Sprintf("%02XH", idx)
+}
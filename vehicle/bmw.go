@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/server/db/tokens"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/vehicle/bmw"
 )
@@ -54,7 +55,7 @@ func NewBMWMiniFromConfig(brand string, other map[string]interface{}) (api.Vehic
 	log := util.NewLogger(brand).Redact(cc.User, cc.Password, cc.VIN)
 	identity := bmw.NewIdentity(log)
 
-	err := identity.Login(cc.User, cc.Password)
+	err := identity.Login(cc.User, cc.Password, tokens.NewStore("bmw", cc.User))
 	if err != nil {
 		return nil, err
 	}
@@ -86,3 +86,25 @@ func (v *API) Status(vin string) (VehicleStatus, error) {
 
 	return VehicleStatus{}, err
 }
+
+// ChargingSettings sets the vehicle's charging target soc
+func (v *API) ChargingSettings(vin string, targetSoC int) error {
+	uri := fmt.Sprintf("%s/eadrax-crccs/v1/vehicles/%s/charging-settings", CocoApiURI, vin)
+
+	data := struct {
+		ChargingTarget int `json:"chargingTarget"`
+	}{
+		ChargingTarget: targetSoC,
+	}
+
+	req, err := request.New(http.MethodPost, uri, request.MarshalJSON(data), map[string]string{
+		"Content-Type": request.JSONContent,
+		"X-User-Agent": v.xUserAgent,
+	})
+	if err == nil {
+		var res any
+		err = v.DoJSON(req, &res)
+	}
+
+	return err
+}
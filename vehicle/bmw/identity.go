@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/evcc-io/evcc/api/store"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/oauth"
 	"github.com/evcc-io/evcc/util/request"
@@ -37,14 +38,16 @@ func NewIdentity(log *util.Logger) *Identity {
 	return v
 }
 
-func (v *Identity) Login(user, password string) error {
+// Login creates the token source, using the stored token if available
+func (v *Identity) Login(user, password string, store store.Store) error {
 	v.user = user
 	v.password = password
 
-	token, err := v.RefreshToken(nil)
+	ts := oauth.PersistentRefreshTokenSource(nil, v, store)
 
+	_, err := ts.Token()
 	if err == nil {
-		v.TokenSource = oauth.RefreshTokenSource(token, v)
+		v.TokenSource = ts
 	}
 
 	return err
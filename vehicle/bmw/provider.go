@@ -11,7 +11,8 @@ const kmPerMile = 1.609344
 
 // Provider implements the evcc vehicle api
 type Provider struct {
-	statusG func() (VehicleStatus, error)
+	statusG  func() (VehicleStatus, error)
+	settings func(targetSoC int) error
 }
 
 // NewProvider provides the evcc vehicle api provider
@@ -20,6 +21,9 @@ func NewProvider(api *API, vin string, cache time.Duration) *Provider {
 		statusG: provider.Cached(func() (VehicleStatus, error) {
 			return api.Status(vin)
 		}, cache),
+		settings: func(targetSoC int) error {
+			return api.ChargingSettings(vin, targetSoC)
+		},
 	}
 	return impl
 }
@@ -109,3 +113,10 @@ func (v *Provider) Odometer() (float64, error) {
 
 	return 0, err
 }
+
+var _ api.SocLimitSetter = (*Provider)(nil)
+
+// SetTargetSoC implements the api.SocLimitSetter interface
+func (v *Provider) SetTargetSoC(soc int) error {
+	return v.settings(soc)
+}
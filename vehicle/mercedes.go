@@ -6,7 +6,9 @@ import (
 	"time"
 
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/server/db/tokens"
 	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
 	"github.com/evcc-io/evcc/vehicle/mercedes"
 )
 
@@ -15,6 +17,7 @@ type Mercedes struct {
 	*embed
 	api.AuthProvider
 	*mercedes.Provider
+	budget *request.Budget
 }
 
 func init() {
@@ -29,6 +32,7 @@ func NewMercedesFromConfig(other map[string]interface{}) (api.Vehicle, error) {
 		VIN                    string
 		Sandbox                bool
 		Cache                  time.Duration
+		Budget                 int
 	}{
 		Cache: interval,
 	}
@@ -45,17 +49,10 @@ func NewMercedesFromConfig(other map[string]interface{}) (api.Vehicle, error) {
 		return nil, errors.New("missing vin")
 	}
 
-	var options []mercedes.IdentityOption
-
-	// TODO Load tokens from a persistence storage and use those during startup
-	// e.g. persistence.Load("key")
-	// if tokens != nil {
-	// 	options = append(options, mercedes.WithToken(&oauth2.Token{
-	// 		AccessToken:  tokens.Access,
-	// 		RefreshToken: tokens.Refresh,
-	// 		Expiry:       tokens.Expiry,
-	// 	}))
-	// }
+	// load and save tokens from token store, the login account is identified by the client id like the user for other vehicles
+	options := []mercedes.IdentityOption{
+		mercedes.WithStore(tokens.NewStore("mercedes", cc.ClientID)),
+	}
 
 	log := util.NewLogger("mercedes")
 
@@ -71,7 +68,15 @@ func NewMercedesFromConfig(other map[string]interface{}) (api.Vehicle, error) {
 		embed:        &cc.embed,
 		Provider:     mercedes.NewProvider(api, strings.ToUpper(cc.VIN), cc.Cache),
 		AuthProvider: identity, // expose the OAuth2 login
+		budget:       request.NewBudget("mercedes", cc.Budget, "api.mercedes-benz.com"),
 	}
 
 	return v, nil
 }
+
+var _ request.Budgeter = (*Mercedes)(nil)
+
+// Budget implements the request.Budgeter interface
+func (v *Mercedes) Budget() *request.Budget {
+	return v.budget
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/coreos/go-oidc/v3/oidc"
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/api/store"
 	"github.com/evcc-io/evcc/provider"
 	"github.com/evcc-io/evcc/server/auth"
 	"github.com/evcc-io/evcc/util"
@@ -28,6 +29,21 @@ func WithToken(t *oauth2.Token) IdentityOption {
 	}
 }
 
+// WithStore loads the stored token if available and saves obtained tokens to the store.
+func WithStore(store store.Store) IdentityOption {
+	return func(v *Identity) error {
+		v.ReuseTokenSource.store = store
+
+		var token oauth2.Token
+		if err := store.Load(&token); err == nil && token.RefreshToken != "" {
+			v.ReuseTokenSource.saved = token.AccessToken
+			v.ReuseTokenSource.Apply(&token)
+		}
+
+		return nil
+	}
+}
+
 type Identity struct {
 	log *util.Logger
 	*ReuseTokenSource
@@ -4,14 +4,17 @@ import (
 	"context"
 	"sync"
 
+	"github.com/evcc-io/evcc/api/store"
 	"golang.org/x/oauth2"
 )
 
 type ReuseTokenSource struct {
-	mu sync.Mutex
-	oc *oauth2.Config
-	ts oauth2.TokenSource
-	cb func()
+	mu    sync.Mutex
+	oc    *oauth2.Config
+	ts    oauth2.TokenSource
+	cb    func()
+	store store.Store
+	saved string // last saved access token
 }
 
 func (ts *ReuseTokenSource) Token() (*oauth2.Token, error) {
@@ -22,6 +25,8 @@ func (ts *ReuseTokenSource) Token() (*oauth2.Token, error) {
 	if err != nil || !t.Valid() {
 		// invalid token callback
 		ts.cb()
+	} else {
+		ts.save(t)
 	}
 
 	return t, err
@@ -30,5 +35,23 @@ func (ts *ReuseTokenSource) Token() (*oauth2.Token, error) {
 func (ts *ReuseTokenSource) Apply(t *oauth2.Token) {
 	ts.mu.Lock()
 	ts.ts = ts.oc.TokenSource(context.Background(), t)
+	ts.save(t)
 	ts.mu.Unlock()
 }
+
+// save persists refreshed tokens, persisting is optional
+func (ts *ReuseTokenSource) save(t *oauth2.Token) {
+	if ts.store == nil || (t != nil && t.AccessToken == ts.saved) {
+		return
+	}
+
+	if t == nil {
+		// logout
+		ts.saved = ""
+		_ = ts.store.Save(new(oauth2.Token))
+		return
+	}
+
+	ts.saved = t.AccessToken
+	_ = ts.store.Save(t)
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/server/db/tokens"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/request"
 	"github.com/evcc-io/evcc/vehicle/skoda"
@@ -48,7 +49,7 @@ func NewSkodaFromConfig(other map[string]interface{}) (api.Vehicle, error) {
 	log := util.NewLogger("skoda").Redact(cc.User, cc.Password, cc.VIN)
 
 	trs := tokenrefreshservice.New(log, skoda.TRSParams)
-	ts, err := service.MbbTokenSource(log, trs, skoda.AuthClientID, skoda.AuthParams, cc.User, cc.Password)
+	ts, err := service.MbbTokenSource(log, trs, skoda.AuthClientID, skoda.AuthParams, cc.User, cc.Password, tokens.NewStore("skoda", cc.User))
 	if err != nil {
 		return nil, err
 	}
@@ -68,6 +68,10 @@ const (
 	ActionCharge      = "charging"
 	ActionChargeStart = "Start"
 	ActionChargeStop  = "Stop"
+
+	ActionClimatisation      = "air-conditioning"
+	ActionClimatisationStart = "Start"
+	ActionClimatisationStop  = "Stop"
 )
 
 // Action executes a vehicle action
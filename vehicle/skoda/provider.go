@@ -137,6 +137,18 @@ func (v *Provider) TargetSoC() (float64, error) {
 	return 0, err
 }
 
+var _ api.VehicleClimateController = (*Provider)(nil)
+
+// StartClimate implements the api.VehicleClimateController interface
+func (v *Provider) StartClimate() error {
+	return v.action(ActionClimatisation, ActionClimatisationStart)
+}
+
+// StopClimate implements the api.VehicleClimateController interface
+func (v *Provider) StopClimate() error {
+	return v.action(ActionClimatisation, ActionClimatisationStop)
+}
+
 var _ api.VehicleChargeController = (*Provider)(nil)
 
 // StartCharge implements the api.VehicleChargeController interface
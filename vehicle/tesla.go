@@ -198,6 +198,52 @@ func (v *Tesla) TargetSoC() (float64, error) {
 	return 0, err
 }
 
+var _ api.SocLimitSetter = (*Tesla)(nil)
+
+// SetTargetSoC implements the api.SocLimitSetter interface
+func (v *Tesla) SetTargetSoC(soc int) error {
+	err := v.vehicle.SetChargeLimit(soc)
+
+	// wake up sleeping vehicle and retry
+	if err != nil && err.Error() == "408 Request Timeout" {
+		if _, err := v.vehicle.Wakeup(); err != nil {
+			return err
+		}
+		err = api.ErrMustRetry
+	}
+
+	return err
+}
+
+var _ api.VehicleClimateController = (*Tesla)(nil)
+
+// StartClimate implements the api.VehicleClimateController interface
+func (v *Tesla) StartClimate() error {
+	err := v.vehicle.StartAirConditioning()
+
+	// wake up sleeping vehicle and retry
+	if err != nil && err.Error() == "408 Request Timeout" {
+		if _, err := v.vehicle.Wakeup(); err != nil {
+			return err
+		}
+		err = api.ErrMustRetry
+	}
+
+	return err
+}
+
+// StopClimate implements the api.VehicleClimateController interface
+func (v *Tesla) StopClimate() error {
+	err := v.vehicle.StopAirConditioning()
+
+	// ignore sleeping vehicle
+	if err != nil && err.Error() == "408 Request Timeout" {
+		err = nil
+	}
+
+	return err
+}
+
 var _ api.VehicleChargeController = (*Tesla)(nil)
 
 // StartCharge implements the api.VehicleChargeController interface
@@ -0,0 +1,144 @@
+package vehicle
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/provider"
+	"github.com/evcc-io/evcc/provider/mqtt"
+	"github.com/evcc-io/evcc/util"
+)
+
+// TeslaMate is an api.Vehicle implementation using the TeslaMate mqtt integration
+// https://docs.teslamate.org/docs/integrations/mqtt
+type TeslaMate struct {
+	*embed
+	socG, rangeG, odometerG func() (float64, error)
+	latG, lonG              func() (float64, error)
+	limitG, fullG           func() (float64, error)
+	stateG                  func() (string, error)
+}
+
+func init() {
+	registry.Add("teslamate", NewTeslaMateFromConfig)
+}
+
+// NewTeslaMateFromConfig creates a new vehicle
+func NewTeslaMateFromConfig(other map[string]interface{}) (api.Vehicle, error) {
+	cc := struct {
+		embed       `mapstructure:",squash"`
+		mqtt.Config `mapstructure:",squash"`
+		Topic       string
+		ID          int
+		Timeout     time.Duration
+	}{
+		Topic: "teslamate",
+		ID:    1,
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	log := util.NewLogger("teslamate")
+
+	client, err := mqtt.RegisteredClientOrDefault(log, cc.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	topic := func(name string) string {
+		return fmt.Sprintf("%s/cars/%d/%s", cc.Topic, cc.ID, name)
+	}
+
+	floatG := func(name string) func() (float64, error) {
+		return provider.NewMqtt(log, client, topic(name), cc.Timeout).FloatGetter()
+	}
+
+	v := &TeslaMate{
+		embed:     &cc.embed,
+		socG:      floatG("battery_level"),
+		rangeG:    floatG("est_battery_range_km"),
+		odometerG: floatG("odometer"),
+		latG:      floatG("latitude"),
+		lonG:      floatG("longitude"),
+		limitG:    floatG("charge_limit_soc"),
+		fullG:     floatG("time_to_full_charge"),
+		stateG:    provider.NewMqtt(log, client, topic("charging_state"), cc.Timeout).StringGetter(),
+	}
+
+	return v, nil
+}
+
+// SoC implements the api.Vehicle interface
+func (v *TeslaMate) SoC() (float64, error) {
+	return v.socG()
+}
+
+var _ api.ChargeState = (*TeslaMate)(nil)
+
+// Status implements the api.ChargeState interface
+func (v *TeslaMate) Status() (api.ChargeStatus, error) {
+	state, err := v.stateG()
+	if err != nil {
+		return api.StatusNone, err
+	}
+
+	switch state {
+	case "Charging", "Starting":
+		return api.StatusC, nil
+	case "Stopped", "Complete", "NoPower":
+		return api.StatusB, nil
+	default: // Disconnected
+		return api.StatusA, nil
+	}
+}
+
+var _ api.VehicleRange = (*TeslaMate)(nil)
+
+// Range implements the api.VehicleRange interface
+func (v *TeslaMate) Range() (int64, error) {
+	res, err := v.rangeG()
+	return int64(math.Round(res)), err
+}
+
+var _ api.VehicleOdometer = (*TeslaMate)(nil)
+
+// Odometer implements the api.VehicleOdometer interface
+func (v *TeslaMate) Odometer() (float64, error) {
+	return v.odometerG()
+}
+
+var _ api.VehiclePosition = (*TeslaMate)(nil)
+
+// Position implements the api.VehiclePosition interface
+func (v *TeslaMate) Position() (float64, float64, error) {
+	lat, err := v.latG()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	lon, err := v.lonG()
+	return lat, lon, err
+}
+
+var _ api.SocLimiter = (*TeslaMate)(nil)
+
+// TargetSoC implements the api.SocLimiter interface
+func (v *TeslaMate) TargetSoC() (float64, error) {
+	return v.limitG()
+}
+
+var _ api.VehicleFinishTimer = (*TeslaMate)(nil)
+
+// FinishTime implements the api.VehicleFinishTimer interface
+func (v *TeslaMate) FinishTime() (time.Time, error) {
+	res, err := v.fullG()
+	if err == nil && res <= 0 {
+		err = api.ErrNotAvailable
+	}
+
+	return time.Now().Add(time.Duration(res * float64(time.Hour))), err
+}
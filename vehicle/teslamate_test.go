@@ -0,0 +1,36 @@
+package vehicle
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/util/templates"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestTeslaMateTemplate(t *testing.T) {
+	tmpl, err := templates.ByName(templates.Vehicle, "teslamate")
+	require.NoError(t, err)
+
+	render := func(values map[string]interface{}) map[string]interface{} {
+		b, _, err := tmpl.RenderResult(templates.TemplateRenderModeInstance, values)
+		require.NoError(t, err)
+
+		var res map[string]interface{}
+		require.NoError(t, yaml.Unmarshal(b, &res))
+		return res
+	}
+
+	// values are published on change only, no timeout by default
+	res := render(map[string]interface{}{"host": "broker"})
+	assert.Equal(t, "teslamate", res["type"])
+	assert.Equal(t, "broker:1883", res["broker"])
+	assert.Equal(t, "teslamate", res["topic"])
+	assert.Equal(t, 1, res["id"])
+	assert.NotContains(t, res, "timeout")
+
+	res = render(map[string]interface{}{"host": "broker", "id": 2, "timeout": "1h"})
+	assert.Equal(t, 2, res["id"])
+	assert.Equal(t, "1h", res["timeout"])
+}
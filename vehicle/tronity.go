@@ -25,6 +25,7 @@ import (
 
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/provider"
+	"github.com/evcc-io/evcc/server/db/tokens"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/oauth"
 	"github.com/evcc-io/evcc/util/request"
@@ -93,8 +94,8 @@ func NewTronityFromConfig(other map[string]interface{}) (api.Vehicle, error) {
 
 	// https://app.platform.tronity.io/docs#tag/Authentication
 	if err := cc.Tokens.Error(); err != nil {
-		// use app flow if we don't have tokens
-		ts = oauth.RefreshTokenSource(&oauth2.Token{}, v)
+		// use app flow if we don't have tokens, reusing the stored token if still valid
+		ts = oauth.PersistentRefreshTokenSource(nil, v, tokens.NewStore("tronity", cc.Credentials.ID))
 	} else {
 		// use provided tokens generated by code flow
 		ctx := context.WithValue(context.Background(), oauth2.HTTPClient, request.NewClient(log))
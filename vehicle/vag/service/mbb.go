@@ -3,6 +3,7 @@ package service
 import (
 	"net/url"
 
+	"github.com/evcc-io/evcc/api/store"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/vehicle/vag"
 	"github.com/evcc-io/evcc/vehicle/vag/mbb"
@@ -10,19 +11,21 @@ import (
 )
 
 // MbbTokenSource creates a refreshing token source for use with the MBB api.
-// Once the MBB token expires, it is recreated from the token exchanger (either TokenRefreshService or IDK)
-func MbbTokenSource(log *util.Logger, tox vag.TokenExchanger, clientID string, q url.Values, user, password string) (vag.TokenSource, error) {
-	q, err := vwidentity.Login(log, q, user, password)
-	if err != nil {
-		return nil, err
-	}
+// Once the MBB token expires, it is recreated from the token exchanger (either TokenRefreshService or IDK).
+// The token exchanger's token is loaded from and saved to the store.
+func MbbTokenSource(log *util.Logger, tox vag.TokenExchanger, clientID string, q url.Values, user, password string, store store.Store) (vag.TokenSource, error) {
+	trs, err := storedTokenSource(tox, func() (*vag.Token, error) {
+		q, err := vwidentity.Login(log, q, user, password)
+		if err != nil {
+			return nil, err
+		}
 
-	token, err := tox.Exchange(q)
+		return tox.Exchange(q)
+	}, store)
 	if err != nil {
 		return nil, err
 	}
 
-	trs := tox.TokenSource(token)
 	mbb := mbb.New(log, clientID)
 
 	mts := vag.MetaTokenSource(func() (*vag.Token, error) {
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"github.com/evcc-io/evcc/api/store"
+	"github.com/evcc-io/evcc/vehicle/vag"
+)
+
+// storedTokenSource creates a refreshing token source from the stored token if it can still be refreshed.
+// Otherwise the token exchanger's token is obtained from login. Refreshed tokens are saved to the store.
+func storedTokenSource(tox vag.TokenExchanger, login func() (*vag.Token, error), store store.Store) (vag.TokenSource, error) {
+	var token vag.Token
+	if err := store.Load(&token); err == nil && token.RefreshToken != "" {
+		ts := vag.PersistentTokenSource(tox.TokenSource(&token), store)
+		if _, err := ts.TokenEx(); err == nil {
+			return ts, nil
+		}
+	}
+
+	tok, err := login()
+	if err != nil {
+		return nil, err
+	}
+
+	return vag.PersistentTokenSource(tox.TokenSource(tok), store), nil
+}
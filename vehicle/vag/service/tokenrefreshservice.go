@@ -3,23 +3,25 @@ package service
 import (
 	"net/url"
 
+	"github.com/evcc-io/evcc/api/store"
 	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/vehicle/vag"
 	"github.com/evcc-io/evcc/vehicle/vag/tokenrefreshservice"
 	"github.com/evcc-io/evcc/vehicle/vag/vwidentity"
 	"golang.org/x/oauth2"
 )
 
-func TokenRefreshServiceTokenSource(log *util.Logger, data, q url.Values, user, password string) (oauth2.TokenSource, error) {
-	q, err := vwidentity.Login(log, q, user, password)
-	if err != nil {
-		return nil, err
-	}
-
+// TokenRefreshServiceTokenSource creates a refreshing TokenRefreshService token source.
+// The token is loaded from and saved to the store.
+func TokenRefreshServiceTokenSource(log *util.Logger, data, q url.Values, user, password string, store store.Store) (oauth2.TokenSource, error) {
 	trs := tokenrefreshservice.New(log, data)
-	token, err := trs.Exchange(q)
-	if err != nil {
-		return nil, err
-	}
 
-	return trs.TokenSource(token), nil
+	return storedTokenSource(trs, func() (*vag.Token, error) {
+		q, err := vwidentity.Login(log, q, user, password)
+		if err != nil {
+			return nil, err
+		}
+
+		return trs.Exchange(q)
+	}, store)
 }
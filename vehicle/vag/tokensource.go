@@ -5,6 +5,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/evcc-io/evcc/api/store"
 	"github.com/imdario/mergo"
 	"golang.org/x/oauth2"
 )
@@ -126,3 +127,43 @@ func (ts *metaTokenSource) TokenEx() (*Token, error) {
 
 	return token, err
 }
+
+type persistentTokenSource struct {
+	mu    sync.Mutex
+	ts    TokenSource
+	store store.Store
+	saved string // last saved access token
+}
+
+// PersistentTokenSource creates a token source that saves refreshed tokens to the store
+func PersistentTokenSource(ts TokenSource, store store.Store) *persistentTokenSource {
+	return &persistentTokenSource{ts: ts, store: store}
+}
+
+// Token returns an oauth2 token or an error
+func (ts *persistentTokenSource) Token() (*oauth2.Token, error) {
+	token, err := ts.TokenEx()
+	if err != nil {
+		return nil, err
+	}
+
+	return &token.Token, err
+}
+
+// TokenEx returns a vag token or an error
+func (ts *persistentTokenSource) TokenEx() (*Token, error) {
+	token, err := ts.ts.TokenEx()
+
+	if err == nil {
+		ts.mu.Lock()
+		if token.AccessToken != ts.saved {
+			// persisting is optional
+			if ts.store.Save(token) == nil {
+				ts.saved = token.AccessToken
+			}
+		}
+		ts.mu.Unlock()
+	}
+
+	return token, err
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/server/db/tokens"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/request"
 	"github.com/evcc-io/evcc/vehicle/vag/service"
@@ -47,7 +48,7 @@ func NewVWFromConfig(other map[string]interface{}) (api.Vehicle, error) {
 	log := util.NewLogger("vw").Redact(cc.User, cc.Password, cc.VIN)
 
 	trs := tokenrefreshservice.New(log, vw.TRSParams)
-	ts, err := service.MbbTokenSource(log, trs, vw.AuthClientID, vw.AuthParams, cc.User, cc.Password)
+	ts, err := service.MbbTokenSource(log, trs, vw.AuthClientID, vw.AuthParams, cc.User, cc.Password, tokens.NewStore("vw", cc.User))
 	if err != nil {
 		return nil, err
 	}
@@ -184,6 +184,10 @@ const (
 	ActionCharge      = "batterycharge"
 	ActionChargeStart = "start"
 	ActionChargeStop  = "stop"
+
+	ActionClimatisation      = "climatisation"
+	ActionClimatisationStart = "startClimatisation"
+	ActionClimatisationStop  = "stopClimatisation"
 )
 
 type actionDefinition struct {
@@ -196,6 +200,10 @@ var actionDefinitions = map[string]actionDefinition{
 		"application/vnd.vwg.mbb.ChargerAction_v1_0_0+xml",
 		"charger/actions",
 	},
+	ActionClimatisation: {
+		"application/vnd.vwg.mbb.ClimaterAction_v1_0_0+xml",
+		"climater/actions",
+	},
 }
 
 // Action implements vehicle actions
@@ -101,6 +101,26 @@ func (v *API) Action(vin, action, value string) error {
 	return err
 }
 
+// ChargeSettings sets the vehicle's target soc
+func (v *API) ChargeSettings(vin string, targetSoC int) error {
+	uri := fmt.Sprintf("%s/vehicles/%s/%s/%s", BaseURL, vin, ActionCharge, ActionChargeSettings)
+
+	data := struct {
+		TargetSOCPct int `json:"targetSOC_pct"`
+	}{
+		TargetSOCPct: targetSoC,
+	}
+
+	req, err := request.New(http.MethodPut, uri, request.MarshalJSON(data), request.JSONEncoding)
+
+	if err == nil {
+		var res interface{}
+		err = v.DoJSON(req, &res)
+	}
+
+	return err
+}
+
 // Any implements any api response
 func (v *API) Any(uri, vin string) (interface{}, error) {
 	if strings.Contains(uri, "%s") {
@@ -11,8 +11,9 @@ import (
 
 // Provider is an api.Vehicle implementation for VW ID cars
 type Provider struct {
-	statusG func() (Status, error)
-	action  func(action, value string) error
+	statusG  func() (Status, error)
+	action   func(action, value string) error
+	settings func(targetSoC int) error
 }
 
 // NewProvider creates a new vehicle
@@ -24,6 +25,9 @@ func NewProvider(api *API, vin string, cache time.Duration) *Provider {
 		action: func(action, value string) error {
 			return api.Action(vin, action, value)
 		},
+		settings: func(targetSoC int) error {
+			return api.ChargeSettings(vin, targetSoC)
+		},
 	}
 	return impl
 }
@@ -163,6 +167,25 @@ func (v *Provider) TargetSoC() (float64, error) {
 	return 0, err
 }
 
+var _ api.SocLimitSetter = (*Provider)(nil)
+
+// SetTargetSoC implements the api.SocLimitSetter interface
+func (v *Provider) SetTargetSoC(soc int) error {
+	return v.settings(soc)
+}
+
+var _ api.VehicleClimateController = (*Provider)(nil)
+
+// StartClimate implements the api.VehicleClimateController interface
+func (v *Provider) StartClimate() error {
+	return v.action(ActionClimatisation, ActionClimatisationStart)
+}
+
+// StopClimate implements the api.VehicleClimateController interface
+func (v *Provider) StopClimate() error {
+	return v.action(ActionClimatisation, ActionClimatisationStop)
+}
+
 var _ api.VehicleChargeController = (*Provider)(nil)
 
 // StartCharge implements the api.VehicleChargeController interface